@@ -8,7 +8,9 @@ import (
 
 	"notes-api/internal/app"
 	"notes-api/internal/config"
+	"notes-api/internal/gql"
 	"notes-api/internal/repository"
+	"notes-api/internal/service"
 )
 
 func main() {
@@ -31,13 +33,46 @@ func main() {
 	if cfg.Repository.Type == "json" {
 		log.Printf("Storage file: %s", cfg.Repository.File)
 	}
+	if cfg.Trash.Retention > 0 {
+		log.Printf("Trash retention: %s", cfg.Trash.Retention)
+	}
+
+	if cfg.Development() {
+		log.Printf("Development mode: GraphiQL at /api/graphql")
+	}
 
 	// Создаем приложение с внедренной зависимостью
-	application := app.New(repo)
+	application := app.New(repo, app.Config{
+		TrashRetention: cfg.Trash.Retention,
+		RequireIfMatch: cfg.Concurrency.RequireIfMatch,
+		IdempotencyTTL: cfg.Idempotency.TTL,
+		V1Deprecation:  cfg.API.V1Deprecation,
+		V1Sunset:       cfg.API.V1Sunset,
+		ImportLimit:    cfg.ImportMaxMB << 20,
+		GraphQL:        gql.Limits{MaxDepth: cfg.GraphQL.MaxDepth, MaxComplexity: cfg.GraphQL.MaxComplexity},
+		GraphiQL:       cfg.Development(),
+		EventLogSize:   cfg.Events.LogSize,
+		Webhooks: service.WebhookConfig{
+			MaxAttempts:   cfg.Webhooks.MaxAttempts,
+			RetryDelay:    cfg.Webhooks.RetryDelay,
+			MaxRetryDelay: cfg.Webhooks.MaxRetryDelay,
+			Timeout:       cfg.Webhooks.Timeout,
+			DisableAfter:  cfg.Webhooks.DisableAfter,
+			AllowPrivate:  cfg.Webhooks.AllowPrivate,
+		},
+	})
 
 	// Настраиваем graceful shutdown
 	setupGracefulShutdown(application)
 
+	// gRPC сервер работает на отдельном порту с теми же сервисами
+	go func() {
+		log.Printf("gRPC server starting on :%s", cfg.GRPCPort)
+		if err := application.RunGRPC(":" + cfg.GRPCPort); err != nil {
+			log.Fatalf("gRPC server failed: %v", err)
+		}
+	}()
+
 	// Запускаем приложение
 	log.Printf("Server starting on :%s", cfg.Port)
 	if err := application.Run(":" + cfg.Port); err != nil {
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// cacheEntry закэшированный ответ на GET запрос
+type cacheEntry struct {
+	ETag         string `json:"etag"`
+	LastModified string `json:"last_modified"`
+	Body         []byte `json:"body"`
+}
+
+// noCache отключает кэш ответов (флаг --no-cache)
+var noCache bool
+
+// cachedGet выполняет условный GET: отправляет валидаторы закэшированного ответа
+// и при 304 Not Modified подставляет тело из кэша с кодом 200.
+// Ответы 200 с ETag или Last-Modified сохраняются в кэш.
+func cachedGet(url string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	path := cachePath(url)
+	entry := readCacheEntry(path)
+	if entry != nil {
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && entry != nil:
+		resp.Body.Close()
+		resp.StatusCode = http.StatusOK
+		resp.Body = io.NopCloser(bytes.NewReader(entry.Body))
+	case resp.StatusCode == http.StatusOK && path != "":
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		etag, modified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+		if etag != "" || modified != "" {
+			writeCacheEntry(path, cacheEntry{ETag: etag, LastModified: modified, Body: body})
+		}
+	}
+
+	return resp, nil
+}
+
+// cachePath возвращает путь к файлу кэша для URL; пустая строка - кэш недоступен
+func cachePath(url string) string {
+	if noCache {
+		return ""
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(dir, "notes-cli", hex.EncodeToString(sum[:])+".json")
+}
+
+// readCacheEntry читает запись кэша; при любой ошибке возвращает nil
+func readCacheEntry(path string) *cacheEntry {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil
+	}
+	return &entry
+}
+
+// writeCacheEntry сохраняет запись кэша; ошибки записи не мешают работе клиента
+func writeCacheEntry(path string, entry cacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0o644)
+}
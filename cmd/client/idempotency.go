@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+const (
+	// createAttempts - сколько раз create отправляет запрос при сетевых ошибках
+	createAttempts = 3
+	// requestTimeout - таймаут одного запроса
+	requestTimeout = 10 * time.Second
+)
+
+// newIdempotencyKey создает случайный ключ идемпотентности
+func newIdempotencyKey() string {
+	key := make([]byte, 16)
+	_, _ = rand.Read(key)
+	return hex.EncodeToString(key)
+}
+
+// postIdempotent отправляет POST с заголовком Idempotency-Key и повторяет его при сетевых ошибках.
+// Благодаря ключу повтор после таймаута не создает дубликат, а возвращает исходный ответ.
+func postIdempotent(url string, data []byte, key string) (*http.Response, error) {
+	client := &http.Client{Timeout: requestTimeout}
+
+	var err error
+	for attempt := 1; attempt <= createAttempts; attempt++ {
+		var req *http.Request
+		req, err = http.NewRequest("POST", url, bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Idempotency-Key", key)
+
+		var resp *http.Response
+		resp, err = client.Do(req)
+		if err == nil {
+			return resp, nil
+		}
+		if attempt < createAttempts {
+			time.Sleep(time.Duration(attempt) * 500 * time.Millisecond)
+		}
+	}
+
+	return nil, err
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"notes-api/internal/domain"
+
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import [file or folder]",
+	Short: "Import notes from Markdown, Obsidian, Google Keep or Evernote",
+	Long: `Import notes into the service. Supported sources:
+  markdown-zip  ZIP of Markdown files or a folder of them (YAML front matter is honored)
+  obsidian      Obsidian vault folder or its ZIP
+  keep          Google Keep from Google Takeout (ZIP or a single note JSON)
+  enex          Evernote export (.enex)
+Folders are zipped before upload. The format is detected from the content unless --format is given.
+Notes with the same title and content as an existing note are reported as duplicates and skipped.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		importFormat, _ := cmd.Flags().GetString("format")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		data, err := readImportSource(args[0])
+		if err != nil {
+			fmt.Printf("Error reading %s: %v\n", args[0], err)
+			os.Exit(1)
+		}
+
+		params := url.Values{}
+		if importFormat != "" {
+			params.Set("format", importFormat)
+		}
+		if dryRun {
+			params.Set("dry_run", "true")
+		}
+		importURL := strings.TrimSuffix(baseURL, "/notes") + "/import"
+		if len(params) > 0 {
+			importURL = appendParams(importURL, params)
+		}
+
+		resp, err := http.Post(importURL, "application/octet-stream", bytes.NewReader(data))
+		if err != nil {
+			fmt.Printf("Error sending request: %v\n", err)
+			os.Exit(1)
+		}
+		defer resp.Body.Close()
+
+		handleResponse(resp, func(body []byte) {
+			var report domain.ImportReport
+			if err := json.Unmarshal(body, &report); err != nil {
+				fmt.Printf("Error parsing response: %v\n", err)
+				os.Exit(1)
+			}
+			printImportReport(report)
+			if report.Failed > 0 {
+				os.Exit(1)
+			}
+		}, http.StatusOK)
+	},
+}
+
+// readImportSource читает файл импорта; каталог упаковывается в ZIP архив
+func readImportSource(path string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return os.ReadFile(path)
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	err = filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return err
+		}
+		name, err := filepath.Rel(path, file)
+		if err != nil {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		// Дата изменения файла нужна серверу, если в заметке нет своих дат
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(name)
+		header.Method = zip.Deflate
+		writer, err := archive.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+
+		source, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer source.Close()
+		_, err = io.Copy(writer, source)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// printImportReport печатает результат по каждому файлу и итог
+func printImportReport(report domain.ImportReport) {
+	for _, file := range report.Files {
+		line := fmt.Sprintf("%-10s %s", file.Status, file.File)
+		switch {
+		case file.NoteID != 0:
+			line += fmt.Sprintf(" -> [%d] %s", file.NoteID, file.Title)
+		case file.DuplicateOf != 0:
+			line += fmt.Sprintf(" (same as note [%d])", file.DuplicateOf)
+		case file.DuplicateFile != "":
+			line += fmt.Sprintf(" (same as %s)", file.DuplicateFile)
+		case file.Reason != "":
+			line += " (" + file.Reason + ")"
+		case file.Error != "":
+			line += ": " + file.Error
+		}
+		fmt.Println(line)
+	}
+
+	fmt.Printf("\nFormat: %s. Created: %d, duplicates: %d, skipped: %d, failed: %d\n",
+		report.Format, report.Created, report.Duplicates, report.Skipped, report.Failed)
+	if report.DryRun {
+		fmt.Println("Dry run: nothing was saved")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -36,7 +37,7 @@ func main() {
 }
 
 func init() {
-	rootCmd.AddCommand(createCmd, listCmd, getCmd, updateCmd, deleteCmd)
+	rootCmd.AddCommand(createCmd, listCmd, getCmd, updateCmd, patchCmd, deleteCmd, bulkCmd, importCmd)
 }
 
 var createCmd = &cobra.Command{
@@ -46,10 +47,12 @@ var createCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		title := args[0]
 		content := strings.Join(args[1:], " ")
+		tags, _ := cmd.Flags().GetStringSlice("tag")
 
 		note := domain.CreateNoteRequest{
 			Title:   title,
 			Content: content,
+			Tags:    tags,
 		}
 
 		data, err := json.Marshal(note)
@@ -58,7 +61,12 @@ var createCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		resp, err := http.Post(baseURL, "application/json", bytes.NewBuffer(data))
+		// Один ключ на все попытки: сервер создаст заметку только один раз
+		key, _ := cmd.Flags().GetString("idempotency-key")
+		if key == "" {
+			key = newIdempotencyKey()
+		}
+		resp, err := postIdempotent(baseURL, data, key)
 		if err != nil {
 			fmt.Printf("Error sending request: %v\n", err)
 			os.Exit(1)
@@ -86,18 +94,37 @@ var listCmd = &cobra.Command{
 		page, _ := cmd.Flags().GetInt("page")
 		limit, _ := cmd.Flags().GetInt("limit")
 		all, _ := cmd.Flags().GetBool("all")
+		tags, _ := cmd.Flags().GetStringSlice("tag")
+		anyTag, _ := cmd.Flags().GetBool("any-tag")
+		query, _ := cmd.Flags().GetString("query")
+		sortBy, _ := cmd.Flags().GetString("sort")
+		ids, _ := cmd.Flags().GetString("ids")
 
 		// Создаем URL с query параметрами
 		url := baseURL
 		if all {
-			// Если нужны все записи, делаем большой limit
-			limit = 1000 // Максимальное количество для "всех"
-		}
-		if page > 0 || limit > 0 {
+			// Все заметки сервер отдает потоком без пагинации
+			url = baseURL + "/stream"
+		} else if page > 0 || limit > 0 {
 			url = fmt.Sprintf("%s?page=%d&limit=%d", baseURL, page, limit)
 		}
+		url = appendTagFilter(url, tags, anyTag)
+		if query != "" {
+			url = appendParams(url, map[string][]string{"query": {query}})
+		}
+		if sortBy != "" {
+			url = appendParams(url, map[string][]string{"sort": {sortBy}})
+		}
+		if ids != "" {
+			url = appendParams(url, map[string][]string{"ids": {ids}})
+		}
+
+		if all {
+			listAllNotes(url, format)
+			return
+		}
 
-		resp, err := http.Get(url)
+		resp, err := cachedGet(url)
 		if err != nil {
 			fmt.Printf("Error sending request: %v\n", err)
 			os.Exit(1)
@@ -164,6 +191,47 @@ var listCmd = &cobra.Command{
 	},
 }
 
+// listAllNotes читает поток заметок NDJSON и печатает их в формате format
+func listAllNotes(url, format string) {
+	resp, err := http.Get(url)
+	if err != nil {
+		fmt.Printf("Error sending request: %v\n", err)
+		os.Exit(1)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		handleResponse(resp, nil, http.StatusOK)
+	}
+
+	notes := []domain.Note{}
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var note domain.Note
+		if err := decoder.Decode(&note); err == io.EOF {
+			break
+		} else if err != nil {
+			fmt.Printf("Error reading notes: %v\n", err)
+			os.Exit(1)
+		}
+		notes = append(notes, note)
+	}
+
+	if len(notes) == 0 {
+		fmt.Println("No notes found.")
+		return
+	}
+
+	switch format {
+	case "json":
+		output, _ := json.MarshalIndent(notes, "", "  ")
+		fmt.Println(string(output))
+	case "simple":
+		printNotesSimple(notes)
+	default:
+		printNotesTable(notes)
+	}
+}
+
 var getCmd = &cobra.Command{
 	Use:   "get [id]",
 	Short: "Get note by ID",
@@ -176,7 +244,7 @@ var getCmd = &cobra.Command{
 		}
 
 		url := fmt.Sprintf("%s/%d", baseURL, id)
-		resp, err := http.Get(url)
+		resp, err := cachedGet(url)
 		if err != nil {
 			fmt.Printf("Error sending request: %v\n", err)
 			os.Exit(1)
@@ -213,6 +281,10 @@ var updateCmd = &cobra.Command{
 			Title:   title,
 			Content: content,
 		}
+		// Теги отправляем только если они явно указаны, иначе сервер их сохранит
+		if cmd.Flags().Changed("tag") {
+			note.Tags, _ = cmd.Flags().GetStringSlice("tag")
+		}
 
 		data, err := json.Marshal(note)
 		if err != nil {
@@ -227,6 +299,70 @@ var updateCmd = &cobra.Command{
 			os.Exit(1)
 		}
 		req.Header.Set("Content-Type", "application/json")
+		setIfMatch(cmd, req)
+
+		client := &http.Client{}
+		resp, err := client.Do(req)
+		if err != nil {
+			fmt.Printf("Error sending request: %v\n", err)
+			os.Exit(1)
+		}
+		defer resp.Body.Close()
+
+		handleResponse(resp, func(body []byte) {
+			var updatedNote domain.Note
+			if err := json.Unmarshal(body, &updatedNote); err != nil {
+				fmt.Printf("Error parsing response: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Println("Note updated successfully:")
+			printNoteDetail(updatedNote)
+		}, http.StatusOK)
+	},
+}
+
+var patchCmd = &cobra.Command{
+	Use:   "patch [id]",
+	Short: "Change only the given fields of a note",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil || id <= 0 {
+			fmt.Println("Error: invalid note ID")
+			os.Exit(1)
+		}
+
+		// JSON Merge Patch: отправляем только явно указанные поля
+		patch := map[string]any{}
+		if cmd.Flags().Changed("title") {
+			patch["title"], _ = cmd.Flags().GetString("title")
+		}
+		if cmd.Flags().Changed("content") {
+			patch["content"], _ = cmd.Flags().GetString("content")
+		}
+		if cmd.Flags().Changed("tag") {
+			patch["tags"], _ = cmd.Flags().GetStringSlice("tag")
+		}
+		if len(patch) == 0 {
+			fmt.Println("Error: nothing to change, use --title, --content or --tag")
+			os.Exit(1)
+		}
+
+		data, err := json.Marshal(patch)
+		if err != nil {
+			fmt.Printf("Error creating request: %v\n", err)
+			os.Exit(1)
+		}
+
+		url := fmt.Sprintf("%s/%d", baseURL, id)
+		req, err := http.NewRequest("PATCH", url, bytes.NewBuffer(data))
+		if err != nil {
+			fmt.Printf("Error creating request: %v\n", err)
+			os.Exit(1)
+		}
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+		setIfMatch(cmd, req)
 
 		client := &http.Client{}
 		resp, err := client.Do(req)
@@ -266,6 +402,7 @@ var deleteCmd = &cobra.Command{
 			fmt.Printf("Error creating request: %v\n", err)
 			os.Exit(1)
 		}
+		setIfMatch(cmd, req)
 
 		client := &http.Client{}
 		resp, err := client.Do(req)
@@ -283,6 +420,90 @@ var deleteCmd = &cobra.Command{
 	},
 }
 
+var bulkCmd = &cobra.Command{
+	Use:   "bulk [file]",
+	Short: "Apply create/update/delete operations from a JSON file ('-' for stdin)",
+	Long: `Apply a batch of operations in one request. The file holds a JSON array of operations:
+  [{"op": "create", "title": "...", "content": "...", "tags": ["..."]},
+   {"op": "update", "id": 1, "title": "...", "content": "...", "version": 2},
+   {"op": "delete", "id": 2}]`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		atomic, _ := cmd.Flags().GetBool("atomic")
+
+		var data []byte
+		var err error
+		if args[0] == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(args[0])
+		}
+		if err != nil {
+			fmt.Printf("Error reading operations: %v\n", err)
+			os.Exit(1)
+		}
+
+		var operations []domain.BulkOperation
+		if err := json.Unmarshal(data, &operations); err != nil {
+			fmt.Printf("Error parsing operations: %v\n", err)
+			os.Exit(1)
+		}
+
+		body, err := json.Marshal(domain.BulkRequest{Atomic: atomic, Operations: operations})
+		if err != nil {
+			fmt.Printf("Error creating request: %v\n", err)
+			os.Exit(1)
+		}
+
+		resp, err := http.Post(baseURL+"/bulk", "application/json", bytes.NewBuffer(body))
+		if err != nil {
+			fmt.Printf("Error sending request: %v\n", err)
+			os.Exit(1)
+		}
+		defer resp.Body.Close()
+
+		// Ответ 422 (атомарный пакет отменен) тоже содержит результаты операций
+		expected := http.StatusOK
+		if resp.StatusCode == http.StatusUnprocessableEntity {
+			expected = http.StatusUnprocessableEntity
+		}
+		handleResponse(resp, func(body []byte) {
+			var response struct {
+				Succeeded int `json:"succeeded"`
+				Failed    int `json:"failed"`
+				Results   []struct {
+					Index  int    `json:"index"`
+					Op     string `json:"op"`
+					ID     int64  `json:"id"`
+					Status int    `json:"status"`
+					Error  string `json:"error"`
+				} `json:"results"`
+			}
+			if err := json.Unmarshal(body, &response); err != nil {
+				fmt.Printf("Error parsing response: %v\n", err)
+				os.Exit(1)
+			}
+
+			for _, result := range response.Results {
+				line := fmt.Sprintf("%3d. %-6s", result.Index+1, result.Op)
+				if result.ID != 0 {
+					line += fmt.Sprintf(" [%d]", result.ID)
+				}
+				if result.Error != "" {
+					line += fmt.Sprintf(" failed (%d): %s", result.Status, result.Error)
+				} else {
+					line += " ok"
+				}
+				fmt.Println(line)
+			}
+			fmt.Printf("\nSucceeded: %d, failed: %d\n", response.Succeeded, response.Failed)
+			if response.Failed > 0 {
+				os.Exit(1)
+			}
+		}, expected)
+	},
+}
+
 func handleResponse(resp *http.Response, successHandler func([]byte), expectedStatus int) {
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -291,9 +512,21 @@ func handleResponse(resp *http.Response, successHandler func([]byte), expectedSt
 	}
 
 	if resp.StatusCode != expectedStatus {
-		var errorResp map[string]string
-		if err := json.Unmarshal(body, &errorResp); err == nil {
-			fmt.Printf("Error: %s\n", errorResp["error"])
+		// Сервер отвечает документом ошибки application/problem+json (RFC 7807)
+		var problem struct {
+			Title  string              `json:"title"`
+			Detail string              `json:"detail"`
+			Errors []domain.FieldError `json:"errors"`
+		}
+		if err := json.Unmarshal(body, &problem); err == nil && (problem.Detail != "" || problem.Title != "") {
+			message := problem.Detail
+			if message == "" {
+				message = problem.Title
+			}
+			fmt.Printf("Error: %s\n", message)
+			for _, field := range problem.Errors {
+				fmt.Printf("  %s: %s\n", field.Field, field.Message)
+			}
 		} else {
 			fmt.Printf("Error: HTTP %d - %s\n", resp.StatusCode, string(body))
 		}
@@ -339,18 +572,76 @@ func printNoteDetail(note domain.Note) {
 	fmt.Printf("ID:         %d\n", note.ID)
 	fmt.Printf("Title:      %s\n", note.Title)
 	fmt.Printf("Content:    %s\n", note.Content)
+	if len(note.Tags) > 0 {
+		fmt.Printf("Tags:       %s\n", strings.Join(domain.TagNames(note.Tags), ", "))
+	}
 	fmt.Printf("Created:    %s\n", formatTime(note.CreatedAt))
 	fmt.Printf("Updated:    %s\n", formatTime(note.UpdatedAt))
+	fmt.Printf("Version:    %d\n", note.Version)
 	fmt.Println("===================")
 }
 
+// setIfMatch добавляет If-Match с версией заметки из флага --if-match,
+// чтобы сервер отклонил изменение, если заметку уже изменили
+func setIfMatch(cmd *cobra.Command, req *http.Request) {
+	if version, _ := cmd.Flags().GetInt64("if-match"); version > 0 {
+		req.Header.Set("If-Match", fmt.Sprintf(`"%d"`, version))
+	}
+}
+
+// appendTagFilter добавляет к URL фильтр по тегам
+func appendTagFilter(rawURL string, tags []string, anyTag bool) string {
+	if len(tags) == 0 {
+		return rawURL
+	}
+
+	params := url.Values{}
+	for _, tag := range tags {
+		params.Add("tag", tag)
+	}
+	if anyTag {
+		params.Set("tag_mode", "or")
+	}
+
+	return appendParams(rawURL, params)
+}
+
+// appendParams добавляет к URL параметры query string
+func appendParams(rawURL string, params url.Values) string {
+	separator := "?"
+	if strings.Contains(rawURL, "?") {
+		separator = "&"
+	}
+	return rawURL + separator + params.Encode()
+}
+
 func formatTime(t time.Time) string {
 	return t.Format("2006-01-02 15:04:05")
 }
 
 func init() {
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Do not use cached responses for list and get")
+
 	listCmd.Flags().StringP("format", "f", "table", "Output format (table, json, simple)")
 	listCmd.Flags().IntP("page", "p", 1, "Page number")
 	listCmd.Flags().IntP("limit", "l", 10, "Number of notes per page")
 	listCmd.Flags().BoolP("all", "a", false, "Show all notes (overrides page/limit)")
+	listCmd.Flags().StringSliceP("tag", "t", nil, "Filter by tag (repeatable)")
+	listCmd.Flags().Bool("any-tag", false, "Match notes having any of the tags instead of all")
+	listCmd.Flags().StringP("sort", "s", "", "Sort order, e.g. '-updated_at,title'")
+	listCmd.Flags().String("ids", "", "Only notes with these IDs, e.g. '1,2,3'")
+	listCmd.Flags().StringP("query", "q", "", `Structured query, e.g. 'tag:work created:>2026-01-01 -title:draft "exact phrase"'`)
+
+	createCmd.Flags().StringSliceP("tag", "t", nil, "Note tags (repeatable or comma-separated)")
+	createCmd.Flags().String("idempotency-key", "", "Idempotency key, reuse it when retrying to avoid duplicate notes (random by default)")
+	updateCmd.Flags().StringSliceP("tag", "t", nil, "Replace note tags (repeatable or comma-separated)")
+	patchCmd.Flags().String("title", "", "New note title")
+	patchCmd.Flags().String("content", "", "New note content")
+	patchCmd.Flags().StringSliceP("tag", "t", nil, "Replace note tags (repeatable or comma-separated)")
+	bulkCmd.Flags().Bool("atomic", false, "Apply all operations or none of them")
+	importCmd.Flags().String("format", "", "Source format: markdown-zip, obsidian, keep, enex (detected by default)")
+	importCmd.Flags().Bool("dry-run", false, "Only show what would be imported")
+	for _, cmd := range []*cobra.Command{updateCmd, patchCmd, deleteCmd} {
+		cmd.Flags().Int64("if-match", 0, "Apply only if the note still has this version")
+	}
 }
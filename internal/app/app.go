@@ -1,57 +1,261 @@
 package app
 
 import (
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"notes-api/internal/gql"
+	"notes-api/internal/grpcapi"
 	"notes-api/internal/handler"
 	"notes-api/internal/repository"
 	"notes-api/internal/service"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/logger"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/gofiber/fiber/v2/middleware/rewrite"
+	"github.com/valyala/fasthttp"
+	"google.golang.org/grpc"
 )
 
+// Config содержит настройки приложения
+type Config struct {
+	TrashRetention time.Duration         // 0 - автоочистка корзины отключена
+	RequireIfMatch bool                  // PUT, PATCH и DELETE заметки требуют заголовок If-Match
+	IdempotencyTTL time.Duration         // Сколько хранятся ответы на запросы с Idempotency-Key; 0 - ключи не используются
+	V1Deprecation  time.Time             // С какого момента /api/v1 считается устаревшим (заголовок Deprecation); нулевое значение - не объявлено
+	V1Sunset       time.Time             // Когда /api/v1 будет отключен (заголовок Sunset); нулевое значение - дата не объявлена
+	ImportLimit    int                   // Максимальный размер тела запроса импорта в байтах; 0 - как у остальных запросов (4 МБ)
+	GraphQL        gql.Limits            // Ограничения глубины и стоимости запросов GraphQL
+	GraphiQL       bool                  // Страница GraphiQL на GET /api/graphql (режим разработки)
+	EventLogSize   int                   // Сколько последних событий хранится для переподключения с Last-Event-ID
+	Webhooks       service.WebhookConfig // Повторы, таймаут и автоотключение доставки событий вебхукам
+}
+
 // App представляет основное приложение с внедренными зависимостями
 type App struct {
-	repo    repository.NoteRepository
+	repo    repository.Repository
 	service *service.NoteService
 	handler *handler.NoteHandler
+	events  *service.EventBroker
 	fiber   *fiber.App
+	grpc    *grpc.Server
+
+	stopTrashCleanup       func()
+	stopIdempotencyCleanup func()
+	stopWebhookDelivery    func()
 }
 
 // New создает новое приложение с внедрением зависимостей
-func New(repo repository.NoteRepository) *App {
+func New(repo repository.Repository, cfg Config) *App {
 	// Создаем цепочку зависимостей (Dependency Injection)
-	noteService := service.NewNoteService(repo)
-	noteHandler := handler.NewNoteHandler(noteService)
+	// Сервисы общие для всех версий API, версии отличаются только форматом ответов
+	events := service.NewEventBroker(cfg.EventLogSize)
+	services := apiServices{
+		events:    events,
+		notes:     service.NewNoteService(repo, events, cfg.RequireIfMatch),
+		tags:      service.NewTagService(repo, repo, events),
+		notebooks: service.NewNotebookService(repo, repo),
+		revisions: service.NewRevisionService(repo, repo, events),
+		trash:     service.NewTrashService(repo, events),
+		export:    service.NewExportService(repo),
+		imports:   service.NewImportService(repo, events),
+		webhooks:  service.NewWebhookService(repo, cfg.Webhooks),
+	}
+	v1 := newVersionHandlers(services, handler.EnvelopeV1)
+	v2 := newVersionHandlers(services, handler.EnvelopeV2)
+	idempotencyService := service.NewIdempotencyService(repo, cfg.IdempotencyTTL)
+	executor, err := gql.NewExecutor(gql.Services{
+		Notes:     services.notes,
+		Notebooks: services.notebooks,
+		Revisions: services.revisions,
+
+		RequireVersion: cfg.RequireIfMatch,
+	}, cfg.GraphQL)
+	if err != nil {
+		// Схема задается в коде, ошибка в ней - ошибка программы
+		panic(fmt.Sprintf("invalid GraphQL schema: %v", err))
+	}
+	graphQL := handler.NewGraphQLHandler(executor, cfg.GraphiQL)
 
 	// Создаем Fiber приложение
 	app := fiber.New(fiber.Config{
 		AppName: "Notes API",
+		// Все ошибки обработчиков отдаются документом application/problem+json
+		ErrorHandler: handler.ErrorHandler,
 	})
+	// Файлы импорта больше обычных запросов: лимит тела поднимается только для маршрутов импорта
+	if cfg.ImportLimit > 0 {
+		app.Server().HeaderReceived = importBodyLimit(cfg.ImportLimit)
+	}
 
 	// Middleware
 	app.Use(logger.New())
+	// Паника в обработчике превращается в ответ 500 вместо обрыва соединения
+	app.Use(recover.New(recover.Config{EnableStackTrace: true}))
+	// /api без версии - псевдоним /api/v1
+	app.Use(rewrite.New(rewrite.Config{
+		Next: func(c *fiber.Ctx) bool {
+			path := c.Path()
+			return path == "/api/v1" || path == "/api/v2" ||
+				strings.HasPrefix(path, "/api/v1/") || strings.HasPrefix(path, "/api/v2/") ||
+				strings.HasPrefix(path, "/api/docs") || path == "/api/graphql"
+		},
+		Rules: map[string]string{"/api/*": "/api/v1/$1"},
+	}))
 
 	// Настраиваем маршруты
-	setupRoutes(app, noteHandler)
+	setupRoutes(app, v1, v2, handler.RequireIfMatch(cfg.RequireIfMatch), handler.Idempotency(idempotencyService),
+		handler.Deprecation(cfg.V1Deprecation, cfg.V1Sunset, "/api/v2"))
+
+	// GraphQL не зависит от версии REST API: схема одна, развивается добавлением полей
+	app.Get("/api/graphql", graphQL.Query)
+	app.Post("/api/graphql", graphQL.Query)
+
+	// Описание API строится по уже зарегистрированным маршрутам, поэтому подключается последним
+	routes := app.GetRoutes(true)
+	app.Get("/api/v1/openapi.json", handler.OpenAPI(routes, "/api/v1", "1.0.0", handler.EnvelopeV1))
+	app.Get("/api/v2/openapi.json", handler.OpenAPI(routes, "/api/v2", "2.0.0", handler.EnvelopeV2))
+	app.Use("/api/docs", handler.SwaggerUI(
+		handler.SwaggerSpec{Name: "v2", URL: "/api/v2/openapi.json"},
+		handler.SwaggerSpec{Name: "v1 (deprecated)", URL: "/api/v1/openapi.json"},
+	))
 
 	return &App{
 		repo:    repo,
-		service: noteService,
-		handler: noteHandler,
+		service: services.notes,
+		handler: v1.notes,
+		events:  events,
 		fiber:   app,
+		// gRPC использует те же сервисы, что и REST API
+		grpc: grpcapi.NewServer(services.notes, cfg.RequireIfMatch),
+
+		stopTrashCleanup:       services.trash.StartAutoEmpty(cfg.TrashRetention),
+		stopIdempotencyCleanup: idempotencyService.StartCleanup(),
+		// Вебхуки получают те же события, что потоки /api/events
+		stopWebhookDelivery: services.webhooks.StartDelivery(events),
+	}
+}
+
+// importPaths пути импорта заметок во всех версиях API
+var importPaths = []string{"/api/import", "/api/v1/import", "/api/v2/import"}
+
+// importBodyLimit задает лимит тела POST запросов импорта. Лимит выбирается по заголовкам,
+// до чтения тела, поэтому остальные маршруты по-прежнему не принимают больше лимита Fiber.
+func importBodyLimit(limit int) func(header *fasthttp.RequestHeader) fasthttp.RequestConfig {
+	return func(header *fasthttp.RequestHeader) fasthttp.RequestConfig {
+		path, _, _ := strings.Cut(string(header.RequestURI()), "?")
+		if header.IsPost() && slices.Contains(importPaths, strings.TrimSuffix(path, "/")) {
+			return fasthttp.RequestConfig{MaxRequestBodySize: limit}
+		}
+		return fasthttp.RequestConfig{}
 	}
 }
 
-// setupRoutes настраивает все API маршруты
-func setupRoutes(app *fiber.App, handler *handler.NoteHandler) {
-	api := app.Group("/api")
+// apiServices сервисы, общие для всех версий API
+type apiServices struct {
+	events    *service.EventBroker
+	notes     *service.NoteService
+	tags      *service.TagService
+	notebooks *service.NotebookService
+	revisions *service.RevisionService
+	trash     *service.TrashService
+	export    *service.ExportService
+	imports   *service.ImportService
+	webhooks  *service.WebhookService
+}
 
+// versionHandlers обработчики одной версии API
+type versionHandlers struct {
+	notes     *handler.NoteHandler
+	tags      *handler.TagHandler
+	notebooks *handler.NotebookHandler
+	revisions *handler.RevisionHandler
+	trash     *handler.TrashHandler
+	export    *handler.ExportHandler
+	imports   *handler.ImportHandler
+	events    *handler.EventsHandler
+	webhooks  *handler.WebhookHandler
+}
+
+// newVersionHandlers создает обработчики версии API с форматом ответов envelope
+func newVersionHandlers(s apiServices, envelope handler.Envelope) versionHandlers {
+	return versionHandlers{
+		notes:     handler.NewNoteHandler(s.notes, envelope),
+		tags:      handler.NewTagHandler(s.tags, envelope),
+		notebooks: handler.NewNotebookHandler(s.notebooks, envelope),
+		revisions: handler.NewRevisionHandler(s.revisions, envelope),
+		trash:     handler.NewTrashHandler(s.trash, envelope),
+		export:    handler.NewExportHandler(s.export),
+		imports:   handler.NewImportHandler(s.imports, envelope),
+		events:    handler.NewEventsHandler(s.events),
+		webhooks:  handler.NewWebhookHandler(s.webhooks, envelope),
+	}
+}
+
+// setupRoutes настраивает маршруты /api/v1 (устаревшая версия, заголовки deprecation) и /api/v2
+func setupRoutes(app *fiber.App, v1, v2 versionHandlers, ifMatch, idempotency, deprecation fiber.Handler) {
+	registerRoutes(app.Group("/api/v1", deprecation), v1, ifMatch, idempotency)
+	registerRoutes(app.Group("/api/v2"), v2, ifMatch, idempotency)
+}
+
+// registerRoutes настраивает маршруты одной версии API
+func registerRoutes(api fiber.Router, h versionHandlers, ifMatch, idempotency fiber.Handler) {
 	// Notes endpoints
-	api.Post("/notes", handler.CreateNote)
-	api.Get("/notes", handler.GetAllNotes)
-	api.Get("/notes/:id", handler.GetNoteByID)
-	api.Put("/notes/:id", handler.UpdateNote)
-	api.Delete("/notes/:id", handler.DeleteNote)
+	api.Post("/notes", idempotency, h.notes.CreateNote)
+	api.Get("/notes", h.notes.GetAllNotes)
+	api.Get("/notes/search", h.notes.SearchNotes)
+	api.Get("/notes/stream", h.notes.StreamNotes)
+	api.Post("/notes/bulk", h.notes.BulkNotes)
+	api.Get("/notes/:id", h.notes.GetNoteByID)
+	api.Put("/notes/:id", ifMatch, h.notes.UpdateNote)
+	api.Patch("/notes/:id", ifMatch, h.notes.PatchNote)
+	api.Delete("/notes/:id", ifMatch, h.notes.DeleteNote)
+	api.Post("/notes/:id/move", h.notes.MoveNote)
+
+	// Revisions endpoints
+	api.Get("/notes/:id/revisions", h.revisions.GetRevisions)
+	api.Get("/notes/:id/revisions/:rev", h.revisions.GetRevision)
+	api.Get("/notes/:id/revisions/:rev/diff", h.revisions.DiffRevision)
+	api.Post("/notes/:id/revisions/:rev/restore", ifMatch, h.revisions.RestoreRevision)
+
+	// Trash endpoints
+	api.Get("/trash", h.trash.GetTrash)
+	api.Post("/trash/:id/restore", h.trash.RestoreNote)
+	api.Delete("/trash/:id", h.trash.PurgeNote)
+
+	// Tags endpoints
+	api.Get("/tags", h.tags.GetAllTags)
+	api.Post("/tags/merge", h.tags.MergeTags)
+	api.Put("/tags/:name", h.tags.RenameTag)
+
+	// Notebooks endpoints
+	api.Post("/notebooks", h.notebooks.CreateNotebook)
+	api.Get("/notebooks", h.notebooks.GetAllNotebooks)
+	api.Get("/notebooks/:id", h.notebooks.GetNotebookByID)
+	api.Put("/notebooks/:id", h.notebooks.UpdateNotebook)
+	api.Delete("/notebooks/:id", h.notebooks.DeleteNotebook)
+	api.Post("/notebooks/:id/move", h.notebooks.MoveNotebook)
+	api.Get("/notebooks/:id/notes", h.notebooks.GetNotebookNotes)
+
+	// Export and import endpoints
+	api.Get("/export", h.export.Export)
+	api.Post("/import", h.imports.Import)
+
+	// Events endpoints
+	api.Get("/events", h.events.Stream)
+	api.Get("/events/ws", h.events.WebSocket)
+
+	// Webhooks endpoints
+	api.Post("/webhooks", h.webhooks.CreateWebhook)
+	api.Get("/webhooks", h.webhooks.GetAllWebhooks)
+	api.Get("/webhooks/:id", h.webhooks.GetWebhookByID)
+	api.Put("/webhooks/:id", h.webhooks.UpdateWebhook)
+	api.Delete("/webhooks/:id", h.webhooks.DeleteWebhook)
+	api.Get("/webhooks/:id/deliveries", h.webhooks.GetDeliveries)
 
 	// Health check
 	api.Get("/health", func(c *fiber.Ctx) error {
@@ -67,8 +271,24 @@ func (a *App) Run(addr string) error {
 	return a.fiber.Listen(addr)
 }
 
+// RunGRPC запускает gRPC сервер на указанном адресе
+func (a *App) RunGRPC(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return a.grpc.Serve(listener)
+}
+
 // Shutdown корректно останавливает приложение
 func (a *App) Shutdown() error {
+	a.stopTrashCleanup()
+	a.stopIdempotencyCleanup()
+	a.grpc.GracefulStop()
+	// Запросы к вебхукам и ожидание повторов прерываются, доставки записываются в журнал до закрытия хранилища
+	a.stopWebhookDelivery()
+	// Потоки событий бесконечны: без закрытия подписок остановка ждала бы отключения клиентов
+	a.events.Close()
 	return a.fiber.Shutdown()
 }
 
@@ -1,19 +1,53 @@
 package config
 
 import (
+	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 // Config содержит все настройки приложения
 type Config struct {
-	Port       string
-	Repository struct {
+	Env         string // Окружение: development включает инструменты разработчика (GraphiQL)
+	Port        string
+	GRPCPort    string
+	ImportMaxMB int // Максимальный размер файла импорта в мегабайтах; остальные запросы ограничены 4 МБ
+	Repository  struct {
 		Type string
 		DSN  string
 		File string
 	}
+	Trash struct {
+		Retention time.Duration // Сколько заметки хранятся в корзине; 0 - бессрочно
+	}
+	Concurrency struct {
+		RequireIfMatch bool // Изменение заметки без If-Match отклоняется с 428
+	}
+	Idempotency struct {
+		TTL time.Duration // Сколько хранятся ответы на запросы с Idempotency-Key; 0 - ключи не используются
+	}
+	API struct {
+		V1Deprecation time.Time // С какого момента /api/v1 считается устаревшим; нулевое значение - не объявлено
+		V1Sunset      time.Time // Когда /api/v1 будет отключен; нулевое значение - не объявлено
+	}
+	GraphQL struct {
+		MaxDepth      int // Максимальная вложенность полей запроса
+		MaxComplexity int // Максимальная стоимость запроса
+	}
+	Events struct {
+		LogSize int // Сколько последних событий хранится для переподключения с Last-Event-ID
+	}
+	Webhooks struct {
+		MaxAttempts   int           // Сколько раз пытаться доставить событие
+		RetryDelay    time.Duration // Пауза перед первым повтором; каждая следующая вдвое больше
+		MaxRetryDelay time.Duration // Наибольшая пауза между попытками
+		Timeout       time.Duration // Сколько ждать ответа получателя
+		DisableAfter  int           // После скольких неудачных доставок подряд вебхук отключается; 0 - не отключать
+		AllowPrivate  bool          // Разрешить вебхуки на loopback, частные и link-local адреса
+	}
 }
 
 // Load загружает конфигурацию из .env файла и переменных окружения
@@ -24,16 +58,52 @@ func Load() *Config {
 	cfg := &Config{}
 
 	// Server config
+	cfg.Env = getEnv("APP_ENV", "production")
 	cfg.Port = getEnv("PORT", "8081")
+	cfg.GRPCPort = getEnv("GRPC_PORT", "9091")
+	cfg.ImportMaxMB = getIntEnv("MAX_IMPORT_SIZE_MB", 32)
 
 	// Repository config
 	cfg.Repository.Type = getEnv("STORAGE_TYPE", "json")
 	cfg.Repository.DSN = os.Getenv("DATABASE_URL")
 	cfg.Repository.File = getEnv("STORAGE_FILE", "storage/notes.json")
 
+	// Trash config: автоочистка корзины включается явно, например TRASH_RETENTION=720h
+	cfg.Trash.Retention = getDurationEnv("TRASH_RETENTION", 0)
+
+	// Concurrency config
+	cfg.Concurrency.RequireIfMatch = getBoolEnv("REQUIRE_IF_MATCH", false)
+
+	// Idempotency config
+	cfg.Idempotency.TTL = getDurationEnv("IDEMPOTENCY_TTL", 24*time.Hour)
+
+	// API versions config: даты берутся из плана выпуска, без них заголовки устаревания не отправляются
+	cfg.API.V1Deprecation = getDateEnv("API_V1_DEPRECATION", time.Time{})
+	cfg.API.V1Sunset = getDateEnv("API_V1_SUNSET", time.Time{})
+
+	// GraphQL config
+	cfg.GraphQL.MaxDepth = getIntEnv("GRAPHQL_MAX_DEPTH", 8)
+	cfg.GraphQL.MaxComplexity = getIntEnv("GRAPHQL_MAX_COMPLEXITY", 1000)
+
+	// Events config
+	cfg.Events.LogSize = getIntEnv("EVENT_LOG_SIZE", 1000)
+
+	// Webhooks config
+	cfg.Webhooks.MaxAttempts = getIntEnv("WEBHOOK_MAX_ATTEMPTS", 6)
+	cfg.Webhooks.RetryDelay = getDurationEnv("WEBHOOK_RETRY_DELAY", 10*time.Second)
+	cfg.Webhooks.MaxRetryDelay = getDurationEnv("WEBHOOK_MAX_RETRY_DELAY", 10*time.Minute)
+	cfg.Webhooks.Timeout = getDurationEnv("WEBHOOK_TIMEOUT", 10*time.Second)
+	cfg.Webhooks.DisableAfter = getIntEnv("WEBHOOK_DISABLE_AFTER", 10)
+	cfg.Webhooks.AllowPrivate = getBoolEnv("WEBHOOK_ALLOW_PRIVATE", false)
+
 	return cfg
 }
 
+// Development проверяет, что приложение запущено в режиме разработки
+func (c *Config) Development() bool {
+	return c.Env == "development"
+}
+
 // getEnv возвращает значение переменной окружения или значение по умолчанию
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -41,3 +111,71 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getBoolEnv возвращает логическое значение из переменной окружения
+// или значение по умолчанию, если переменная не задана или некорректна
+func getBoolEnv(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Invalid %s=%q, using default %t", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
+// getIntEnv возвращает положительное целое из переменной окружения
+// или значение по умолчанию, если переменная не задана или некорректна
+func getIntEnv(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number <= 0 {
+		log.Printf("Invalid %s=%q, using default %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return number
+}
+
+// getDurationEnv возвращает длительность из переменной окружения (например "720h")
+// или значение по умолчанию, если переменная не задана или некорректна
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Invalid %s=%q, using default %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return duration
+}
+
+// getDateEnv возвращает дату из переменной окружения (YYYY-MM-DD, UTC)
+// или значение по умолчанию, если переменная не задана или некорректна; нулевое значение - дата не задана
+func getDateEnv(key string, defaultValue time.Time) time.Time {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		if defaultValue.IsZero() {
+			log.Printf("Invalid %s=%q, ignoring it", key, value)
+		} else {
+			log.Printf("Invalid %s=%q, using default %s", key, value, defaultValue.Format(time.DateOnly))
+		}
+		return defaultValue
+	}
+	return date
+}
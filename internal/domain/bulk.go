@@ -0,0 +1,52 @@
+package domain
+
+// Виды операций пакетного запроса
+const (
+	BulkCreate = "create"
+	BulkUpdate = "update"
+	BulkDelete = "delete"
+)
+
+// MaxBulkOperations максимальное число операций в одном пакетном запросе
+const MaxBulkOperations = 1000
+
+// BulkRequest пакетный запрос на изменение заметок
+type BulkRequest struct {
+	// true - все операции применяются вместе или не применяется ни одна,
+	// false - каждая операция применяется независимо от остальных
+	Atomic     bool            `json:"atomic"`
+	Operations []BulkOperation `json:"operations" required:"true"`
+}
+
+// BulkOperation операция пакетного запроса.
+// create использует title, content, tags и notebook_id; update - id, title, content, tags
+// (как PUT, теги меняются только если переданы); delete - id.
+// Если version не 0, update и delete применяются только к заметке с этой версией.
+type BulkOperation struct {
+	Op         string   `json:"op" required:"true"`
+	ID         int64    `json:"id"`
+	Version    int64    `json:"version"`
+	Title      string   `json:"title"`
+	Content    string   `json:"content"`
+	Tags       []string `json:"tags"`
+	NotebookID *int64   `json:"notebook_id"`
+}
+
+// BulkNoteOp проверенная операция, которую выполняет хранилище
+type BulkNoteOp struct {
+	Index   int        // Номер операции в исходном запросе
+	Op      string     // BulkCreate, BulkUpdate или BulkDelete
+	ID      int64      // Заметка для update и delete
+	Note    *Note      // Новая заметка для create
+	Patch   *NotePatch // Изменение для update
+	Version int64      // Ожидаемая версия для delete, 0 - любая
+}
+
+// BulkResult результат одной операции пакетного запроса
+type BulkResult struct {
+	Index int
+	Op    string
+	ID    int64
+	Note  *Note // Заметка после create и update
+	Err   error // nil - операция применена
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
+// ErrInvalidCursor ошибка разбора курсора пагинации
+var ErrInvalidCursor = NewFieldError("cursor", "invalid cursor")
+
+// NoteCursor позиция в списке заметок для keyset пагинации по (created_at, id)
+type NoteCursor struct {
+	CreatedAt time.Time `json:"c"`
+	ID        int64     `json:"i"`
+	Before    bool      `json:"b,omitempty"` // true - заметки перед позицией, false - после нее
+}
+
+// NewNoteCursor создает курсор, указывающий на заметку
+func NewNoteCursor(note *Note, before bool) NoteCursor {
+	return NoteCursor{CreatedAt: note.CreatedAt, ID: note.ID, Before: before}
+}
+
+// Encode кодирует курсор в непрозрачную строку для клиента
+func (c NoteCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeNoteCursor разбирает курсор, полученный от клиента
+func DecodeNoteCursor(value string) (*NoteCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor NoteCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID <= 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
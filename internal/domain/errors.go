@@ -0,0 +1,108 @@
+package domain
+
+import "strings"
+
+// NotFoundError запрошенный объект не существует
+type NotFoundError struct {
+	Message string
+}
+
+func (e *NotFoundError) Error() string {
+	return e.Message
+}
+
+// NewNotFoundError создает ошибку отсутствующего объекта
+func NewNotFoundError(message string) *NotFoundError {
+	return &NotFoundError{Message: message}
+}
+
+// FieldError ошибка проверки одного поля запроса
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError данные запроса не прошли проверку.
+// Fields перечисляет ошибки отдельных полей, если их можно указать.
+type ValidationError struct {
+	Message string
+	Fields  []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	if e.Message != "" || len(e.Fields) == 0 {
+		return e.Message
+	}
+
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// NewValidationError создает ошибку проверки запроса без привязки к полю
+func NewValidationError(message string) *ValidationError {
+	return &ValidationError{Message: message}
+}
+
+// NewFieldError создает ошибку проверки одного поля
+func NewFieldError(field, message string) *ValidationError {
+	return &ValidationError{Fields: []FieldError{{Field: field, Message: message}}}
+}
+
+// ConflictError запрос противоречит текущему состоянию данных
+type ConflictError struct {
+	Message string
+}
+
+func (e *ConflictError) Error() string {
+	return e.Message
+}
+
+// NewConflictError создает ошибку конфликта
+func NewConflictError(message string) *ConflictError {
+	return &ConflictError{Message: message}
+}
+
+// PreconditionError не выполнено условие запроса, например ожидаемая версия заметки
+type PreconditionError struct {
+	Message string
+}
+
+func (e *PreconditionError) Error() string {
+	return e.Message
+}
+
+// NewPreconditionError создает ошибку невыполненного условия
+func NewPreconditionError(message string) *PreconditionError {
+	return &PreconditionError{Message: message}
+}
+
+// PreconditionRequiredError запрос изменяет данные без обязательного условия, например ожидаемой версии заметки
+type PreconditionRequiredError struct {
+	Field   string // Поле запроса, в котором передается условие
+	Message string
+}
+
+func (e *PreconditionRequiredError) Error() string {
+	return e.Message
+}
+
+// NewPreconditionRequiredError создает ошибку отсутствующего условия в поле field
+func NewPreconditionRequiredError(field, message string) *PreconditionRequiredError {
+	return &PreconditionRequiredError{Field: field, Message: message}
+}
+
+// UnavailableError хранилище временно недоступно; запрос можно повторить позже
+type UnavailableError struct {
+	Err error
+}
+
+func (e *UnavailableError) Error() string {
+	return "storage unavailable: " + e.Err.Error()
+}
+
+func (e *UnavailableError) Unwrap() error {
+	return e.Err
+}
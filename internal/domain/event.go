@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// Типы событий изменения заметок
+const (
+	EventNoteCreated = "note.created"
+	EventNoteUpdated = "note.updated"
+	EventNoteDeleted = "note.deleted"
+)
+
+// NoteEvent событие изменения заметки
+type NoteEvent struct {
+	ID     int64     `json:"id"` // Номер события, растет на 1 с каждым событием
+	Type   string    `json:"type"`
+	NoteID int64     `json:"note_id"`
+	Note   *Note     `json:"note,omitempty"` // Заметка после изменения; нет у note.deleted
+	Time   time.Time `json:"time"`
+}
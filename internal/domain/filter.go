@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NoteFilter описывает условия отбора заметок при получении списка
+type NoteFilter struct {
+	IDs           []int64    // Если не nil, только заметки с этими ID
+	Tags          []string   // Нормализованные имена тегов
+	MatchAllTags  bool       // true - заметка должна иметь все теги (AND), false - хотя бы один (OR)
+	NotebookIDs   []int64    // Если не nil, только заметки из этих блокнотов
+	Query         QueryNode  // Если не nil, только заметки, подходящие под структурированный запрос
+	CreatedAfter  *time.Time // Созданные строго позже
+	CreatedBefore *time.Time // Созданные строго раньше
+	UpdatedSince  *time.Time // Измененные в этот момент или позже
+	TitlePrefix   string     // Заголовок начинается с префикса (без учета регистра)
+}
+
+// MaxFilterIDs максимальное число ID в фильтре ?ids=
+const MaxFilterIDs = 100
+
+// ParseIDs разбирает список ID через запятую ("1,2,3"); повторы отбрасываются
+func ParseIDs(value string) ([]int64, error) {
+	var ids []int64
+	seen := make(map[int64]bool)
+	for _, part := range strings.Split(value, ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil || id <= 0 {
+			return nil, NewFieldError("ids", fmt.Sprintf("invalid note ID %q in ids", strings.TrimSpace(part)))
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) > MaxFilterIDs {
+		return nil, NewFieldError("ids", fmt.Sprintf("ids accepts at most %d note IDs", MaxFilterIDs))
+	}
+	return ids, nil
+}
+
+// Поля, по которым можно сортировать заметки
+const (
+	SortByID        = "id"
+	SortByTitle     = "title"
+	SortByCreatedAt = "created_at"
+	SortByUpdatedAt = "updated_at"
+)
+
+// SortField поле сортировки и направление
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// DefaultNoteSort порядок заметок по умолчанию: новые первыми
+var DefaultNoteSort = []SortField{{Field: SortByCreatedAt, Desc: true}}
+
+// NoteListOptions параметры получения списка заметок
+type NoteListOptions struct {
+	Filter NoteFilter
+	Sort   []SortField // Если пусто, используется DefaultNoteSort
+	Limit  int
+	Offset int
+	// Если не nil, вместо Offset возвращается до Limit заметок сразу после позиции курсора
+	// (или сразу перед ней при Cursor.Before) в порядке сортировки.
+	// Сортировка должна быть по created_at (CursorSortSupported).
+	Cursor *NoteCursor
+}
+
+// ParseSort разбирает параметр сортировки вида "-updated_at,title":
+// поля через запятую, '-' перед полем означает убывание
+func ParseSort(value string) ([]SortField, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	var fields []SortField
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		field := SortField{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+
+		switch field.Field {
+		case SortByID, SortByTitle, SortByCreatedAt, SortByUpdatedAt:
+		case "":
+			return nil, NewFieldError("sort", "sort field cannot be empty")
+		default:
+			return nil, NewFieldError("sort", fmt.Sprintf("unknown sort field %q (allowed: id, title, created_at, updated_at)", field.Field))
+		}
+		if seen[field.Field] {
+			return nil, NewFieldError("sort", fmt.Sprintf("duplicate sort field %q", field.Field))
+		}
+		seen[field.Field] = true
+
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// CursorSortSupported проверяет, что сортировка совместима с курсором по (created_at, id)
+func (o NoteListOptions) CursorSortSupported() bool {
+	return len(o.Sort) == 0 || (len(o.Sort) == 1 && o.Sort[0].Field == SortByCreatedAt)
+}
+
+// NoteSort возвращает порядок сортировки с ID в конце, чтобы порядок был однозначным
+func (o NoteListOptions) NoteSort() []SortField {
+	fields := o.Sort
+	if len(fields) == 0 {
+		fields = DefaultNoteSort
+	}
+
+	for _, field := range fields {
+		if field.Field == SortByID {
+			return fields
+		}
+	}
+	last := fields[len(fields)-1]
+	return append(append([]SortField(nil), fields...), SortField{Field: SortByID, Desc: last.Desc})
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// IdempotencyKey запрос с заголовком Idempotency-Key и сохраненный ответ на него
+type IdempotencyKey struct {
+	Key         string    `json:"key" gorm:"primaryKey;size:255"`
+	RequestHash string    `json:"request_hash" gorm:"not null"` // SHA-256 метода, пути и тела запроса
+	StatusCode  int       `json:"status_code" gorm:"not null"`  // 0 - запрос еще выполняется
+	ContentType string    `json:"content_type" gorm:"not null"`
+	ETag        string    `json:"etag" gorm:"column:etag;not null"`
+	Body        []byte    `json:"body"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at" gorm:"not null;index"` // После этого момента ключ можно использовать заново
+}
+
+// Completed проверяет, сохранен ли ответ на запрос
+func (k *IdempotencyKey) Completed() bool {
+	return k.StatusCode != 0
+}
+
+// Expired проверяет, истек ли срок хранения ключа к моменту now
+func (k *IdempotencyKey) Expired(now time.Time) bool {
+	return !now.Before(k.ExpiresAt)
+}
@@ -0,0 +1,59 @@
+package domain
+
+// Результаты импорта файла
+const (
+	ImportCreated   = "created"   // Заметка создана (при dry_run - будет создана)
+	ImportDuplicate = "duplicate" // Такая заметка уже есть или встречается в импорте раньше
+	ImportSkipped   = "skipped"   // Файл не является заметкой
+	ImportFailed    = "failed"    // Файл не удалось разобрать или заметка не прошла проверку
+)
+
+// ImportReport отчет об импорте заметок
+type ImportReport struct {
+	Format     string             `json:"format"`
+	DryRun     bool               `json:"dry_run"` // true - ничего не сохранено
+	Created    int                `json:"created"`
+	Duplicates int                `json:"duplicates"`
+	Skipped    int                `json:"skipped"`
+	Failed     int                `json:"failed"`
+	Files      []ImportFileResult `json:"files"`
+}
+
+// ImportFileResult результат импорта одного файла (одной заметки в ENEX)
+type ImportFileResult struct {
+	File          string `json:"file"`
+	Status        string `json:"status"`
+	Title         string `json:"title,omitempty"`
+	NoteID        int64  `json:"note_id,omitempty"`        // Созданная заметка
+	DuplicateOf   int64  `json:"duplicate_of,omitempty"`   // Существующая заметка с тем же заголовком и текстом
+	DuplicateFile string `json:"duplicate_file,omitempty"` // Файл этого же импорта с тем же заголовком и текстом
+	Reason        string `json:"reason,omitempty"`         // Почему файл пропущен
+	Error         string `json:"error,omitempty"`
+}
+
+// Add добавляет результат файла в отчет и обновляет счетчики
+func (r *ImportReport) Add(result ImportFileResult) {
+	r.Files = append(r.Files, result)
+	r.count(result.Status, 1)
+}
+
+// SetStatus меняет результат файла с номером i, например после неудачного сохранения заметки
+func (r *ImportReport) SetStatus(i int, status, message string) {
+	r.count(r.Files[i].Status, -1)
+	r.Files[i].Status = status
+	r.Files[i].Error = message
+	r.count(status, 1)
+}
+
+func (r *ImportReport) count(status string, delta int) {
+	switch status {
+	case ImportCreated:
+		r.Created += delta
+	case ImportDuplicate:
+		r.Duplicates += delta
+	case ImportSkipped:
+		r.Skipped += delta
+	case ImportFailed:
+		r.Failed += delta
+	}
+}
@@ -1,7 +1,6 @@
 package domain
 
 import (
-	"errors"
 	"time"
 
 	"gorm.io/gorm"
@@ -9,33 +8,45 @@ import (
 
 // Note представляет структуру заметки
 type Note struct {
-	ID        int64          `json:"id" gorm:"primaryKey;autoIncrement"`
-	Title     string         `json:"title" gorm:"not null"`
-	Content   string         `json:"content" gorm:"not null"`
-	CreatedAt time.Time      `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	ID         int64          `json:"id" gorm:"primaryKey;autoIncrement;index:idx_notes_created_at_id,priority:2"`
+	Title      string         `json:"title" gorm:"not null"`
+	Content    string         `json:"content" gorm:"not null"`
+	Tags       []Tag          `json:"tags" gorm:"many2many:note_tags;"`
+	NotebookID *int64         `json:"notebook_id" gorm:"index"`
+	Language   string         `json:"language" gorm:"not null;default:''"` // Язык полнотекстового поиска, определяется по тексту
+	Version    int64          `json:"version" gorm:"not null;default:1"`   // Увеличивается при каждом изменении заметки
+	CreatedAt  time.Time      `json:"created_at" gorm:"autoCreateTime;index:idx_notes_created_at_id,priority:1"`
+	UpdatedAt  time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt  gorm.DeletedAt `json:"deleted_at,omitzero" gorm:"index"` // Заполнено только у заметок в корзине
 }
 
 // Validate проверяет корректность данных заметки
 func (n *Note) Validate() error {
+	var fields []FieldError
 	if n.Title == "" {
-		return errors.New("title cannot be empty")
+		fields = append(fields, FieldError{Field: "title", Message: "title cannot be empty"})
 	}
 	if n.Content == "" {
-		return errors.New("content cannot be empty")
+		fields = append(fields, FieldError{Field: "content", Message: "content cannot be empty"})
+	}
+	if len(fields) > 0 {
+		return &ValidationError{Fields: fields}
 	}
 	return nil
 }
 
 // CreateNoteRequest представляет запрос на создание заметки
 type CreateNoteRequest struct {
-	Title   string `json:"title"`
-	Content string `json:"content"`
+	Title      string   `json:"title" required:"true"`
+	Content    string   `json:"content" required:"true"`
+	Tags       []string `json:"tags"`
+	NotebookID *int64   `json:"notebook_id"`
 }
 
 // UpdateNoteRequest представляет запрос на обновление заметки
+// Если Tags не передан (nil), теги заметки не меняются
 type UpdateNoteRequest struct {
-	Title   string `json:"title"`
-	Content string `json:"content"`
+	Title   string   `json:"title" required:"true"`
+	Content string   `json:"content" required:"true"`
+	Tags    []string `json:"tags"`
 }
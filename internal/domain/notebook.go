@@ -0,0 +1,51 @@
+package domain
+
+import "time"
+
+// Notebook представляет блокнот (папку) для заметок.
+// Блокноты могут быть вложены друг в друга через ParentID.
+type Notebook struct {
+	ID        int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name      string    `json:"name" gorm:"not null"`
+	ParentID  *int64    `json:"parent_id" gorm:"index"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// Validate проверяет корректность данных блокнота
+func (n *Notebook) Validate() error {
+	var fields []FieldError
+	if n.Name == "" {
+		fields = append(fields, FieldError{Field: "name", Message: "name cannot be empty"})
+	}
+	if n.ParentID != nil && *n.ParentID <= 0 {
+		fields = append(fields, FieldError{Field: "parent_id", Message: "invalid parent_id"})
+	}
+	if len(fields) > 0 {
+		return &ValidationError{Fields: fields}
+	}
+	return nil
+}
+
+// CreateNotebookRequest представляет запрос на создание блокнота
+type CreateNotebookRequest struct {
+	Name     string `json:"name" required:"true"`
+	ParentID *int64 `json:"parent_id"`
+}
+
+// UpdateNotebookRequest представляет запрос на переименование блокнота
+type UpdateNotebookRequest struct {
+	Name string `json:"name" required:"true"`
+}
+
+// MoveNotebookRequest представляет запрос на перемещение блокнота.
+// ParentID == nil перемещает блокнот в корень.
+type MoveNotebookRequest struct {
+	ParentID *int64 `json:"parent_id"`
+}
+
+// MoveNoteRequest представляет запрос на перемещение заметки в блокнот.
+// NotebookID == nil убирает заметку из блокнотов.
+type MoveNoteRequest struct {
+	NotebookID *int64 `json:"notebook_id"`
+}
@@ -0,0 +1,53 @@
+package domain
+
+// NotePatch частичное изменение заметки: nil поля не меняются
+type NotePatch struct {
+	Title   *string
+	Content *string
+	Tags    []Tag // nil - теги не меняются
+	Version int64 // Ожидаемая версия заметки, 0 - любая
+}
+
+// NotePatchFromNote создает изменение, заменяющее заголовок и содержимое,
+// а теги - только если они заданы в note. Ожидаемая версия берется из note.Version.
+func NotePatchFromNote(note *Note) *NotePatch {
+	return &NotePatch{
+		Title:   &note.Title,
+		Content: &note.Content,
+		Tags:    note.Tags,
+		Version: note.Version,
+	}
+}
+
+// TitleChanged проверяет, меняет ли изменение заголовок заметки
+func (p *NotePatch) TitleChanged(note *Note) bool {
+	return p.Title != nil && *p.Title != note.Title
+}
+
+// ContentChanged проверяет, меняет ли изменение содержимое заметки
+func (p *NotePatch) ContentChanged(note *Note) bool {
+	return p.Content != nil && *p.Content != note.Content
+}
+
+// TagsChanged проверяет, меняет ли изменение набор тегов заметки
+func (p *NotePatch) TagsChanged(note *Note) bool {
+	return p.Tags != nil && !SameTags(note.Tags, p.Tags)
+}
+
+// Changes проверяет, меняет ли изменение хотя бы одно поле заметки
+func (p *NotePatch) Changes(note *Note) bool {
+	return p.TitleChanged(note) || p.ContentChanged(note) || p.TagsChanged(note)
+}
+
+// Apply применяет изменение к заметке
+func (p *NotePatch) Apply(note *Note) {
+	if p.Title != nil {
+		note.Title = *p.Title
+	}
+	if p.Content != nil {
+		note.Content = *p.Content
+	}
+	if p.Tags != nil {
+		note.Tags = p.Tags
+	}
+}
@@ -0,0 +1,76 @@
+package domain
+
+import "time"
+
+// QueryNode узел дерева условий структурированного запроса к заметкам.
+// Дерево не зависит от хранилища: каждый репозиторий переводит его в свои условия.
+type QueryNode interface {
+	queryNode()
+}
+
+// Поля заметки, по которым ищется текст
+const (
+	QueryFieldText    = ""        // Заголовок или содержимое
+	QueryFieldTitle   = "title"   // Только заголовок
+	QueryFieldContent = "content" // Только содержимое
+)
+
+// Поля заметки с датами
+const (
+	QueryFieldCreated = "created_at"
+	QueryFieldUpdated = "updated_at"
+)
+
+// Операторы сравнения дат
+const (
+	QueryOpLess         = "<"
+	QueryOpLessEqual    = "<="
+	QueryOpGreater      = ">"
+	QueryOpGreaterEqual = ">="
+)
+
+// QueryAnd истинен, если истинны все условия
+type QueryAnd struct {
+	Nodes []QueryNode
+}
+
+// QueryOr истинен, если истинно хотя бы одно условие
+type QueryOr struct {
+	Nodes []QueryNode
+}
+
+// QueryNot отрицает условие
+type QueryNot struct {
+	Node QueryNode
+}
+
+// QueryText ищет подстроку в поле без учета регистра
+type QueryText struct {
+	Field string // QueryFieldText, QueryFieldTitle или QueryFieldContent
+	Value string
+}
+
+// QueryTag требует наличия тега у заметки
+type QueryTag struct {
+	Name string // Нормализованное имя тега
+}
+
+// QueryNotebook требует, чтобы заметка лежала в блокноте
+type QueryNotebook struct {
+	ID int64
+}
+
+// QueryTime сравнивает дату заметки со значением
+type QueryTime struct {
+	Field string // QueryFieldCreated или QueryFieldUpdated
+	Op    string // Один из операторов QueryOp*
+	Value time.Time
+}
+
+func (QueryAnd) queryNode()      {}
+func (QueryOr) queryNode()       {}
+func (QueryNot) queryNode()      {}
+func (QueryText) queryNode()     {}
+func (QueryTag) queryNode()      {}
+func (QueryNotebook) queryNode() {}
+func (QueryTime) queryNode()     {}
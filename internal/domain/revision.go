@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+// Revision представляет сохраненную предыдущую версию заметки.
+// Номера ревизий для каждой заметки начинаются с 1 и растут с каждым изменением.
+type Revision struct {
+	ID        int64     `json:"-" gorm:"primaryKey;autoIncrement"`
+	NoteID    int64     `json:"note_id" gorm:"not null;uniqueIndex:idx_revisions_note_number"`
+	Number    int       `json:"number" gorm:"not null;uniqueIndex:idx_revisions_note_number"`
+	Title     string    `json:"title" gorm:"not null"`
+	Content   string    `json:"content" gorm:"not null"`
+	Tags      []string  `json:"tags" gorm:"serializer:json"`
+	CreatedAt time.Time `json:"created_at"` // Когда эта версия была записана
+}
+
+// NewRevision создает ревизию из текущего состояния заметки
+func NewRevision(note *Note, number int) *Revision {
+	return &Revision{
+		NoteID:    note.ID,
+		Number:    number,
+		Title:     note.Title,
+		Content:   note.Content,
+		Tags:      TagNames(note.Tags),
+		CreatedAt: note.UpdatedAt,
+	}
+}
+
+// Text возвращает текстовое представление ревизии для построения diff
+func (r *Revision) Text() string {
+	return NoteText(r.Title, r.Content)
+}
+
+// NoteText возвращает текстовое представление заметки: заголовок, пустая строка, содержимое
+func NoteText(title, content string) string {
+	return title + "\n\n" + content
+}
+
+// RevisionDiff представляет разницу между двумя версиями заметки
+type RevisionDiff struct {
+	NoteID int64  `json:"note_id"`
+	From   int    `json:"from"`
+	To     *int   `json:"to"` // nil - текущая версия заметки
+	Diff   string `json:"diff"`
+}
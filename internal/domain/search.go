@@ -0,0 +1,8 @@
+package domain
+
+// SearchResult представляет найденную заметку с релевантностью и фрагментом текста
+type SearchResult struct {
+	Note    *Note   `json:"note"`
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"` // Совпадения обрамлены <mark></mark>
+}
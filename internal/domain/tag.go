@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxTagLength ограничивает длину имени тега
+const MaxTagLength = 64
+
+// Tag представляет тег заметки.
+// В JSON тег сериализуется просто строкой с именем.
+type Tag struct {
+	ID   int64  `gorm:"primaryKey;autoIncrement"`
+	Name string `gorm:"uniqueIndex;not null"`
+}
+
+// MarshalJSON сериализует тег как строку
+func (t Tag) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Name)
+}
+
+// UnmarshalJSON разбирает тег из строки
+func (t *Tag) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &t.Name)
+}
+
+// TagUsage представляет тег и количество заметок с ним
+type TagUsage struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// RenameTagRequest представляет запрос на переименование тега
+type RenameTagRequest struct {
+	Name string `json:"name" required:"true"`
+}
+
+// MergeTagsRequest представляет запрос на слияние тегов
+type MergeTagsRequest struct {
+	Sources []string `json:"sources" required:"true"`
+	Target  string   `json:"target" required:"true"`
+}
+
+// NormalizeTagName приводит имя тега к каноничному виду и проверяет его
+func NormalizeTagName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return "", NewFieldError("tags", "tag cannot be empty")
+	}
+	if utf8.RuneCountInString(name) > MaxTagLength {
+		return "", NewFieldError("tags", "tag is too long")
+	}
+	if strings.ContainsAny(name, ",/") {
+		return "", NewFieldError("tags", "tag cannot contain ',' or '/'")
+	}
+	return name, nil
+}
+
+// NormalizeTags нормализует список тегов и убирает дубликаты
+func NormalizeTags(names []string) ([]Tag, error) {
+	tags := make([]Tag, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		normalized, err := NormalizeTagName(name)
+		if err != nil {
+			return nil, err
+		}
+		if seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		tags = append(tags, Tag{Name: normalized})
+	}
+	return tags, nil
+}
+
+// TagNames возвращает имена тегов
+func TagNames(tags []Tag) []string {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	return names
+}
+
+// SameTags проверяет, что наборы тегов совпадают без учета порядка
+func SameTags(a, b []Tag) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	names := make(map[string]bool, len(a))
+	for _, tag := range a {
+		names[tag.Name] = true
+	}
+	for _, tag := range b {
+		if !names[tag.Name] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// EventTypes типы событий изменения заметок, на которые можно подписать вебхук
+var EventTypes = []string{EventNoteCreated, EventNoteUpdated, EventNoteDeleted}
+
+// Webhook подписка внешнего сервиса на события изменения заметок
+type Webhook struct {
+	ID     int64    `json:"id" gorm:"primaryKey;autoIncrement"`
+	URL    string   `json:"url" gorm:"not null"`
+	Events []string `json:"events" gorm:"serializer:json"` // Пусто - все события
+	// Secret ключ подписи HMAC-SHA256; в ответах API есть только при создании
+	Secret string `json:"secret,omitempty" gorm:"not null"`
+	Active bool   `json:"active" gorm:"not null"`
+	// FailureCount сколько доставок подряд не удалось после всех повторов
+	FailureCount int        `json:"failure_count" gorm:"not null"`
+	DisabledAt   *time.Time `json:"disabled_at"` // Когда вебхук отключен из-за ошибок доставки
+	CreatedAt    time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// Subscribed проверяет, подписан ли вебхук на события типа eventType
+func (w *Webhook) Subscribed(eventType string) bool {
+	return len(w.Events) == 0 || slices.Contains(w.Events, eventType)
+}
+
+// Validate проверяет адрес и типы событий вебхука
+func (w *Webhook) Validate() error {
+	var fields []FieldError
+	if u, err := url.Parse(w.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		fields = append(fields, FieldError{Field: "url", Message: "url must be an absolute http or https URL"})
+	}
+	for _, event := range w.Events {
+		if !slices.Contains(EventTypes, event) {
+			fields = append(fields, FieldError{Field: "events", Message: fmt.Sprintf("unknown event type %q", event)})
+		}
+	}
+	if len(fields) > 0 {
+		return &ValidationError{Fields: fields}
+	}
+	return nil
+}
+
+// WebhookRequest представляет запрос на создание или изменение вебхука
+type WebhookRequest struct {
+	URL    string   `json:"url" required:"true"`
+	Events []string `json:"events"` // Пусто - все события
+	Secret string   `json:"secret"` // Пусто при создании - сгенерировать; при изменении - оставить прежний
+	Active *bool    `json:"active"` // Нет - true при создании, прежнее при изменении; true включает отключенный вебхук
+}
+
+// Состояния доставки события вебхуку
+const (
+	DeliveryPending   = "pending"   // Доставка выполняется или ждет повтора
+	DeliverySucceeded = "succeeded" // Получатель ответил 2xx
+	DeliveryFailed    = "failed"    // Все попытки неудачны
+)
+
+// WebhookDelivery запись журнала доставки события вебхуку
+type WebhookDelivery struct {
+	ID             int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	WebhookID      int64      `json:"webhook_id" gorm:"not null;index"`
+	EventID        int64      `json:"event_id" gorm:"not null"`
+	EventType      string     `json:"event_type" gorm:"not null"`
+	NoteID         int64      `json:"note_id" gorm:"not null"`
+	Status         string     `json:"status" gorm:"not null"`
+	Attempts       int        `json:"attempts" gorm:"not null"`
+	ResponseStatus int        `json:"response_status,omitempty"` // Код ответа последней попытки; 0 - ответа не было
+	Error          string     `json:"error,omitempty"`           // Ошибка последней попытки
+	NextAttemptAt  *time.Time `json:"next_attempt_at"`           // Когда будет следующая попытка
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
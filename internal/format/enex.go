@@ -0,0 +1,184 @@
+package format
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"notes-api/internal/domain"
+)
+
+// enexTimeLayout формат дат в ENEX
+const enexTimeLayout = "20060102T150405Z"
+
+// extraBlankLines три и больше переводов строки подряд
+var extraBlankLines = regexp.MustCompile(`\n{3,}`)
+
+// enexNote заметка в выгрузке Evernote
+type enexNote struct {
+	Title   string   `xml:"title"`
+	Content string   `xml:"content"` // ENML документ
+	Created string   `xml:"created"`
+	Updated string   `xml:"updated"`
+	Tags    []string `xml:"tag"`
+}
+
+// parseENEX читает заметки из выгрузки Evernote по одной, не разбирая весь документ в память
+func parseENEX(data []byte) ([]ImportedNote, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	var notes []ImportedNote
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, domain.NewValidationError("invalid ENEX file: " + err.Error())
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "note" {
+			continue
+		}
+		var enex enexNote
+		if err := decoder.DecodeElement(&enex, &start); err != nil {
+			return nil, domain.NewValidationError("invalid ENEX file: " + err.Error())
+		}
+		notes = append(notes, enexImportedNote(fmt.Sprintf("note %d", len(notes)+1), enex))
+	}
+
+	if notes == nil {
+		return nil, domain.NewValidationError("ENEX file contains no notes")
+	}
+	return notes, nil
+}
+
+// enexImportedNote преобразует заметку Evernote: ENML становится Markdown текстом, теги сохраняются
+func enexImportedNote(source string, enex enexNote) ImportedNote {
+	note := ImportedNote{Source: source, Title: strings.TrimSpace(enex.Title), Tags: importTags(enex.Tags)}
+
+	content, err := enmlToMarkdown(enex.Content)
+	if err != nil {
+		note.Err = domain.NewValidationError("invalid note content: " + err.Error())
+		return note
+	}
+	note.Content = content
+	if note.Title == "" {
+		note.Title = textTitle(content)
+	}
+	if created, err := time.Parse(enexTimeLayout, enex.Created); err == nil {
+		note.CreatedAt = created
+	}
+	if updated, err := time.Parse(enexTimeLayout, enex.Updated); err == nil {
+		note.UpdatedAt = updated
+	}
+	return note
+}
+
+// enmlToMarkdown преобразует ENML (XHTML Evernote) в текст с разметкой Markdown:
+// заголовки, элементы списков, задачи en-todo и ссылки. Остальное форматирование и вложения отбрасываются.
+func enmlToMarkdown(enml string) (string, error) {
+	decoder := xml.NewDecoder(strings.NewReader(enml))
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	var b strings.Builder
+	var links []string // Адреса открытых ссылок
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch name := strings.ToLower(t.Name.Local); name {
+			case "div", "p", "blockquote", "table", "tr", "ul", "ol":
+				startLine(&b)
+			case "br":
+				b.WriteByte('\n')
+			case "h1", "h2", "h3", "h4", "h5", "h6":
+				startLine(&b)
+				b.WriteString(strings.Repeat("#", int(name[1]-'0')) + " ")
+			case "li":
+				startLine(&b)
+				b.WriteString("- ")
+			case "en-todo":
+				if xmlAttr(t, "checked") == "true" {
+					b.WriteString("[x] ")
+				} else {
+					b.WriteString("[ ] ")
+				}
+			case "a":
+				href := xmlAttr(t, "href")
+				links = append(links, href)
+				if href != "" {
+					b.WriteByte('[')
+				}
+			}
+		case xml.EndElement:
+			switch strings.ToLower(t.Name.Local) {
+			case "div", "p", "blockquote", "table", "tr", "ul", "ol", "li", "h1", "h2", "h3", "h4", "h5", "h6":
+				startLine(&b)
+			case "a":
+				if len(links) == 0 {
+					continue
+				}
+				if href := links[len(links)-1]; href != "" {
+					b.WriteString("](" + href + ")")
+				}
+				links = links[:len(links)-1]
+			}
+		case xml.CharData:
+			// Как в HTML, пробельные символы внутри текста схлопываются
+			text := strings.Join(strings.Fields(string(t)), " ")
+			if text == "" {
+				continue
+			}
+			first, _ := utf8.DecodeRune(t)
+			last, _ := utf8.DecodeLastRune(t)
+			if unicode.IsSpace(first) && b.Len() > 0 && !strings.HasSuffix(b.String(), " ") && !strings.HasSuffix(b.String(), "\n") {
+				b.WriteByte(' ')
+			}
+			b.WriteString(text)
+			if unicode.IsSpace(last) {
+				b.WriteByte(' ')
+			}
+		}
+	}
+
+	lines := strings.Split(b.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.TrimSpace(extraBlankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")), nil
+}
+
+// startLine начинает новую строку, если текущая не пуста
+func startLine(b *strings.Builder) {
+	if b.Len() > 0 && !strings.HasSuffix(b.String(), "\n") {
+		b.WriteByte('\n')
+	}
+}
+
+// xmlAttr возвращает значение атрибута элемента или пустую строку
+func xmlAttr(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if strings.EqualFold(attr.Name.Local, name) {
+			return attr.Value
+		}
+	}
+	return ""
+}
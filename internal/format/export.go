@@ -0,0 +1,190 @@
+package format
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"notes-api/internal/domain"
+)
+
+// ExportFormat формат выгрузки всех заметок
+type ExportFormat string
+
+const (
+	ExportMarkdownZip ExportFormat = "markdown-zip" // ZIP архив, по Markdown файлу на заметку
+	ExportJSON        ExportFormat = "json"         // JSON массив заметок
+	ExportNDJSON      ExportFormat = "ndjson"       // По заметке в JSON на строку
+)
+
+// MIMENDJSON MIME тип newline-delimited JSON
+const MIMENDJSON = "application/x-ndjson"
+
+// maxSlugLength максимальная длина части имени файла, взятой из заголовка
+const maxSlugLength = 60
+
+// ExportFormats поддерживаемые форматы выгрузки
+var ExportFormats = []ExportFormat{ExportMarkdownZip, ExportJSON, ExportNDJSON}
+
+// ParseExportFormat разбирает формат выгрузки; пустое значение - markdown-zip
+func ParseExportFormat(value string) (ExportFormat, error) {
+	if value == "" {
+		return ExportMarkdownZip, nil
+	}
+	for _, f := range ExportFormats {
+		if string(f) == value {
+			return f, nil
+		}
+	}
+	return "", domain.NewFieldError("format", fmt.Sprintf("unknown export format %q", value))
+}
+
+// ContentType возвращает MIME тип выгрузки
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case ExportJSON:
+		return "application/json"
+	case ExportNDJSON:
+		return MIMENDJSON
+	default:
+		return "application/zip"
+	}
+}
+
+// Extension возвращает расширение файла выгрузки
+func (f ExportFormat) Extension() string {
+	switch f {
+	case ExportJSON:
+		return ".json"
+	case ExportNDJSON:
+		return ".ndjson"
+	default:
+		return ".zip"
+	}
+}
+
+// Exporter пишет заметки в выгрузку по одной, не накапливая их в памяти
+type Exporter interface {
+	Write(note *domain.Note) error
+	// Close дописывает окончание выгрузки (конец JSON массива, оглавление ZIP архива)
+	Close() error
+}
+
+// NewExporter создает Exporter, пишущий выгрузку формата f в w
+func NewExporter(f ExportFormat, w io.Writer) Exporter {
+	switch f {
+	case ExportJSON:
+		return &jsonExporter{w: w}
+	case ExportNDJSON:
+		return &ndjsonExporter{encoder: json.NewEncoder(w)}
+	default:
+		return &zipExporter{zip: zip.NewWriter(w)}
+	}
+}
+
+// zipExporter пишет каждую заметку отдельным файлом <id>-<заголовок>.md
+type zipExporter struct {
+	zip *zip.Writer
+}
+
+func (e *zipExporter) Write(note *domain.Note) error {
+	data, err := EncodeMarkdown(note)
+	if err != nil {
+		return err
+	}
+
+	file, err := e.zip.CreateHeader(&zip.FileHeader{
+		Name:     NoteFileName(note),
+		Method:   zip.Deflate,
+		Modified: note.UpdatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	return err
+}
+
+func (e *zipExporter) Close() error {
+	return e.zip.Close()
+}
+
+// jsonExporter пишет заметки JSON массивом
+type jsonExporter struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonExporter) Write(note *domain.Note) error {
+	data, err := json.Marshal(note)
+	if err != nil {
+		return err
+	}
+
+	separator := ",\n"
+	if e.count == 0 {
+		separator = "[\n"
+	}
+	e.count++
+	if _, err := io.WriteString(e.w, separator); err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonExporter) Close() error {
+	end := "\n]\n"
+	if e.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(e.w, end)
+	return err
+}
+
+// ndjsonExporter пишет по заметке на строку
+type ndjsonExporter struct {
+	encoder *json.Encoder
+}
+
+func (e *ndjsonExporter) Write(note *domain.Note) error {
+	return e.encoder.Encode(note)
+}
+
+func (e *ndjsonExporter) Close() error {
+	return nil
+}
+
+// NoteFileName возвращает имя Markdown файла заметки: ID и заголовок, пригодный для имени файла.
+// ID делает имена уникальными, даже если заголовки совпадают.
+func NoteFileName(note *domain.Note) string {
+	if slug := slugify(note.Title); slug != "" {
+		return fmt.Sprintf("%d-%s.md", note.ID, slug)
+	}
+	return fmt.Sprintf("%d.md", note.ID)
+}
+
+// slugify оставляет в заголовке буквы и цифры в нижнем регистре, заменяя остальное дефисами
+func slugify(title string) string {
+	var b strings.Builder
+	dash := false
+	count := 0
+	for _, r := range strings.ToLower(title) {
+		if count >= maxSlugLength {
+			break
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+			count++
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+			count++
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
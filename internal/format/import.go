@@ -0,0 +1,242 @@
+package format
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"notes-api/internal/domain"
+)
+
+// ImportFormat формат файла импорта заметок
+type ImportFormat string
+
+const (
+	ImportMarkdownZip ImportFormat = "markdown-zip" // ZIP архив Markdown файлов с необязательным front matter
+	ImportObsidian    ImportFormat = "obsidian"     // ZIP архив хранилища Obsidian
+	ImportKeep        ImportFormat = "keep"         // Google Keep из Google Takeout: ZIP архив или один JSON файл
+	ImportENEX        ImportFormat = "enex"         // Выгрузка Evernote
+)
+
+// ImportFormats поддерживаемые форматы импорта
+var ImportFormats = []ImportFormat{ImportMarkdownZip, ImportObsidian, ImportKeep, ImportENEX}
+
+const (
+	maxImportFileSize    = 10 << 20  // Максимальный размер одного файла внутри архива после распаковки
+	maxImportArchiveSize = 100 << 20 // Сколько всего можно распаковать из одного архива
+	maxImportFiles       = 10000     // Максимальное число файлов в архиве
+)
+
+// ImportedNote заметка, прочитанная из файла импорта.
+// Если файл не удалось разобрать, заполнено Err; если файл не является заметкой - Skipped.
+type ImportedNote struct {
+	Source     string // Путь файла в архиве или номер заметки в файле
+	Title      string
+	Content    string
+	Tags       []string
+	NotebookID *int64
+	CreatedAt  time.Time // Нулевое время - неизвестно
+	UpdatedAt  time.Time
+	Skipped    string // Причина, по которой файл пропущен
+	Err        error
+}
+
+// ParseImportFormat разбирает формат импорта; пустое значение - определить по содержимому
+func ParseImportFormat(value string) (ImportFormat, error) {
+	if value == "" {
+		return "", nil
+	}
+	for _, f := range ImportFormats {
+		if string(f) == value {
+			return f, nil
+		}
+	}
+	return "", domain.NewFieldError("format", fmt.Sprintf("unknown import format %q", value))
+}
+
+// DetectImportFormat определяет формат файла импорта по содержимому:
+// ZIP с каталогом .obsidian - Obsidian, ZIP с JSON файлами Keep - Google Keep, остальные ZIP - Markdown,
+// JSON - Google Keep, XML - Evernote
+func DetectImportFormat(data []byte) (ImportFormat, error) {
+	trimmed := bytes.TrimLeft(bytes.TrimPrefix(data, []byte("\ufeff")), " \t\r\n")
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return "", domain.NewValidationError("invalid ZIP archive: " + err.Error())
+		}
+		return detectArchiveFormat(archive), nil
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return ImportKeep, nil
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return ImportENEX, nil
+	}
+	return "", domain.NewFieldError("format", "cannot detect import format, pass ?format=")
+}
+
+// detectArchiveFormat определяет формат ZIP архива по именам файлов
+func detectArchiveFormat(archive *zip.Reader) ImportFormat {
+	for _, file := range archive.File {
+		name := strings.TrimPrefix(file.Name, "/")
+		if name == ".obsidian/" || strings.HasPrefix(name, ".obsidian/") || strings.Contains(name, "/.obsidian/") {
+			return ImportObsidian
+		}
+		if isKeepNote(name) {
+			return ImportKeep
+		}
+	}
+	return ImportMarkdownZip
+}
+
+// ParseImport читает заметки из файла импорта формата f
+func ParseImport(f ImportFormat, data []byte) ([]ImportedNote, error) {
+	switch f {
+	case ImportKeep:
+		if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+			return parseArchive(data, parseKeepFile)
+		}
+		return []ImportedNote{parseKeepNote("note.json", data)}, nil
+	case ImportENEX:
+		return parseENEX(data)
+	case ImportObsidian:
+		return parseArchive(data, parseObsidianFile)
+	default:
+		return parseArchive(data, parseMarkdownFile)
+	}
+}
+
+// archiveFileParser разбирает один файл архива; ok false - файл не относится к формату и пропускается
+type archiveFileParser func(name string, data []byte, modified time.Time) (note ImportedNote, ok bool)
+
+// parseArchive разбирает каждый файл ZIP архива, кроме каталогов и скрытых файлов
+func parseArchive(data []byte, parse archiveFileParser) ([]ImportedNote, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, domain.NewValidationError("invalid ZIP archive: " + err.Error())
+	}
+
+	var notes []ImportedNote
+	budget := maxImportArchiveSize
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || isHiddenPath(file.Name) {
+			continue
+		}
+		if len(notes) == maxImportFiles {
+			return nil, domain.NewValidationError(fmt.Sprintf("archive contains more than %d files", maxImportFiles))
+		}
+
+		content, err := readArchiveFile(file, budget)
+		budget -= len(content)
+		if errors.Is(err, errArchiveTooLarge) {
+			return nil, domain.NewValidationError(fmt.Sprintf("archive unpacks to more than %d MB", maxImportArchiveSize>>20))
+		}
+		if err != nil {
+			notes = append(notes, ImportedNote{Source: file.Name, Err: err})
+			continue
+		}
+		note, ok := parse(file.Name, content, file.Modified)
+		if !ok {
+			notes = append(notes, ImportedNote{Source: file.Name, Skipped: "unsupported file type"})
+			continue
+		}
+		notes = append(notes, note)
+	}
+
+	return notes, nil
+}
+
+// errArchiveTooLarge файл не помещается в оставшийся объем распаковки архива
+var errArchiveTooLarge = errors.New("archive is too large")
+
+// readArchiveFile распаковывает файл архива, ограничивая его размер maxImportFileSize
+// и оставшимся объемом распаковки budget. Прочитанные данные возвращаются и при ошибке размера,
+// чтобы вызывающий код учел их в объеме.
+func readArchiveFile(file *zip.File, budget int) ([]byte, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(io.LimitReader(reader, int64(min(maxImportFileSize, budget))+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > budget {
+		return data, errArchiveTooLarge
+	}
+	if len(data) > maxImportFileSize {
+		return data, domain.NewValidationError(fmt.Sprintf("file is larger than %d MB", maxImportFileSize>>20))
+	}
+	return data, nil
+}
+
+// isHiddenPath проверяет, что файл или один из его каталогов скрытый (.obsidian, .trash, __MACOSX)
+func isHiddenPath(name string) bool {
+	for part := range strings.SplitSeq(name, "/") {
+		if strings.HasPrefix(part, ".") || part == "__MACOSX" {
+			return true
+		}
+	}
+	return false
+}
+
+// isMarkdownFile проверяет расширение Markdown файла
+func isMarkdownFile(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	return ext == ".md" || ext == ".markdown"
+}
+
+// fileTitle возвращает имя файла без каталога и расширения
+func fileTitle(name string) string {
+	base := path.Base(name)
+	return strings.TrimSuffix(base, path.Ext(base))
+}
+
+// parseMarkdownFile разбирает Markdown файл с front matter в формате выгрузки (title, tags, notebook_id, даты).
+// Без заголовка в front matter заголовком становится первый "# заголовок", первая строка или имя файла.
+func parseMarkdownFile(name string, data []byte, modified time.Time) (ImportedNote, bool) {
+	if !isMarkdownFile(name) {
+		return ImportedNote{}, false
+	}
+
+	note := ImportedNote{Source: name, CreatedAt: modified, UpdatedAt: modified}
+	meta, content, err := ParseMarkdown(data)
+	if err != nil {
+		note.Err = err
+		return note, true
+	}
+
+	note.Title = meta.Title
+	if note.Title == "" {
+		note.Title = fileTitle(name)
+	}
+	note.Content = content
+	note.Tags = importTags(meta.Tags)
+	note.NotebookID = meta.NotebookID
+	if meta.CreatedAt != nil {
+		note.CreatedAt = *meta.CreatedAt
+	}
+	if meta.UpdatedAt != nil {
+		note.UpdatedAt = *meta.UpdatedAt
+	}
+	return note, true
+}
+
+// importTags приводит имена тегов из других приложений к допустимым: ',' и '/' заменяются на '-',
+// пустые имена отбрасываются
+func importTags(names []string) []string {
+	tags := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.Trim(strings.NewReplacer(",", "-", "/", "-").Replace(strings.TrimSpace(name)), "-")
+		if name != "" {
+			tags = append(tags, name)
+		}
+	}
+	return tags
+}
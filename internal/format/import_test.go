@@ -0,0 +1,75 @@
+package format
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// zipArchive собирает ZIP архив из count одинаковых файлов по size байт
+func zipArchive(t *testing.T, count, size int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	content := bytes.Repeat([]byte{'a'}, size)
+	for i := range count {
+		w, err := archive.Create(fmt.Sprintf("note-%d.md", i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseArchiveLimits(t *testing.T) {
+	tests := []struct {
+		name      string
+		count     int
+		size      int
+		wantErr   string // Ошибка всего архива; пусто - архив разбирается
+		wantFiles int
+		wantBig   int // Сколько файлов отклонено по размеру
+	}{
+		{name: "within limits", count: 3, size: 100, wantFiles: 3},
+		{name: "file too large", count: 2, size: maxImportFileSize + 1, wantFiles: 2, wantBig: 2},
+		{name: "exactly archive size", count: maxImportArchiveSize / maxImportFileSize, size: maxImportFileSize, wantFiles: maxImportArchiveSize / maxImportFileSize},
+		{name: "archive too large", count: maxImportArchiveSize/maxImportFileSize + 1, size: maxImportFileSize, wantErr: "archive unpacks to more than"},
+		{name: "exactly file count", count: maxImportFiles, size: 0, wantFiles: maxImportFiles},
+		{name: "too many files", count: maxImportFiles + 1, size: 0, wantErr: "archive contains more than"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notes, err := ParseImport(ImportMarkdownZip, zipArchive(t, tt.count, tt.size))
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseImport() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseImport() error = %v", err)
+			}
+			if len(notes) != tt.wantFiles {
+				t.Errorf("got %d files, want %d", len(notes), tt.wantFiles)
+			}
+			big := 0
+			for _, note := range notes {
+				if note.Err != nil {
+					big++
+				}
+			}
+			if big != tt.wantBig {
+				t.Errorf("got %d oversized files, want %d", big, tt.wantBig)
+			}
+		})
+	}
+}
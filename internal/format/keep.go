@@ -0,0 +1,86 @@
+package format
+
+import (
+	"encoding/json"
+	"path"
+	"strings"
+	"time"
+
+	"notes-api/internal/domain"
+)
+
+// keepNote заметка Google Keep в Google Takeout (Takeout/Keep/*.json)
+type keepNote struct {
+	Title       string `json:"title"`
+	TextContent string `json:"textContent"`
+	ListContent []struct {
+		Text      string `json:"text"`
+		IsChecked bool   `json:"isChecked"`
+	} `json:"listContent"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	IsTrashed               bool  `json:"isTrashed"`
+	CreatedTimestampUsec    int64 `json:"createdTimestampUsec"`
+	UserEditedTimestampUsec int64 `json:"userEditedTimestampUsec"`
+}
+
+// isKeepNote проверяет, что файл архива Takeout - заметка Keep
+func isKeepNote(name string) bool {
+	return strings.EqualFold(path.Ext(name), ".json") &&
+		(strings.HasPrefix(name, "Keep/") || strings.Contains(name, "/Keep/"))
+}
+
+// parseKeepFile разбирает JSON файл из архива Takeout; HTML копии заметок и вложения пропускаются
+func parseKeepFile(name string, data []byte, _ time.Time) (ImportedNote, bool) {
+	if !strings.EqualFold(path.Ext(name), ".json") {
+		return ImportedNote{}, false
+	}
+	return parseKeepNote(name, data), true
+}
+
+// parseKeepNote разбирает заметку Keep: списки становятся списками задач Markdown, ярлыки - тегами.
+// Заметки из корзины Keep пропускаются.
+func parseKeepNote(name string, data []byte) ImportedNote {
+	note := ImportedNote{Source: name}
+
+	var keep keepNote
+	if err := json.Unmarshal(data, &keep); err != nil {
+		note.Err = domain.NewValidationError("invalid Google Keep note: " + err.Error())
+		return note
+	}
+	if keep.IsTrashed {
+		note.Skipped = "note is in Google Keep trash"
+		return note
+	}
+
+	content := strings.TrimSpace(normalizeNewlines(keep.TextContent))
+	if len(keep.ListContent) > 0 {
+		var list strings.Builder
+		for _, item := range keep.ListContent {
+			mark := " "
+			if item.IsChecked {
+				mark = "x"
+			}
+			list.WriteString("- [" + mark + "] " + item.Text + "\n")
+		}
+		content = strings.TrimSpace(content + "\n\n" + list.String())
+	}
+
+	note.Title = strings.TrimSpace(keep.Title)
+	if note.Title == "" {
+		note.Title = textTitle(content)
+	}
+	note.Content = content
+	for _, label := range keep.Labels {
+		note.Tags = append(note.Tags, label.Name)
+	}
+	note.Tags = importTags(note.Tags)
+	if keep.CreatedTimestampUsec > 0 {
+		note.CreatedAt = time.UnixMicro(keep.CreatedTimestampUsec).UTC()
+	}
+	if keep.UserEditedTimestampUsec > 0 {
+		note.UpdatedAt = time.UnixMicro(keep.UserEditedTimestampUsec).UTC()
+	}
+	return note
+}
@@ -0,0 +1,68 @@
+package format
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"notes-api/internal/domain"
+
+	"gopkg.in/yaml.v3"
+)
+
+// csvHeader колонки CSV списка заметок
+var csvHeader = []string{"id", "title", "content", "tags", "notebook_id", "version", "created_at", "updated_at"}
+
+// WriteCSV пишет заметки в CSV (RFC 4180) с заголовком; теги перечисляются через запятую
+func WriteCSV(w io.Writer, notes []*domain.Note) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, note := range notes {
+		notebookID := ""
+		if note.NotebookID != nil {
+			notebookID = strconv.FormatInt(*note.NotebookID, 10)
+		}
+		record := []string{
+			strconv.FormatInt(note.ID, 10),
+			note.Title,
+			note.Content,
+			strings.Join(domain.TagNames(note.Tags), ","),
+			notebookID,
+			strconv.FormatInt(note.Version, 10),
+			note.CreatedAt.Format(time.RFC3339Nano),
+			note.UpdatedAt.Format(time.RFC3339Nano),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// noteRecord заметка в YAML: метаданные front matter и текст
+type noteRecord struct {
+	NoteMeta `yaml:",inline"`
+	Content  string `yaml:"content"`
+}
+
+// WriteYAML пишет заметки YAML последовательностью
+func WriteYAML(w io.Writer, notes []*domain.Note) error {
+	records := make([]noteRecord, len(notes))
+	for i, note := range notes {
+		records[i] = noteRecord{NoteMeta: NewNoteMeta(note), Content: note.Content}
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(records); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
@@ -0,0 +1,161 @@
+package format
+
+import (
+	"bytes"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"notes-api/internal/domain"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MIME типы представлений заметок
+const (
+	MIMEMarkdown = "text/markdown"
+	MIMEText     = "text/plain"
+	MIMECSV      = "text/csv"
+	MIMEYAML     = "application/yaml"
+)
+
+// frontMatterDelimiter отделяет YAML front matter от текста заметки
+const frontMatterDelimiter = "---"
+
+// maxTextTitleLength максимальная длина заголовка, взятого из первой строки текста
+const maxTextTitleLength = 80
+
+// NoteMeta поля заметки в YAML front matter Markdown файла.
+// При разборе все поля необязательны: файл может быть написан вручную.
+type NoteMeta struct {
+	ID         int64      `yaml:"id,omitempty"`
+	Title      string     `yaml:"title,omitempty"`
+	Tags       []string   `yaml:"tags,omitempty,flow"`
+	NotebookID *int64     `yaml:"notebook_id,omitempty"`
+	Version    int64      `yaml:"version,omitempty"`
+	CreatedAt  *time.Time `yaml:"created_at,omitempty"`
+	UpdatedAt  *time.Time `yaml:"updated_at,omitempty"`
+}
+
+// NewNoteMeta возвращает метаданные заметки для front matter
+func NewNoteMeta(note *domain.Note) NoteMeta {
+	return NoteMeta{
+		ID:         note.ID,
+		Title:      note.Title,
+		Tags:       domain.TagNames(note.Tags),
+		NotebookID: note.NotebookID,
+		Version:    note.Version,
+		CreatedAt:  &note.CreatedAt,
+		UpdatedAt:  &note.UpdatedAt,
+	}
+}
+
+// EncodeMarkdown возвращает заметку Markdown документом: YAML front matter и текст заметки
+func EncodeMarkdown(note *domain.Note) ([]byte, error) {
+	meta, err := yaml.Marshal(NewNoteMeta(note))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(frontMatterDelimiter + "\n")
+	buf.Write(meta)
+	buf.WriteString(frontMatterDelimiter + "\n")
+	buf.WriteString(note.Content)
+	if !strings.HasSuffix(note.Content, "\n") {
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// EncodeText возвращает заметку простым текстом: заголовок, пустая строка и текст
+func EncodeText(note *domain.Note) []byte {
+	text := note.Title + "\n\n" + note.Content
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	return []byte(text)
+}
+
+// ParseMarkdown разбирает Markdown документ с необязательным YAML front matter.
+// Если в front matter нет заголовка, им становится первый заголовок "# ..." в начале текста
+// (он убирается из текста) или первая строка текста.
+func ParseMarkdown(data []byte) (NoteMeta, string, error) {
+	var meta NoteMeta
+	text, err := splitFrontMatter(data, &meta)
+	if err != nil {
+		return meta, "", err
+	}
+
+	if meta.Title == "" {
+		firstLine, rest, _ := strings.Cut(text, "\n")
+		if heading, ok := strings.CutPrefix(firstLine, "# "); ok {
+			meta.Title = strings.TrimSpace(heading)
+			text = strings.TrimLeft(rest, "\n")
+		} else {
+			meta.Title = textTitle(text)
+		}
+	}
+
+	return meta, strings.TrimRight(text, "\n"), nil
+}
+
+// splitFrontMatter разбирает YAML front matter документа в meta и возвращает текст после него
+// без пустых строк в начале. Документ без front matter возвращается целиком.
+func splitFrontMatter(data []byte, meta any) (string, error) {
+	text := strings.TrimPrefix(normalizeNewlines(string(data)), "\ufeff")
+
+	if rest, ok := strings.CutPrefix(text, frontMatterDelimiter+"\n"); ok {
+		header, body, found := strings.Cut(rest, "\n"+frontMatterDelimiter+"\n")
+		if !found {
+			// Пустой front matter
+			body, found = strings.CutPrefix(rest, frontMatterDelimiter+"\n")
+		}
+		if !found {
+			header, found = strings.CutSuffix(rest, "\n"+frontMatterDelimiter)
+		}
+		if !found {
+			return "", domain.NewValidationError("front matter is not closed with ---")
+		}
+		if err := yaml.Unmarshal([]byte(header), meta); err != nil {
+			return "", domain.NewValidationError("invalid front matter: " + err.Error())
+		}
+		text = body
+	}
+
+	return strings.TrimLeft(text, "\n"), nil
+}
+
+// ParseText разбирает простой текст в формате EncodeText: заголовок - первая строка, текст заметки - остальные.
+// Текст из одной строки становится и заголовком, и текстом заметки.
+func ParseText(data []byte) (title, content string) {
+	text := strings.Trim(normalizeNewlines(string(data)), "\n")
+	firstLine, rest, _ := strings.Cut(text, "\n")
+	rest = strings.Trim(rest, "\n")
+	if rest == "" || utf8.RuneCountInString(strings.TrimSpace(firstLine)) > maxTextTitleLength {
+		return textTitle(text), text
+	}
+	return strings.TrimSpace(firstLine), rest
+}
+
+// textTitle возвращает первую непустую строку текста без Markdown разметки заголовка,
+// сокращенную до maxTextTitleLength символов
+func textTitle(text string) string {
+	for line := range strings.Lines(text) {
+		line = strings.TrimSpace(strings.TrimLeft(line, "#"))
+		if line == "" {
+			continue
+		}
+		if utf8.RuneCountInString(line) > maxTextTitleLength {
+			runes := []rune(line)
+			line = strings.TrimSpace(string(runes[:maxTextTitleLength-1])) + "…"
+		}
+		return line
+	}
+	return ""
+}
+
+// normalizeNewlines заменяет переводы строк Windows на \n
+func normalizeNewlines(text string) string {
+	return strings.ReplaceAll(text, "\r\n", "\n")
+}
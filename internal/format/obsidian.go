@@ -0,0 +1,82 @@
+package format
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// obsidianTagPattern тег в тексте заметки Obsidian: #тег или #вложенный/тег, не только из цифр
+var obsidianTagPattern = regexp.MustCompile(`(?:^|\s)#([\p{L}\p{N}_/-]*[\p{L}_/-][\p{L}\p{N}_/-]*)`)
+
+// obsidianMeta свойства заметки Obsidian в front matter
+type obsidianMeta struct {
+	Title string     `yaml:"title"`
+	Tags  stringList `yaml:"tags"`
+}
+
+// stringList список строк в YAML, который можно записать и одной строкой через запятые или пробелы
+type stringList []string
+
+func (l *stringList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*l = strings.FieldsFunc(node.Value, func(r rune) bool { return r == ',' || r == ' ' })
+		return nil
+	}
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+// parseObsidianFile разбирает заметку хранилища Obsidian: заголовок - имя файла, теги - из свойства tags
+// и из #тегов в тексте. Ссылки [[...]] и вложения остаются в тексте как есть.
+func parseObsidianFile(name string, data []byte, modified time.Time) (ImportedNote, bool) {
+	if !isMarkdownFile(name) {
+		return ImportedNote{}, false
+	}
+
+	note := ImportedNote{Source: name, CreatedAt: modified, UpdatedAt: modified}
+	var meta obsidianMeta
+	content, err := splitFrontMatter(data, &meta)
+	if err != nil {
+		note.Err = err
+		return note, true
+	}
+
+	note.Title = meta.Title
+	if note.Title == "" {
+		note.Title = fileTitle(name)
+	}
+	note.Content = strings.TrimRight(content, "\n")
+
+	tags := make([]string, 0, len(meta.Tags))
+	for _, tag := range meta.Tags {
+		tags = append(tags, strings.TrimPrefix(tag, "#"))
+	}
+	note.Tags = importTags(append(tags, inlineTags(content)...))
+	return note, true
+}
+
+// inlineTags возвращает #теги из текста, кроме блоков кода
+func inlineTags(content string) []string {
+	var tags []string
+	inCode := false
+	for line := range strings.Lines(content) {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			continue
+		}
+		for _, match := range obsidianTagPattern.FindAllStringSubmatch(line, -1) {
+			tags = append(tags, match[1])
+		}
+	}
+	return tags
+}
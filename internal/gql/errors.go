@@ -0,0 +1,87 @@
+package gql
+
+import (
+	"errors"
+	"log"
+
+	"notes-api/internal/domain"
+	"notes-api/internal/service"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Коды ошибок в extensions.code
+const (
+	CodeNotFound           = "NOT_FOUND"
+	CodeBadUserInput       = "BAD_USER_INPUT"
+	CodeConflict           = "CONFLICT"
+	CodePreconditionFailed = "PRECONDITION_FAILED"
+	CodeUnavailable        = "UNAVAILABLE"
+	CodeInternal           = "INTERNAL"
+	CodeQueryTooDeep       = "QUERY_TOO_DEEP"
+	CodeQueryTooComplex    = "QUERY_TOO_COMPLEX"
+)
+
+// Error ошибка GraphQL с кодом в extensions
+type Error struct {
+	Message string
+	Code    string
+	Fields  []domain.FieldError // Ошибки отдельных полей для BAD_USER_INPUT
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Extensions реализует gqlerrors.ExtendedError
+func (e *Error) Extensions() map[string]any {
+	extensions := map[string]any{"code": e.Code}
+	if len(e.Fields) > 0 {
+		extensions["fields"] = e.Fields
+	}
+	return extensions
+}
+
+// resolve оборачивает резолвер: ошибки предметной области получают код,
+// остальные записываются в лог и отдаются клиенту без подробностей
+func resolve(fn graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		result, err := fn(p)
+		if err != nil {
+			return nil, resolverError(err)
+		}
+		return result, nil
+	}
+}
+
+func resolverError(err error) *Error {
+	var (
+		notFound     *domain.NotFoundError
+		validation   *domain.ValidationError
+		queryErr     *service.QueryError
+		conflict     *domain.ConflictError
+		precondition *domain.PreconditionError
+		required     *domain.PreconditionRequiredError
+		unavailable  *domain.UnavailableError
+	)
+	switch {
+	case errors.As(err, &notFound):
+		return &Error{Message: err.Error(), Code: CodeNotFound}
+	case errors.As(err, &queryErr):
+		return &Error{Message: err.Error(), Code: CodeBadUserInput}
+	case errors.As(err, &validation):
+		return &Error{Message: err.Error(), Code: CodeBadUserInput, Fields: validation.Fields}
+	case errors.As(err, &required):
+		return &Error{Message: err.Error(), Code: CodeBadUserInput, Fields: []domain.FieldError{{Field: required.Field, Message: required.Message}}}
+	case errors.As(err, &conflict):
+		return &Error{Message: err.Error(), Code: CodeConflict}
+	case errors.As(err, &precondition):
+		return &Error{Message: err.Error(), Code: CodePreconditionFailed}
+	case errors.As(err, &unavailable):
+		log.Printf("GraphQL: %v", err)
+		return &Error{Message: "storage is temporarily unavailable", Code: CodeUnavailable}
+	default:
+		log.Printf("GraphQL: %v", err)
+		return &Error{Message: "internal server error", Code: CodeInternal}
+	}
+}
@@ -0,0 +1,100 @@
+package gql
+
+import (
+	"context"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// Request запрос GraphQL
+type Request struct {
+	Query         string         `json:"query"`
+	Variables     map[string]any `json:"variables"`
+	OperationName string         `json:"operationName"`
+	ReadOnly      bool           `json:"-"` // Мутации запрещены (запрос GET)
+}
+
+// Executor выполняет запросы GraphQL с проверкой ограничений глубины и стоимости
+type Executor struct {
+	schema graphql.Schema
+	limits Limits
+}
+
+// NewExecutor создает исполнитель запросов над сервисами services
+func NewExecutor(services Services, limits Limits) (*Executor, error) {
+	schema, err := NewSchema(services)
+	if err != nil {
+		return nil, err
+	}
+	return &Executor{schema: schema, limits: limits}, nil
+}
+
+// Execute разбирает и проверяет запрос, затем выполняет его.
+// Ошибки разбора, проверки и ограничений возвращаются в Result.Errors без выполнения запроса.
+func (e *Executor) Execute(ctx context.Context, req Request) *graphql.Result {
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{
+		Body: []byte(req.Query),
+		Name: "GraphQL request",
+	})})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	validation := graphql.ValidateDocument(&e.schema, doc, nil)
+	if !validation.IsValid {
+		return &graphql.Result{Errors: validation.Errors}
+	}
+
+	// Если операцию не удалось выбрать, об этом сообщит graphql.Execute
+	if op := selectOperation(doc, req.OperationName); op != nil {
+		if req.ReadOnly && op.Operation != ast.OperationTypeQuery {
+			return errorResult(&Error{Message: "only queries are allowed in GET requests; send mutations with POST", Code: CodeBadUserInput})
+		}
+		if err := checkLimits(&e.schema, doc, op, req.Variables, e.limits); err != nil {
+			return errorResult(err)
+		}
+	}
+
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        e.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       ctx,
+	})
+}
+
+// selectOperation возвращает операцию с именем name или единственную операцию документа
+func selectOperation(doc *ast.Document, name string) *ast.OperationDefinition {
+	var found *ast.OperationDefinition
+	for _, definition := range doc.Definitions {
+		op, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if name != "" {
+			if op.Name != nil && op.Name.Value == name {
+				return op
+			}
+			continue
+		}
+		if found != nil {
+			return nil
+		}
+		found = op
+	}
+	return found
+}
+
+func errorResult(err *Error) *graphql.Result {
+	return &graphql.Result{Errors: []gqlerrors.FormattedError{{
+		Message:    err.Message,
+		Locations:  []location.SourceLocation{},
+		Extensions: err.Extensions(),
+	}}}
+}
@@ -0,0 +1,199 @@
+package gql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Limits ограничения запроса, проверяемые до выполнения
+type Limits struct {
+	MaxDepth      int // Максимальная вложенность полей; 0 - без ограничения
+	MaxComplexity int // Максимальная стоимость запроса; 0 - без ограничения
+}
+
+// defaultListSize сколько элементов предполагается в списке без аргумента limit (версии заметки, блокноты)
+const defaultListSize = 10
+
+// complexity оценивает стоимость и глубину операции запроса.
+// Каждое поле стоит 1; поля внутри списка стоят столько раз, сколько элементов в нем может быть:
+// для страницы заметок - значение limit, для остальных списков - defaultListSize.
+// Служебные поля интроспекции (__schema, __typename) не учитываются.
+type complexity struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]any
+	limits    Limits
+}
+
+// checkLimits проверяет операцию op документа doc на ограничения глубины и стоимости
+func checkLimits(schema *graphql.Schema, doc *ast.Document, op *ast.OperationDefinition, variables map[string]any, limits Limits) *Error {
+	c := &complexity{
+		fragments: make(map[string]*ast.FragmentDefinition),
+		variables: variables,
+		limits:    limits,
+	}
+	for _, definition := range doc.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok {
+			c.fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	root := schema.QueryType()
+	if op.Operation == ast.OperationTypeMutation {
+		root = schema.MutationType()
+	}
+
+	cost, err := c.selectionSet(op.SelectionSet, root, 1, 0)
+	if err != nil {
+		return err
+	}
+	if limits.MaxComplexity > 0 && cost > limits.MaxComplexity {
+		return &Error{
+			Message: fmt.Sprintf("query complexity %d exceeds the limit of %d; request fewer fields or smaller pages", cost, limits.MaxComplexity),
+			Code:    CodeQueryTooComplex,
+		}
+	}
+	return nil
+}
+
+// selectionSet возвращает стоимость полей set типа parent на глубине depth.
+// pageSize - limit страницы, которой принадлежат поля (0 - поля не на странице).
+func (c *complexity) selectionSet(set *ast.SelectionSet, parent *graphql.Object, depth, pageSize int) (int, *Error) {
+	if set == nil {
+		return 0, nil
+	}
+
+	total := 0
+	for _, selection := range set.Selections {
+		var (
+			cost int
+			err  *Error
+		)
+		switch selection := selection.(type) {
+		case *ast.Field:
+			cost, err = c.field(selection, parent, depth, pageSize)
+		case *ast.InlineFragment:
+			cost, err = c.selectionSet(selection.SelectionSet, parent, depth, pageSize)
+		case *ast.FragmentSpread:
+			// Циклы фрагментов отклоняются проверкой документа раньше
+			if fragment := c.fragments[selection.Name.Value]; fragment != nil {
+				cost, err = c.selectionSet(fragment.SelectionSet, parent, depth, pageSize)
+			}
+		}
+		if err != nil {
+			return 0, err
+		}
+		total += cost
+	}
+	return total, nil
+}
+
+func (c *complexity) field(field *ast.Field, parent *graphql.Object, depth, pageSize int) (int, *Error) {
+	name := field.Name.Value
+	if strings.HasPrefix(name, "__") {
+		return 0, nil
+	}
+	if c.limits.MaxDepth > 0 && depth > c.limits.MaxDepth {
+		return 0, &Error{
+			Message: fmt.Sprintf("query depth exceeds the limit of %d at field %q", c.limits.MaxDepth, name),
+			Code:    CodeQueryTooDeep,
+		}
+	}
+
+	definition := parent.Fields()[name]
+	if definition == nil {
+		return 1, nil
+	}
+	object, ok := namedType(definition.Type).(*graphql.Object)
+	if !ok {
+		return 1, nil
+	}
+
+	// Поля страницы (items) повторяются limit раз
+	childPageSize := 0
+	if limit, ok := c.limitArgument(field, definition); ok {
+		childPageSize = limit
+	}
+	childCost, err := c.selectionSet(field.SelectionSet, object, depth+1, childPageSize)
+	if err != nil {
+		return 0, err
+	}
+
+	if isList(definition.Type) {
+		size := defaultListSize
+		if pageSize > 0 {
+			size = pageSize
+		}
+		childCost *= size
+	}
+	return 1 + childCost, nil
+}
+
+// limitArgument возвращает значение аргумента limit поля: из запроса, переменной или по умолчанию
+func (c *complexity) limitArgument(field *ast.Field, definition *graphql.FieldDefinition) (int, bool) {
+	var value any
+	for _, arg := range definition.Args {
+		if arg.Name() == "limit" {
+			value = arg.DefaultValue
+		}
+	}
+	if value == nil {
+		return 0, false
+	}
+
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != "limit" {
+			continue
+		}
+		switch argValue := arg.Value.(type) {
+		case *ast.IntValue:
+			value = argValue.Value
+		case *ast.Variable:
+			if variable, ok := c.variables[argValue.Name.Value]; ok && variable != nil {
+				value = variable
+			}
+		}
+	}
+
+	limit := 0
+	switch value := value.(type) {
+	case int:
+		limit = value
+	case float64:
+		limit = int(value)
+	case json.Number:
+		n, _ := value.Int64()
+		limit = int(n)
+	case string:
+		limit, _ = strconv.Atoi(value)
+	}
+	// Лимит вне допустимого диапазона отклонит резолвер; для оценки берем ближайшее допустимое значение
+	return min(max(limit, 1), maxPageLimit), true
+}
+
+// namedType возвращает тип без оберток NonNull и List
+func namedType(t graphql.Type) graphql.Type {
+	for {
+		switch wrapper := t.(type) {
+		case *graphql.NonNull:
+			t = wrapper.OfType
+		case *graphql.List:
+			t = wrapper.OfType
+		default:
+			return t
+		}
+	}
+}
+
+// isList проверяет, что поле возвращает список
+func isList(t graphql.Type) bool {
+	if nonNull, ok := t.(*graphql.NonNull); ok {
+		t = nonNull.OfType
+	}
+	_, ok := t.(*graphql.List)
+	return ok
+}
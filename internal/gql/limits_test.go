@@ -0,0 +1,139 @@
+package gql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// testSchema схема без сервисов: проверке ограничений резолверы не нужны
+func testSchema(t *testing.T) graphql.Schema {
+	t.Helper()
+	schema, err := NewSchema(Services{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
+
+func TestQueryComplexity(t *testing.T) {
+	schema := testSchema(t)
+
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]any
+		want      int
+	}{
+		{name: "scalar fields", query: `{ note(id: 1) { id title } }`, want: 3},
+		{name: "introspection fields are free", query: `{ __typename note(id: 1) { __typename id } }`, want: 2},
+		{name: "default page limit", query: `{ notes { total items { id } } }`, want: 1 + 1 + (1 + 10)},
+		{name: "page limit multiplies items", query: `{ notes(limit: 50) { items { id title } } }`, want: 1 + (1 + 2*50)},
+		{name: "page limit from variable", query: `query($l: Int) { notes(limit: $l) { items { id } } }`, variables: map[string]any{"l": 20}, want: 1 + (1 + 20)},
+		{name: "page limit from JSON variable", query: `query($l: Int) { notes(limit: $l) { items { id } } }`, variables: map[string]any{"l": float64(30)}, want: 1 + (1 + 30)},
+		{name: "page limit above maximum", query: `{ notes(limit: 1000) { items { id } } }`, want: 1 + (1 + maxPageLimit)},
+		{name: "page limit below minimum", query: `{ notes(limit: 0) { items { id } } }`, want: 1 + (1 + 1)},
+		{name: "list without limit", query: `{ notebooks { id name } }`, want: 1 + 2*defaultListSize},
+		{name: "revisions list", query: `{ note(id: 1) { revisions { number } } }`, want: 1 + (1 + defaultListSize)},
+		{
+			name:  "nested pages multiply",
+			query: `{ notebooks { notes(limit: 5) { items { id } } } }`,
+			want:  1 + defaultListSize*(1+(1+5)),
+		},
+		{name: "named fragment", query: `{ note(id: 1) { ...fields } } fragment fields on Note { id title }`, want: 3},
+		{name: "inline fragment", query: `{ note(id: 1) { ... on Note { id } } }`, want: 2},
+		{name: "mutation", query: `mutation { createNote(input: {title: "a", content: "b"}) { id } }`, want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parser.Parse(parser.ParseParams{Source: tt.query})
+			if err != nil {
+				t.Fatal(err)
+			}
+			op := selectOperation(doc, "")
+			if op == nil {
+				t.Fatal("no operation in query")
+			}
+
+			c := &complexity{fragments: make(map[string]*ast.FragmentDefinition), variables: tt.variables}
+			for _, definition := range doc.Definitions {
+				if fragment, ok := definition.(*ast.FragmentDefinition); ok {
+					c.fragments[fragment.Name.Value] = fragment
+				}
+			}
+			root := schema.QueryType()
+			if op.Operation == ast.OperationTypeMutation {
+				root = schema.MutationType()
+			}
+			got, limitErr := c.selectionSet(op.SelectionSet, root, 1, 0)
+			if limitErr != nil {
+				t.Fatalf("unexpected error: %v", limitErr)
+			}
+			if got != tt.want {
+				t.Errorf("complexity = %d, want %d", got, tt.want)
+			}
+
+			// Запрос со стоимостью ровно на границе проходит, на единицу дороже - нет
+			if err := checkLimits(&schema, doc, op, tt.variables, Limits{MaxComplexity: tt.want}); err != nil {
+				t.Errorf("checkLimits() with limit %d: %v", tt.want, err)
+			}
+			if err := checkLimits(&schema, doc, op, tt.variables, Limits{MaxComplexity: tt.want - 1}); err == nil || err.Code != CodeQueryTooComplex {
+				t.Errorf("checkLimits() with limit %d = %v, want %s", tt.want-1, err, CodeQueryTooComplex)
+			}
+		})
+	}
+}
+
+func TestExecutorLimits(t *testing.T) {
+	deep := `{ note(id: 1) { notebook { notes { items { id } } } } }` // id на глубине 5
+
+	tests := []struct {
+		name     string
+		limits   Limits
+		query    string
+		readOnly bool
+		wantCode string // Пусто - запрос проходит проверку ограничений
+	}{
+		{name: "depth within limit", limits: Limits{MaxDepth: 5}, query: deep},
+		{name: "too deep", limits: Limits{MaxDepth: 4}, query: deep, wantCode: CodeQueryTooDeep},
+		{name: "no depth limit", limits: Limits{}, query: deep},
+		{name: "too deep through fragment", limits: Limits{MaxDepth: 2}, query: `{ note(id: 1) { ...f } } fragment f on Note { notebook { id } }`, wantCode: CodeQueryTooDeep},
+		{name: "too complex", limits: Limits{MaxComplexity: 100}, query: `{ notes(limit: 100) { items { id } } }`, wantCode: CodeQueryTooComplex},
+		{name: "no complexity limit", limits: Limits{}, query: `{ notes(limit: 100) { items { id title content } } }`},
+		{name: "mutation in GET", query: `mutation { deleteNote(id: 1) }`, readOnly: true, wantCode: CodeBadUserInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor, err := NewExecutor(Services{}, tt.limits)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.wantCode == "" {
+				doc, err := parser.Parse(parser.ParseParams{Source: tt.query})
+				if err != nil {
+					t.Fatal(err)
+				}
+				schema := testSchema(t)
+				if err := checkLimits(&schema, doc, selectOperation(doc, ""), nil, tt.limits); err != nil {
+					t.Errorf("checkLimits() = %v, want nil", err)
+				}
+				return
+			}
+
+			// Отклоненный запрос не выполняется, поэтому сервисы не нужны
+			result := executor.Execute(context.Background(), Request{Query: tt.query, ReadOnly: tt.readOnly})
+			if len(result.Errors) != 1 {
+				t.Fatalf("got errors %v, want one error", result.Errors)
+			}
+			if code := result.Errors[0].Extensions["code"]; code != tt.wantCode {
+				t.Errorf("error code = %v, want %s (%s)", code, tt.wantCode, result.Errors[0].Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,494 @@
+package gql
+
+import (
+	"strconv"
+	"time"
+
+	"notes-api/internal/domain"
+	"notes-api/internal/service"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Ограничения страницы списка заметок, как у REST API
+const (
+	defaultPageLimit = 10
+	maxPageLimit     = 100
+)
+
+// Services сервисы, через которые выполняются запросы GraphQL
+type Services struct {
+	Notes     *service.NoteService
+	Notebooks *service.NotebookService
+	Revisions *service.RevisionService
+	// RequireVersion updateNote и deleteNote без version отклоняются, как REST запросы без If-Match
+	RequireVersion bool
+}
+
+// notePage страница списка заметок
+type notePage struct {
+	notes []*domain.Note
+	total int
+	page  int
+	limit int
+}
+
+// schemaBuilder строит схему GraphQL; типы ссылаются друг на друга, поэтому поля задаются отложенно
+type schemaBuilder struct {
+	services Services
+
+	note     *graphql.Object
+	notebook *graphql.Object
+	revision *graphql.Object
+	notePage *graphql.Object
+}
+
+// NewSchema создает схему GraphQL: запросы note, notes, notebook, notebooks
+// и мутации createNote, updateNote, deleteNote
+func NewSchema(services Services) (graphql.Schema, error) {
+	b := &schemaBuilder{services: services}
+	b.defineTypes()
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    b.query(),
+		Mutation: b.mutation(),
+	})
+}
+
+func (b *schemaBuilder) defineTypes() {
+	b.revision = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Revision",
+		Description: "Сохраненная версия заметки",
+		Fields: graphql.Fields{
+			"number":    revisionField(graphql.NewNonNull(graphql.Int), func(r *domain.Revision) any { return r.Number }),
+			"title":     revisionField(graphql.NewNonNull(graphql.String), func(r *domain.Revision) any { return r.Title }),
+			"content":   revisionField(graphql.NewNonNull(graphql.String), func(r *domain.Revision) any { return r.Content }),
+			"tags":      revisionField(stringList, func(r *domain.Revision) any { return nonNilStrings(r.Tags) }),
+			"createdAt": revisionField(graphql.NewNonNull(graphql.DateTime), func(r *domain.Revision) any { return r.CreatedAt }),
+		},
+	})
+
+	b.note = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Note",
+		Description: "Заметка",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":         noteField(graphql.NewNonNull(graphql.ID), func(n *domain.Note) any { return n.ID }),
+				"title":      noteField(graphql.NewNonNull(graphql.String), func(n *domain.Note) any { return n.Title }),
+				"content":    noteField(graphql.NewNonNull(graphql.String), func(n *domain.Note) any { return n.Content }),
+				"tags":       noteField(stringList, func(n *domain.Note) any { return nonNilStrings(domain.TagNames(n.Tags)) }),
+				"notebookId": noteField(graphql.ID, func(n *domain.Note) any { return optionalID(n.NotebookID) }),
+				"language":   noteField(graphql.NewNonNull(graphql.String), func(n *domain.Note) any { return n.Language }),
+				"version":    noteField(graphql.NewNonNull(graphql.Int), func(n *domain.Note) any { return n.Version }),
+				"createdAt":  noteField(graphql.NewNonNull(graphql.DateTime), func(n *domain.Note) any { return n.CreatedAt }),
+				"updatedAt":  noteField(graphql.NewNonNull(graphql.DateTime), func(n *domain.Note) any { return n.UpdatedAt }),
+				"notebook": &graphql.Field{
+					Type:        b.notebook,
+					Description: "Блокнот заметки; null, если заметка вне блокнотов",
+					Resolve: resolve(func(p graphql.ResolveParams) (any, error) {
+						note := p.Source.(*domain.Note)
+						if note.NotebookID == nil {
+							return nil, nil
+						}
+						return b.services.Notebooks.GetNotebookByID(*note.NotebookID)
+					}),
+				},
+				"revisions": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(b.revision))),
+					Description: "Сохраненные версии заметки",
+					Resolve: resolve(func(p graphql.ResolveParams) (any, error) {
+						return b.services.Revisions.GetRevisions(p.Source.(*domain.Note).ID)
+					}),
+				},
+			}
+		}),
+	})
+
+	b.notePage = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "NotePage",
+		Description: "Страница списка заметок",
+		Fields: graphql.Fields{
+			"items": pageField(graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(b.note))), func(p *notePage) any { return p.notes }),
+			"total": pageField(graphql.NewNonNull(graphql.Int), func(p *notePage) any { return p.total }),
+			"page":  pageField(graphql.NewNonNull(graphql.Int), func(p *notePage) any { return p.page }),
+			"limit": pageField(graphql.NewNonNull(graphql.Int), func(p *notePage) any { return p.limit }),
+			"hasNext": pageField(graphql.NewNonNull(graphql.Boolean), func(p *notePage) any {
+				return p.page*p.limit < p.total
+			}),
+		},
+	})
+
+	b.notebook = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Notebook",
+		Description: "Блокнот",
+		Fields: graphql.Fields{
+			"id":        notebookField(graphql.NewNonNull(graphql.ID), func(n *domain.Notebook) any { return n.ID }),
+			"name":      notebookField(graphql.NewNonNull(graphql.String), func(n *domain.Notebook) any { return n.Name }),
+			"parentId":  notebookField(graphql.ID, func(n *domain.Notebook) any { return optionalID(n.ParentID) }),
+			"createdAt": notebookField(graphql.NewNonNull(graphql.DateTime), func(n *domain.Notebook) any { return n.CreatedAt }),
+			"updatedAt": notebookField(graphql.NewNonNull(graphql.DateTime), func(n *domain.Notebook) any { return n.UpdatedAt }),
+			"notes": &graphql.Field{
+				Type:        graphql.NewNonNull(b.notePage),
+				Description: "Заметки блокнота и всех вложенных блокнотов",
+				Args:        pageArgs(graphql.FieldConfigArgument{}),
+				Resolve: resolve(func(p graphql.ResolveParams) (any, error) {
+					id := p.Source.(*domain.Notebook).ID
+					return b.notes(p.Args, func(opts domain.NoteListOptions) ([]*domain.Note, int, error) {
+						return b.services.Notebooks.GetNotebookNotes(id, opts)
+					})
+				}),
+			},
+		},
+	})
+}
+
+func (b *schemaBuilder) query() *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"note": &graphql.Field{
+				Type:        b.note,
+				Description: "Заметка по ID",
+				Args:        graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}},
+				Resolve: resolve(func(p graphql.ResolveParams) (any, error) {
+					id, err := idArg(p.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+					return b.services.Notes.GetNoteByID(id)
+				}),
+			},
+			"notes": &graphql.Field{
+				Type:        graphql.NewNonNull(b.notePage),
+				Description: "Список заметок с фильтром, сортировкой и постраничной выдачей",
+				Args: pageArgs(graphql.FieldConfigArgument{
+					"filter": {Type: noteFilterInput},
+					"sort": {
+						Type:        graphql.String,
+						Description: "Поля сортировки через запятую, '-' - по убыванию: \"-updated_at,title\"",
+					},
+				}),
+				Resolve: resolve(func(p graphql.ResolveParams) (any, error) {
+					return b.notes(p.Args, b.services.Notes.GetAllNotes)
+				}),
+			},
+			"notebook": &graphql.Field{
+				Type:        b.notebook,
+				Description: "Блокнот по ID",
+				Args:        graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}},
+				Resolve: resolve(func(p graphql.ResolveParams) (any, error) {
+					id, err := idArg(p.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+					return b.services.Notebooks.GetNotebookByID(id)
+				}),
+			},
+			"notebooks": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(b.notebook))),
+				Description: "Все блокноты",
+				Resolve: resolve(func(p graphql.ResolveParams) (any, error) {
+					return b.services.Notebooks.GetAllNotebooks()
+				}),
+			},
+		},
+	})
+}
+
+func (b *schemaBuilder) mutation() *graphql.Object {
+	versionArg := &graphql.ArgumentConfig{
+		Type:        graphql.Int,
+		Description: "Ожидаемая версия заметки; при несовпадении - ошибка PRECONDITION_FAILED. Обязательна при REQUIRE_IF_MATCH=true",
+	}
+
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createNote": &graphql.Field{
+				Type:        graphql.NewNonNull(b.note),
+				Description: "Создает заметку",
+				Args:        graphql.FieldConfigArgument{"input": {Type: graphql.NewNonNull(createNoteInput)}},
+				Resolve: resolve(func(p graphql.ResolveParams) (any, error) {
+					input := p.Args["input"].(map[string]any)
+					req := domain.CreateNoteRequest{
+						Title:   stringArg(input, "title"),
+						Content: stringArg(input, "content"),
+						Tags:    stringsArg(input, "tags"),
+					}
+					if _, ok := input["notebookId"]; ok {
+						id, err := idArg(input, "notebookId")
+						if err != nil {
+							return nil, err
+						}
+						req.NotebookID = &id
+					}
+					return b.services.Notes.CreateNote(req)
+				}),
+			},
+			"updateNote": &graphql.Field{
+				Type:        graphql.NewNonNull(b.note),
+				Description: "Заменяет заголовок и текст заметки; теги меняются, только если переданы",
+				Args: graphql.FieldConfigArgument{
+					"id":      {Type: graphql.NewNonNull(graphql.ID)},
+					"input":   {Type: graphql.NewNonNull(updateNoteInput)},
+					"version": versionArg,
+				},
+				Resolve: resolve(func(p graphql.ResolveParams) (any, error) {
+					id, err := idArg(p.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+					input := p.Args["input"].(map[string]any)
+					req := domain.UpdateNoteRequest{
+						Title:   stringArg(input, "title"),
+						Content: stringArg(input, "content"),
+						Tags:    stringsArg(input, "tags"),
+					}
+					version, err := b.version(p.Args)
+					if err != nil {
+						return nil, err
+					}
+					return b.services.Notes.UpdateNote(id, req, version)
+				}),
+			},
+			"deleteNote": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.Boolean),
+				Description: "Перемещает заметку в корзину",
+				Args: graphql.FieldConfigArgument{
+					"id":      {Type: graphql.NewNonNull(graphql.ID)},
+					"version": versionArg,
+				},
+				Resolve: resolve(func(p graphql.ResolveParams) (any, error) {
+					id, err := idArg(p.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+					version, err := b.version(p.Args)
+					if err != nil {
+						return nil, err
+					}
+					if err := b.services.Notes.DeleteNote(id, version); err != nil {
+						return nil, err
+					}
+					return true, nil
+				}),
+			},
+		},
+	})
+}
+
+// notes получает страницу заметок по аргументам filter, sort, page и limit
+func (b *schemaBuilder) notes(args map[string]any, fetch func(domain.NoteListOptions) ([]*domain.Note, int, error)) (*notePage, error) {
+	page, limit := args["page"].(int), args["limit"].(int)
+	if page < 1 {
+		return nil, domain.NewFieldError("page", "page must be at least 1")
+	}
+	if limit < 1 || limit > maxPageLimit {
+		return nil, domain.NewFieldError("limit", "limit must be between 1 and "+strconv.Itoa(maxPageLimit))
+	}
+
+	filter, err := noteFilter(args["filter"])
+	if err != nil {
+		return nil, err
+	}
+	sort, err := domain.ParseSort(stringArg(args, "sort"))
+	if err != nil {
+		return nil, err
+	}
+
+	notes, total, err := fetch(domain.NoteListOptions{
+		Filter: filter,
+		Sort:   sort,
+		Limit:  limit,
+		Offset: (page - 1) * limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &notePage{notes: notes, total: total, page: page, limit: limit}, nil
+}
+
+// stringList непустой список строк
+var stringList = graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))
+
+var tagModeEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "TagMode",
+	Values: graphql.EnumValueConfigMap{
+		"AND": {Value: true, Description: "Заметка должна иметь все теги"},
+		"OR":  {Value: false, Description: "Заметка должна иметь хотя бы один тег"},
+	},
+})
+
+var noteFilterInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "NoteFilter",
+	Description: "Условия отбора заметок, как параметры GET /api/v2/notes",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"ids":           {Type: graphql.NewList(graphql.NewNonNull(graphql.ID))},
+		"tags":          {Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		"tagMode":       {Type: tagModeEnum, DefaultValue: true},
+		"query":         {Type: graphql.String, Description: "Структурированный запрос: tag:work AND created:>2024-01-01"},
+		"createdAfter":  {Type: graphql.DateTime},
+		"createdBefore": {Type: graphql.DateTime},
+		"updatedSince":  {Type: graphql.DateTime},
+		"titlePrefix":   {Type: graphql.String},
+	},
+})
+
+var createNoteInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "CreateNoteInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"title":      {Type: graphql.NewNonNull(graphql.String)},
+		"content":    {Type: graphql.NewNonNull(graphql.String)},
+		"tags":       {Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		"notebookId": {Type: graphql.ID},
+	},
+})
+
+var updateNoteInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "UpdateNoteInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"title":   {Type: graphql.NewNonNull(graphql.String)},
+		"content": {Type: graphql.NewNonNull(graphql.String)},
+		"tags":    {Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+	},
+})
+
+// pageArgs добавляет к аргументам поля page и limit
+func pageArgs(args graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+	args["page"] = &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 1}
+	args["limit"] = &graphql.ArgumentConfig{
+		Type:         graphql.Int,
+		DefaultValue: defaultPageLimit,
+		Description:  "Заметок на странице, не больше " + strconv.Itoa(maxPageLimit),
+	}
+	return args
+}
+
+// noteFilter собирает фильтр заметок из входного объекта NoteFilter
+func noteFilter(value any) (domain.NoteFilter, error) {
+	var filter domain.NoteFilter
+	input, _ := value.(map[string]any)
+	if input == nil {
+		return filter, nil
+	}
+
+	if values, ok := input["ids"].([]any); ok {
+		if len(values) > domain.MaxFilterIDs {
+			return filter, domain.NewFieldError("filter.ids", "ids accepts at most "+strconv.Itoa(domain.MaxFilterIDs)+" note IDs")
+		}
+		filter.IDs = []int64{}
+		for _, value := range values {
+			id, err := parseID("filter.ids", value)
+			if err != nil {
+				return filter, err
+			}
+			filter.IDs = append(filter.IDs, id)
+		}
+	}
+
+	tags, err := domain.NormalizeTags(stringsArg(input, "tags"))
+	if err != nil {
+		return filter, err
+	}
+	filter.Tags = domain.TagNames(tags)
+	filter.MatchAllTags, _ = input["tagMode"].(bool)
+
+	if filter.Query, err = service.ParseNoteQuery(stringArg(input, "query")); err != nil {
+		return filter, err
+	}
+
+	filter.CreatedAfter = timeArg(input, "createdAfter")
+	filter.CreatedBefore = timeArg(input, "createdBefore")
+	filter.UpdatedSince = timeArg(input, "updatedSince")
+	filter.TitlePrefix = stringArg(input, "titlePrefix")
+
+	return filter, nil
+}
+
+// idArg разбирает ID из аргумента name
+func idArg(args map[string]any, name string) (int64, error) {
+	return parseID(name, args[name])
+}
+
+func parseID(field string, value any) (int64, error) {
+	text, _ := value.(string)
+	id, err := strconv.ParseInt(text, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, domain.NewFieldError(field, "invalid ID "+strconv.Quote(text))
+	}
+	return id, nil
+}
+
+func stringArg(args map[string]any, name string) string {
+	value, _ := args[name].(string)
+	return value
+}
+
+// stringsArg возвращает список строк; nil, если аргумент не передан
+func stringsArg(args map[string]any, name string) []string {
+	values, ok := args[name].([]any)
+	if !ok {
+		return nil
+	}
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		result = append(result, value.(string))
+	}
+	return result
+}
+
+func timeArg(args map[string]any, name string) *time.Time {
+	value, ok := args[name].(time.Time)
+	if !ok {
+		return nil
+	}
+	return &value
+}
+
+// version возвращает ожидаемую версию заметки из аргумента version; 0 - без проверки.
+// Если версия обязательна, мутация без нее отклоняется.
+func (b *schemaBuilder) version(args map[string]any) (int64, error) {
+	version, _ := args["version"].(int)
+	if version <= 0 && b.services.RequireVersion {
+		return 0, service.ErrVersionRequired
+	}
+	return int64(version), nil
+}
+
+func optionalID(id *int64) any {
+	if id == nil {
+		return nil
+	}
+	return *id
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+func noteField(t graphql.Output, get func(*domain.Note) any) *graphql.Field {
+	return &graphql.Field{Type: t, Resolve: func(p graphql.ResolveParams) (any, error) {
+		return get(p.Source.(*domain.Note)), nil
+	}}
+}
+
+func notebookField(t graphql.Output, get func(*domain.Notebook) any) *graphql.Field {
+	return &graphql.Field{Type: t, Resolve: func(p graphql.ResolveParams) (any, error) {
+		return get(p.Source.(*domain.Notebook)), nil
+	}}
+}
+
+func revisionField(t graphql.Output, get func(*domain.Revision) any) *graphql.Field {
+	return &graphql.Field{Type: t, Resolve: func(p graphql.ResolveParams) (any, error) {
+		return get(p.Source.(*domain.Revision)), nil
+	}}
+}
+
+func pageField(t graphql.Output, get func(*notePage) any) *graphql.Field {
+	return &graphql.Field{Type: t, Resolve: func(p graphql.ResolveParams) (any, error) {
+		return get(p.Source.(*notePage)), nil
+	}}
+}
@@ -0,0 +1,125 @@
+package grpcapi
+
+import (
+	"fmt"
+	"time"
+
+	"notes-api/internal/domain"
+	"notes-api/internal/service"
+	notesv1 "notes-api/pkg/api/notes/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Ограничения страницы списка, как у REST API
+const (
+	defaultLimit = 10
+	maxLimit     = 100
+)
+
+// noteMessage преобразует заметку в сообщение protobuf
+func noteMessage(note *domain.Note) *notesv1.Note {
+	if note == nil {
+		return nil
+	}
+	return &notesv1.Note{
+		Id:         note.ID,
+		Title:      note.Title,
+		Content:    note.Content,
+		Tags:       domain.TagNames(note.Tags),
+		NotebookId: note.NotebookID,
+		Language:   note.Language,
+		Version:    note.Version,
+		CreatedAt:  timestamppb.New(note.CreatedAt),
+		UpdatedAt:  timestamppb.New(note.UpdatedAt),
+	}
+}
+
+func noteMessages(notes []*domain.Note) []*notesv1.Note {
+	messages := make([]*notesv1.Note, len(notes))
+	for i, note := range notes {
+		messages[i] = noteMessage(note)
+	}
+	return messages
+}
+
+// noteFilter собирает фильтр заметок из сообщения NoteFilter
+func noteFilter(msg *notesv1.NoteFilter) (domain.NoteFilter, error) {
+	filter := domain.NoteFilter{MatchAllTags: true}
+	if msg == nil {
+		return filter, nil
+	}
+
+	if len(msg.GetIds()) > domain.MaxFilterIDs {
+		return filter, domain.NewFieldError("filter.ids", fmt.Sprintf("ids accepts at most %d note IDs", domain.MaxFilterIDs))
+	}
+	if len(msg.GetIds()) > 0 {
+		filter.IDs = msg.GetIds()
+	}
+
+	tags, err := domain.NormalizeTags(msg.GetTags())
+	if err != nil {
+		return filter, err
+	}
+	filter.Tags = domain.TagNames(tags)
+	filter.MatchAllTags = !msg.GetMatchAnyTag()
+
+	if filter.Query, err = service.ParseNoteQuery(msg.GetQuery()); err != nil {
+		return filter, err
+	}
+
+	filter.CreatedAfter = optionalTime(msg.GetCreatedAfter())
+	filter.CreatedBefore = optionalTime(msg.GetCreatedBefore())
+	filter.UpdatedSince = optionalTime(msg.GetUpdatedSince())
+	filter.TitlePrefix = msg.GetTitlePrefix()
+
+	return filter, nil
+}
+
+func optionalTime(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+// tagNames возвращает теги из TagList; nil, если список не задан (теги не меняются)
+func tagNames(list *notesv1.TagList) []string {
+	if list == nil {
+		return nil
+	}
+	if list.Names == nil {
+		return []string{}
+	}
+	return list.Names
+}
+
+// pagination возвращает limit и offset с теми же значениями по умолчанию и ограничениями, что у REST API
+func pagination(limit, offset int32) (int, int) {
+	if limit < 1 {
+		limit = defaultLimit
+	}
+	return int(min(limit, maxLimit)), int(max(offset, 0))
+}
+
+var patchFormats = map[notesv1.PatchFormat]service.PatchFormat{
+	notesv1.PatchFormat_PATCH_FORMAT_MERGE_PATCH: service.MergePatch,
+	notesv1.PatchFormat_PATCH_FORMAT_JSON_PATCH:  service.JSONPatch,
+}
+
+var bulkOps = map[notesv1.BulkOp]string{
+	notesv1.BulkOp_BULK_OP_CREATE: domain.BulkCreate,
+	notesv1.BulkOp_BULK_OP_UPDATE: domain.BulkUpdate,
+	notesv1.BulkOp_BULK_OP_DELETE: domain.BulkDelete,
+}
+
+// bulkOpMessage возвращает вид операции пакетного запроса для ответа
+func bulkOpMessage(op string) notesv1.BulkOp {
+	for msg, name := range bulkOps {
+		if name == op {
+			return msg
+		}
+	}
+	return notesv1.BulkOp_BULK_OP_UNSPECIFIED
+}
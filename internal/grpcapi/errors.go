@@ -0,0 +1,106 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"log"
+	"runtime/debug"
+
+	"notes-api/internal/domain"
+	"notes-api/internal/repository"
+	"notes-api/internal/service"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// statusError преобразует ошибку сервиса в статус gRPC, как ErrorHandler REST API - в код HTTP.
+// Ошибки проверки содержат детали google.rpc.BadRequest с ошибками полей;
+// внутренние ошибки записываются в лог и отдаются клиенту без подробностей.
+func statusError(err error) *status.Status {
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+
+	var (
+		queryErr        *service.QueryError
+		validationErr   *domain.ValidationError
+		notFoundErr     *domain.NotFoundError
+		conflictErr     *domain.ConflictError
+		preconditionErr *domain.PreconditionError
+		requiredErr     *domain.PreconditionRequiredError
+		unavailableErr  *domain.UnavailableError
+	)
+	switch {
+	case errors.As(err, &queryErr):
+		return status.New(codes.InvalidArgument, err.Error())
+	case errors.As(err, &validationErr):
+		return withFieldViolations(codes.InvalidArgument, err.Error(), validationErr.Fields)
+	case errors.As(err, &requiredErr):
+		// Как и ошибка проверки: запрос без обязательного поля
+		return withFieldViolations(codes.InvalidArgument, err.Error(), []domain.FieldError{{Field: requiredErr.Field, Message: requiredErr.Message}})
+	case errors.As(err, &notFoundErr):
+		return status.New(codes.NotFound, err.Error())
+	case errors.As(err, &preconditionErr), errors.Is(err, repository.ErrBulkNotApplied):
+		// Несовпадение версии - неудачная проверка перед записью: клиент перечитывает заметку и повторяет
+		return status.New(codes.Aborted, err.Error())
+	case errors.As(err, &conflictErr):
+		return status.New(codes.FailedPrecondition, err.Error())
+	case errors.As(err, &unavailableErr):
+		log.Printf("gRPC: %v", err)
+		return status.New(codes.Unavailable, "storage is temporarily unavailable, retry later")
+	default:
+		log.Printf("gRPC: %v", err)
+		return status.New(codes.Internal, "internal server error")
+	}
+}
+
+// withFieldViolations создает статус с ошибками полей в деталях
+func withFieldViolations(code codes.Code, message string, fields []domain.FieldError) *status.Status {
+	st := status.New(code, message)
+	if len(fields) == 0 {
+		return st
+	}
+
+	details := &errdetails.BadRequest{}
+	for _, field := range fields {
+		details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       field.Field,
+			Description: field.Message,
+		})
+	}
+	if withDetails, err := st.WithDetails(details); err == nil {
+		return withDetails
+	}
+	return st
+}
+
+// unaryErrors преобразует ошибки обработчиков в статусы gRPC; паника превращается в INTERNAL
+func unaryErrors(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer recoverPanic(info.FullMethod, &err)
+
+	resp, err = handler(ctx, req)
+	if err != nil {
+		return nil, statusError(err).Err()
+	}
+	return resp, nil
+}
+
+// streamErrors то же для потоковых методов
+func streamErrors(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer recoverPanic(info.FullMethod, &err)
+
+	if err := handler(srv, stream); err != nil {
+		return statusError(err).Err()
+	}
+	return nil
+}
+
+func recoverPanic(method string, err *error) {
+	if r := recover(); r != nil {
+		log.Printf("gRPC: panic in %s: %v\n%s", method, r, debug.Stack())
+		*err = status.Error(codes.Internal, "internal server error")
+	}
+}
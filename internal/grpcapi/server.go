@@ -0,0 +1,233 @@
+package grpcapi
+
+import (
+	"context"
+
+	"notes-api/internal/domain"
+	"notes-api/internal/service"
+	notesv1 "notes-api/pkg/api/notes/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// NoteServer реализует gRPC сервис notes.v1.NoteService поверх NoteService
+type NoteServer struct {
+	notesv1.UnimplementedNoteServiceServer
+	service *service.NoteService
+	// requireVersion UpdateNote, PatchNote и DeleteNote без version отклоняются, как REST запросы без If-Match
+	requireVersion bool
+}
+
+// NewNoteServer создает реализацию gRPC сервиса заметок; requireVersion делает version обязательной при изменении
+func NewNoteServer(service *service.NoteService, requireVersion bool) *NoteServer {
+	return &NoteServer{service: service, requireVersion: requireVersion}
+}
+
+// NewServer создает gRPC сервер с сервисом заметок и server reflection
+// (grpcurl и другие клиенты получают описание сервиса с сервера)
+func NewServer(notes *service.NoteService, requireVersion bool) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryErrors),
+		grpc.ChainStreamInterceptor(streamErrors),
+	)
+	notesv1.RegisterNoteServiceServer(server, NewNoteServer(notes, requireVersion))
+	reflection.Register(server)
+	return server
+}
+
+// CreateNote создает заметку
+func (s *NoteServer) CreateNote(_ context.Context, req *notesv1.CreateNoteRequest) (*notesv1.Note, error) {
+	note, err := s.service.CreateNote(domain.CreateNoteRequest{
+		Title:      req.GetTitle(),
+		Content:    req.GetContent(),
+		Tags:       req.GetTags(),
+		NotebookID: req.NotebookId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return noteMessage(note), nil
+}
+
+// GetNote возвращает заметку по ID
+func (s *NoteServer) GetNote(_ context.Context, req *notesv1.GetNoteRequest) (*notesv1.Note, error) {
+	note, err := s.service.GetNoteByID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return noteMessage(note), nil
+}
+
+// ListNotes возвращает страницу заметок
+func (s *NoteServer) ListNotes(_ context.Context, req *notesv1.ListNotesRequest) (*notesv1.ListNotesResponse, error) {
+	filter, err := noteFilter(req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+	sort, err := domain.ParseSort(req.GetSort())
+	if err != nil {
+		return nil, err
+	}
+	limit, offset := pagination(req.GetLimit(), req.GetOffset())
+
+	notes, total, err := s.service.GetAllNotes(domain.NoteListOptions{
+		Filter: filter,
+		Sort:   sort,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &notesv1.ListNotesResponse{Notes: noteMessages(notes), Total: int32(total)}, nil
+}
+
+// StreamNotes передает подходящие заметки по одной по мере чтения из хранилища.
+// Отмена вызова клиентом прекращает чтение.
+func (s *NoteServer) StreamNotes(req *notesv1.StreamNotesRequest, stream grpc.ServerStreamingServer[notesv1.Note]) error {
+	filter, err := noteFilter(req.GetFilter())
+	if err != nil {
+		return err
+	}
+	sort, err := domain.ParseSort(req.GetSort())
+	if err != nil {
+		return err
+	}
+
+	for note, err := range s.service.StreamNotes(filter, sort) {
+		if err != nil {
+			return err
+		}
+		// Клиент отменил вызов или истек его срок: дальше читать хранилище незачем
+		if err := stream.Context().Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		if err := stream.Send(noteMessage(note)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateNote обновляет заметку
+func (s *NoteServer) UpdateNote(_ context.Context, req *notesv1.UpdateNoteRequest) (*notesv1.Note, error) {
+	if err := s.checkVersion(req.GetVersion()); err != nil {
+		return nil, err
+	}
+	note, err := s.service.UpdateNote(req.GetId(), domain.UpdateNoteRequest{
+		Title:   req.GetTitle(),
+		Content: req.GetContent(),
+		Tags:    tagNames(req.GetTags()),
+	}, req.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+	return noteMessage(note), nil
+}
+
+// PatchNote применяет патч к заметке
+func (s *NoteServer) PatchNote(_ context.Context, req *notesv1.PatchNoteRequest) (*notesv1.Note, error) {
+	format, ok := patchFormats[req.GetFormat()]
+	if !ok {
+		return nil, domain.NewFieldError("format", "format must be PATCH_FORMAT_MERGE_PATCH or PATCH_FORMAT_JSON_PATCH")
+	}
+
+	if err := s.checkVersion(req.GetVersion()); err != nil {
+		return nil, err
+	}
+	note, err := s.service.PatchNote(req.GetId(), format, req.GetPatch(), req.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+	return noteMessage(note), nil
+}
+
+// DeleteNote перемещает заметку в корзину
+func (s *NoteServer) DeleteNote(_ context.Context, req *notesv1.DeleteNoteRequest) (*emptypb.Empty, error) {
+	if err := s.checkVersion(req.GetVersion()); err != nil {
+		return nil, err
+	}
+	if err := s.service.DeleteNote(req.GetId(), req.GetVersion()); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// checkVersion отклоняет изменение без ожидаемой версии заметки, если версия обязательна
+func (s *NoteServer) checkVersion(version int64) error {
+	if s.requireVersion && version <= 0 {
+		return service.ErrVersionRequired
+	}
+	return nil
+}
+
+// BulkNotes выполняет пакет операций. Ошибки отдельных операций возвращаются в результатах,
+// в том числе в атомарном режиме, когда не применена ни одна операция.
+func (s *NoteServer) BulkNotes(_ context.Context, req *notesv1.BulkNotesRequest) (*notesv1.BulkNotesResponse, error) {
+	bulk := domain.BulkRequest{Atomic: req.GetAtomic(), Operations: make([]domain.BulkOperation, len(req.GetOperations()))}
+	for i, op := range req.GetOperations() {
+		bulk.Operations[i] = domain.BulkOperation{
+			Op:         bulkOps[op.GetOp()],
+			ID:         op.GetId(),
+			Version:    op.GetVersion(),
+			Title:      op.GetTitle(),
+			Content:    op.GetContent(),
+			Tags:       tagNames(op.GetTags()),
+			NotebookID: op.NotebookId,
+		}
+	}
+
+	results, err := s.service.BulkNotes(bulk)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &notesv1.BulkNotesResponse{Atomic: bulk.Atomic, Results: make([]*notesv1.BulkResult, len(results))}
+	for i, result := range results {
+		item := &notesv1.BulkResult{
+			Index: int32(result.Index),
+			Op:    bulkOpMessage(result.Op),
+			Id:    result.ID,
+			Note:  noteMessage(result.Note),
+		}
+		if result.Err != nil {
+			item.Error = statusError(result.Err).Proto()
+			response.Failed++
+		} else {
+			response.Succeeded++
+		}
+		response.Results[i] = item
+	}
+	return response, nil
+}
+
+// MoveNote перемещает заметку в блокнот
+func (s *NoteServer) MoveNote(_ context.Context, req *notesv1.MoveNoteRequest) (*notesv1.Note, error) {
+	note, err := s.service.MoveNote(req.GetId(), domain.MoveNoteRequest{NotebookID: req.NotebookId})
+	if err != nil {
+		return nil, err
+	}
+	return noteMessage(note), nil
+}
+
+// SearchNotes выполняет полнотекстовый поиск
+func (s *NoteServer) SearchNotes(_ context.Context, req *notesv1.SearchNotesRequest) (*notesv1.SearchNotesResponse, error) {
+	limit, offset := pagination(req.GetLimit(), req.GetOffset())
+	results, total, err := s.service.SearchNotes(req.GetQuery(), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &notesv1.SearchNotesResponse{Results: make([]*notesv1.SearchResult, len(results)), Total: int32(total)}
+	for i, result := range results {
+		response.Results[i] = &notesv1.SearchResult{
+			Note:    noteMessage(result.Note),
+			Rank:    result.Rank,
+			Snippet: result.Snippet,
+		}
+	}
+	return response, nil
+}
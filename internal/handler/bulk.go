@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"errors"
+
+	"notes-api/internal/domain"
+	"notes-api/internal/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// bulkResponse ответ на пакетный запрос
+type bulkResponse struct {
+	Atomic    bool             `json:"atomic"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Results   []bulkResultItem `json:"results"`
+}
+
+// bulkResultItem результат одной операции пакетного запроса
+type bulkResultItem struct {
+	Index  int          `json:"index"`
+	Op     string       `json:"op"`
+	Status int          `json:"status"`
+	ID     int64        `json:"id,omitempty"`
+	Note   *domain.Note `json:"note,omitempty"`
+	Error  string       `json:"error,omitempty"`
+}
+
+// BulkNotes обрабатывает пакетный запрос create/update/delete (POST /notes/bulk).
+// В атомарном режиме при ошибке любой операции не применяется ни одна и возвращается 422,
+// иначе ответ 200 содержит результат каждой операции со своим кодом статуса.
+func (h *NoteHandler) BulkNotes(c *fiber.Ctx) error {
+	var req domain.BulkRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errInvalidBody
+	}
+
+	results, err := h.service.BulkNotes(req)
+	if err != nil {
+		return err
+	}
+
+	response := bulkResponse{Atomic: req.Atomic, Results: make([]bulkResultItem, len(results))}
+	for i, result := range results {
+		item := bulkResultItem{
+			Index:  result.Index,
+			Op:     result.Op,
+			Status: bulkStatus(result),
+			ID:     result.ID,
+			Note:   result.Note,
+		}
+		if result.Err != nil {
+			item.Error = result.Err.Error()
+			response.Failed++
+		} else {
+			response.Succeeded++
+		}
+		response.Results[i] = item
+	}
+
+	status := fiber.StatusOK
+	if req.Atomic && response.Failed > 0 {
+		status = fiber.StatusUnprocessableEntity
+	}
+
+	return h.envelope.item(c, status, response)
+}
+
+// bulkStatus возвращает HTTP код результата операции пакетного запроса
+func bulkStatus(result domain.BulkResult) int {
+	switch {
+	case result.Err == nil && result.Op == domain.BulkCreate:
+		return fiber.StatusCreated
+	case result.Err == nil:
+		return fiber.StatusOK
+	case errors.Is(result.Err, repository.ErrBulkNotApplied):
+		return fiber.StatusFailedDependency
+	default:
+		return errorStatus(result.Err)
+	}
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"fmt"
+
+	"notes-api/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// cursorNotePage страница заметок в режиме курсора
+type cursorNotePage struct {
+	notes []*domain.Note
+	total int
+	next  *domain.NoteCursor // nil, если следующей страницы нет
+	prev  *domain.NoteCursor // nil, если предыдущей страницы нет
+}
+
+// cursorMode проверяет, запрошена ли пагинация курсором (?cursor=, пустой курсор - первая страница)
+func cursorMode(c *fiber.Ctx) bool {
+	return c.Context().QueryArgs().Has("cursor")
+}
+
+// fetchCursorPage получает страницу заметок относительно курсора из opts.
+// Запрашивается на одну заметку больше, чтобы узнать, есть ли следующая страница.
+func fetchCursorPage(opts domain.NoteListOptions, fetch func(domain.NoteListOptions) ([]*domain.Note, int, error)) (*cursorNotePage, error) {
+	limit := opts.Limit
+	opts.Limit = limit + 1
+
+	notes, total, err := fetch(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	backward := opts.Cursor != nil && opts.Cursor.Before
+	hasMore := len(notes) > limit
+	if hasMore {
+		// Лишняя заметка лежит дальше всех от курсора
+		if backward {
+			notes = notes[1:]
+		} else {
+			notes = notes[:limit]
+		}
+	}
+
+	page := &cursorNotePage{notes: notes, total: total}
+	if len(notes) == 0 {
+		return page, nil
+	}
+
+	first := domain.NewNoteCursor(notes[0], true)
+	last := domain.NewNoteCursor(notes[len(notes)-1], false)
+	if backward {
+		page.next = &last
+		if hasMore {
+			page.prev = &first
+		}
+	} else {
+		if hasMore {
+			page.next = &last
+		}
+		if opts.Cursor != nil {
+			page.prev = &first
+		}
+	}
+
+	return page, nil
+}
+
+// cursorMeta метаданные пагинации курсором (?cursor=); курсор nil, если страницы нет
+type cursorMeta struct {
+	Limit      int     `json:"limit"`
+	Total      int     `json:"total"`
+	NextCursor *string `json:"next_cursor"`
+	PrevCursor *string `json:"prev_cursor"`
+	HasNext    bool    `json:"hasNext"`
+	HasPrev    bool    `json:"hasPrev"`
+}
+
+// cursorResponse отправляет страницу с курсорами в meta и в заголовке Link (RFC 8288)
+func cursorResponse(c *fiber.Ctx, envelope Envelope, page *cursorNotePage, limit int) error {
+	meta := cursorMeta{
+		Limit:   limit,
+		Total:   page.total,
+		HasNext: page.next != nil,
+		HasPrev: page.prev != nil,
+	}
+
+	links := []string{cursorLink(c, "", "first")}
+	if page.next != nil {
+		cursor := page.next.Encode()
+		meta.NextCursor = &cursor
+		links = append(links, cursorLink(c, cursor, "next"))
+	}
+	if page.prev != nil {
+		cursor := page.prev.Encode()
+		meta.PrevCursor = &cursor
+		links = append(links, cursorLink(c, cursor, "prev"))
+	}
+	c.Append(fiber.HeaderLink, links...)
+
+	return envelope.cursorPage(c, page.notes, meta)
+}
+
+// cursorLink строит ссылку на страницу с курсором, сохраняя остальные параметры запроса
+func cursorLink(c *fiber.Ctx, cursor, rel string) string {
+	return fmt.Sprintf(`<%s>; rel="%s"`, queryURL(c, "cursor", cursor, "page"), rel)
+}
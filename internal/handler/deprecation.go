@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Deprecation возвращает middleware устаревшей версии API, подключаемое к группе ее маршрутов.
+// Ответ получает заголовки Deprecation (RFC 9745) с моментом, когда версия устарела,
+// Sunset (RFC 8594) с датой отключения и Link на тот же путь в версии successor (например /api/v2).
+// Нулевые даты не добавляют свои заголовки; если не задана ни одна, ответы не меняются.
+func Deprecation(deprecatedAt, sunset time.Time, successor string) fiber.Handler {
+	if deprecatedAt.IsZero() && sunset.IsZero() {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	var deprecation, sunsetValue string
+	if !deprecatedAt.IsZero() {
+		deprecation = fmt.Sprintf("@%d", deprecatedAt.Unix())
+	}
+	if !sunset.IsZero() {
+		sunsetValue = sunset.UTC().Format(http.TimeFormat)
+	}
+
+	return func(c *fiber.Ctx) error {
+		if deprecation != "" {
+			c.Set("Deprecation", deprecation)
+		}
+		if sunsetValue != "" {
+			c.Set("Sunset", sunsetValue)
+		}
+		path := strings.TrimPrefix(c.Path(), c.Route().Path)
+		c.Append(fiber.HeaderLink, fmt.Sprintf(`<%s%s>; rel="successor-version"`, successor, path))
+		return c.Next()
+	}
+}
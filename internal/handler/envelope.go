@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"net/url"
+	"reflect"
+	"strconv"
+
+	"notes-api/internal/openapi"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Envelope задает форму тела успешного ответа в версии API.
+// Обработчики одной версии создаются с одним Envelope и работают с теми же сервисами, что и другие версии.
+// Ошибки во всех версиях отдаются документом application/problem+json.
+type Envelope interface {
+	// item отправляет один объект
+	item(c *fiber.Ctx, status int, item any) error
+	// list отправляет список без пагинации
+	list(c *fiber.Ctx, items any) error
+	// page отправляет страницу списка при постраничной пагинации
+	page(c *fiber.Ctx, items any, meta pageMeta) error
+	// cursorPage отправляет страницу списка при пагинации курсором
+	cursorPage(c *fiber.Ctx, items any, meta cursorMeta) error
+
+	// Схемы тех же ответов для описания API
+	itemSchema(item openapi.Schema) openapi.Schema
+	listSchema(item openapi.Schema) openapi.Schema
+	pageSchema(g *openapi.Generator, item openapi.Schema) openapi.Schema
+	cursorPageSchema(g *openapi.Generator, item openapi.Schema) openapi.Schema
+}
+
+var (
+	// EnvelopeV1 ответы /api/v1: объект без обертки, списки в {data, meta}
+	EnvelopeV1 Envelope = v1Envelope{}
+	// EnvelopeV2 ответы /api/v2: любой результат в {data}, у страниц meta в snake_case и ссылки links
+	EnvelopeV2 Envelope = v2Envelope{}
+)
+
+// v1Envelope исходный формат ответов API
+type v1Envelope struct{}
+
+func (v1Envelope) item(c *fiber.Ctx, status int, item any) error {
+	return c.Status(status).JSON(item)
+}
+
+func (v1Envelope) list(c *fiber.Ctx, items any) error {
+	return c.JSON(dataResponse{Data: items})
+}
+
+func (v1Envelope) page(c *fiber.Ctx, items any, meta pageMeta) error {
+	return c.JSON(pageResponse{Data: items, Meta: meta})
+}
+
+func (v1Envelope) cursorPage(c *fiber.Ctx, items any, meta cursorMeta) error {
+	return c.JSON(pageResponse{Data: items, Meta: meta})
+}
+
+func (v1Envelope) itemSchema(item openapi.Schema) openapi.Schema {
+	return item
+}
+
+func (v1Envelope) listSchema(item openapi.Schema) openapi.Schema {
+	return dataSchema(arraySchema(item))
+}
+
+func (v1Envelope) pageSchema(g *openapi.Generator, item openapi.Schema) openapi.Schema {
+	return listSchema(item, g.Output(reflect.TypeFor[pageMeta]()))
+}
+
+func (v1Envelope) cursorPageSchema(g *openapi.Generator, item openapi.Schema) openapi.Schema {
+	return listSchema(item, g.Output(reflect.TypeFor[cursorMeta]()))
+}
+
+// v2Response тело ответа /api/v2
+type v2Response struct {
+	Data  any      `json:"data"`
+	Meta  any      `json:"meta,omitempty"`
+	Links *v2Links `json:"links,omitempty"`
+}
+
+// v2PageMeta метаданные постраничной пагинации в /api/v2
+type v2PageMeta struct {
+	Page       int  `json:"page"`
+	Limit      int  `json:"limit"`
+	Total      int  `json:"total"`
+	TotalPages int  `json:"total_pages"`
+	HasNext    bool `json:"has_next"`
+	HasPrev    bool `json:"has_prev"`
+}
+
+// v2CursorMeta метаданные пагинации курсором в /api/v2
+type v2CursorMeta struct {
+	Limit      int     `json:"limit"`
+	Total      int     `json:"total"`
+	NextCursor *string `json:"next_cursor"`
+	PrevCursor *string `json:"prev_cursor"`
+	HasNext    bool    `json:"has_next"`
+	HasPrev    bool    `json:"has_prev"`
+}
+
+// v2Links ссылки на соседние страницы списка; nil - такой страницы нет
+type v2Links struct {
+	Self  string  `json:"self"`
+	First string  `json:"first"`
+	Last  *string `json:"last,omitempty"` // Только при постраничной пагинации
+	Next  *string `json:"next"`
+	Prev  *string `json:"prev"`
+}
+
+// v2Envelope формат ответов /api/v2
+type v2Envelope struct{}
+
+func (v2Envelope) item(c *fiber.Ctx, status int, item any) error {
+	return c.Status(status).JSON(v2Response{Data: item})
+}
+
+func (v2Envelope) list(c *fiber.Ctx, items any) error {
+	return c.JSON(v2Response{Data: items})
+}
+
+func (v2Envelope) page(c *fiber.Ctx, items any, meta pageMeta) error {
+	pageURL := func(page int) string {
+		return queryURL(c, "page", strconv.Itoa(page), "cursor")
+	}
+
+	last := max(meta.TotalPages, 1)
+	lastURL := pageURL(last)
+	links := &v2Links{
+		Self:  pageURL(meta.Page),
+		First: pageURL(1),
+		Last:  &lastURL,
+		Next:  optionalLink(meta.HasNext, pageURL(meta.Page+1)),
+		Prev:  optionalLink(meta.HasPrev, pageURL(min(meta.Page-1, last))),
+	}
+
+	return c.JSON(v2Response{
+		Data: items,
+		Meta: v2PageMeta{
+			Page:       meta.Page,
+			Limit:      meta.Limit,
+			Total:      meta.Total,
+			TotalPages: meta.TotalPages,
+			HasNext:    meta.HasNext,
+			HasPrev:    meta.HasPrev,
+		},
+		Links: links,
+	})
+}
+
+func (v2Envelope) cursorPage(c *fiber.Ctx, items any, meta cursorMeta) error {
+	cursorURL := func(cursor *string) *string {
+		if cursor == nil {
+			return nil
+		}
+		link := queryURL(c, "cursor", *cursor, "page")
+		return &link
+	}
+
+	return c.JSON(v2Response{
+		Data: items,
+		Meta: v2CursorMeta(meta),
+		Links: &v2Links{
+			Self:  queryURL(c, "cursor", c.Query("cursor"), "page"),
+			First: queryURL(c, "cursor", "", "page"),
+			Next:  cursorURL(meta.NextCursor),
+			Prev:  cursorURL(meta.PrevCursor),
+		},
+	})
+}
+
+func (v2Envelope) itemSchema(item openapi.Schema) openapi.Schema {
+	return dataSchema(item)
+}
+
+func (v2Envelope) listSchema(item openapi.Schema) openapi.Schema {
+	return dataSchema(arraySchema(item))
+}
+
+func (v2Envelope) pageSchema(g *openapi.Generator, item openapi.Schema) openapi.Schema {
+	schema := listSchema(item, g.Output(reflect.TypeFor[v2PageMeta]()))
+	return withLinks(g, schema)
+}
+
+func (v2Envelope) cursorPageSchema(g *openapi.Generator, item openapi.Schema) openapi.Schema {
+	schema := listSchema(item, g.Output(reflect.TypeFor[v2CursorMeta]()))
+	return withLinks(g, schema)
+}
+
+// withLinks добавляет в схему страницы обязательное поле links
+func withLinks(g *openapi.Generator, schema openapi.Schema) openapi.Schema {
+	schema["properties"].(map[string]openapi.Schema)["links"] = g.Output(reflect.TypeFor[v2Links]())
+	schema["required"] = append(schema["required"].([]string), "links")
+	return schema
+}
+
+// optionalLink возвращает ссылку, если страница существует, иначе nil
+func optionalLink(exists bool, link string) *string {
+	if !exists {
+		return nil
+	}
+	return &link
+}
+
+// queryURL строит ссылку на текущий путь с параметром key=value, сохраняя остальные параметры запроса
+// и удаляя параметр drop (page и cursor взаимоисключающие)
+func queryURL(c *fiber.Ctx, key, value, drop string) string {
+	params, _ := url.ParseQuery(string(c.Context().QueryArgs().QueryString()))
+	params.Del(drop)
+	params.Set(key, value)
+	return c.BaseURL() + c.Path() + "?" + params.Encode()
+}
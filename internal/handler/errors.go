@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"notes-api/internal/domain"
+	"notes-api/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MIMEApplicationProblemJSON тип содержимого документа ошибки (RFC 7807)
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// Problem документ ошибки по RFC 7807.
+// Errors, Position и Token - расширения для ошибок проверки и разбора запроса.
+type Problem struct {
+	Type     string              `json:"type"`
+	Title    string              `json:"title"`
+	Status   int                 `json:"status"`
+	Detail   string              `json:"detail,omitempty"`
+	Instance string              `json:"instance,omitempty"`
+	Errors   []domain.FieldError `json:"errors,omitempty"`
+	Position *int                `json:"position,omitempty"`
+	Token    string              `json:"token,omitempty"`
+}
+
+// ErrorHandler отправляет ошибку обработчика документом application/problem+json.
+// Код ответа выбирается по типу ошибки; детали внутренних ошибок не раскрываются, а пишутся в лог.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	problem := newProblem(err)
+	problem.Instance = c.Path()
+	if problem.Status >= fiber.StatusInternalServerError {
+		log.Printf("%s %s: %v", c.Method(), c.Path(), err)
+	}
+
+	body, err := json.Marshal(problem)
+	if err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderContentType, MIMEApplicationProblemJSON)
+	return c.Status(problem.Status).Send(body)
+}
+
+// newProblem строит документ ошибки по ее типу
+func newProblem(err error) Problem {
+	status := errorStatus(err)
+	problem := Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: err.Error(),
+	}
+
+	var validationErr *domain.ValidationError
+	var queryErr *service.QueryError
+	switch {
+	case errors.As(err, &queryErr):
+		problem.Position = &queryErr.Position
+		problem.Token = queryErr.Token
+	case errors.As(err, &validationErr):
+		problem.Errors = validationErr.Fields
+	case status == fiber.StatusServiceUnavailable:
+		problem.Detail = "storage is temporarily unavailable, retry later"
+	case status == fiber.StatusInternalServerError:
+		problem.Detail = "internal server error"
+	}
+
+	return problem
+}
+
+// errorStatus возвращает HTTP код для ошибки
+func errorStatus(err error) int {
+	var fiberErr *fiber.Error
+	var queryErr *service.QueryError
+	var validationErr *domain.ValidationError
+	var notFoundErr *domain.NotFoundError
+	var conflictErr *domain.ConflictError
+	var preconditionErr *domain.PreconditionError
+	var requiredErr *domain.PreconditionRequiredError
+	var unavailableErr *domain.UnavailableError
+
+	switch {
+	case errors.As(err, &fiberErr):
+		return fiberErr.Code
+	case errors.As(err, &queryErr), errors.As(err, &validationErr):
+		return fiber.StatusBadRequest
+	case errors.As(err, &notFoundErr):
+		return fiber.StatusNotFound
+	case errors.As(err, &conflictErr):
+		return fiber.StatusConflict
+	case errors.As(err, &preconditionErr):
+		return fiber.StatusPreconditionFailed
+	case errors.As(err, &requiredErr):
+		return fiber.StatusPreconditionRequired
+	case errors.Is(err, service.ErrIdempotencyKeyReused):
+		return fiber.StatusUnprocessableEntity
+	case errors.As(err, &unavailableErr):
+		return fiber.StatusServiceUnavailable
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// errInvalidBody тело запроса не удалось разобрать
+var errInvalidBody = fiber.NewError(fiber.StatusBadRequest, "invalid request body")
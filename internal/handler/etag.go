@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"notes-api/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// errPreconditionFailed If-Match не может совпасть ни с одной версией заметки
+var errPreconditionFailed = domain.NewPreconditionError("note has been modified, reload it and retry")
+
+// noteETag возвращает сильный ETag заметки на основе ее версии
+func noteETag(note *domain.Note) string {
+	return fmt.Sprintf(`"%d"`, note.Version)
+}
+
+// noteJSON отправляет заметку вместе с ее ETag
+func noteJSON(c *fiber.Ctx, envelope Envelope, note *domain.Note) error {
+	c.Set(fiber.HeaderETag, noteETag(note))
+	return envelope.item(c, fiber.StatusOK, note)
+}
+
+// notesPageETag возвращает слабый ETag страницы списка: он меняется вместе с составом страницы,
+// версией любой заметки на ней, общим количеством заметок или представлением (mediaType)
+func notesPageETag(notes []*domain.Note, total int, mediaType string) string {
+	hash := fnv.New64a()
+	fmt.Fprintf(hash, "%s;%d", mediaType, total)
+	for _, note := range notes {
+		fmt.Fprintf(hash, ";%d:%d", note.ID, note.Version)
+	}
+	return fmt.Sprintf(`W/"%x"`, hash.Sum64())
+}
+
+// notesLastModified возвращает время последнего изменения заметок страницы
+func notesLastModified(notes []*domain.Note) time.Time {
+	var modified time.Time
+	for _, note := range notes {
+		if note.UpdatedAt.After(modified) {
+			modified = note.UpdatedAt
+		}
+	}
+	return modified
+}
+
+// notModified выставляет ETag и Last-Modified ответа и проверяет условный GET.
+// Если у клиента актуальная копия, отправляет 304 Not Modified и возвращает true.
+// If-None-Match имеет приоритет над If-Modified-Since (RFC 9110, раздел 13.2.2).
+func notModified(c *fiber.Ctx, etag string, modified time.Time) bool {
+	c.Set(fiber.HeaderETag, etag)
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	if !modified.IsZero() {
+		c.Set(fiber.HeaderLastModified, modified.UTC().Format(http.TimeFormat))
+	}
+
+	fresh := false
+	if noneMatch := c.Get(fiber.HeaderIfNoneMatch); noneMatch != "" {
+		fresh = etagMatches(noneMatch, etag)
+	} else if modifiedSince := c.Get(fiber.HeaderIfModifiedSince); modifiedSince != "" && !modified.IsZero() {
+		// Last-Modified передается с точностью до секунды
+		since, err := http.ParseTime(modifiedSince)
+		fresh = err == nil && !modified.Truncate(time.Second).After(since)
+	}
+
+	if fresh {
+		c.Status(fiber.StatusNotModified)
+	}
+	return fresh
+}
+
+// etagMatches проверяет, совпадает ли ETag с одним из тегов If-None-Match при слабом сравнении
+func etagMatches(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIfMatch возвращает версию заметки из заголовка If-Match; 0 - заголовка нет или "*".
+// Подходит ETag любого представления заметки ("3", "3.md").
+// Слабые и некорректные ETag не могут совпасть при строгом сравнении и дают errPreconditionFailed.
+func parseIfMatch(c *fiber.Ctx) (int64, error) {
+	value := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+	if strings.Contains(value, ",") {
+		return 0, fiber.NewError(fiber.StatusBadRequest, "If-Match with several entity tags is not supported")
+	}
+
+	tag, _, _ := strings.Cut(strings.Trim(value, `"`), ".")
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil || version <= 0 || !strings.HasPrefix(value, `"`) || !strings.HasSuffix(value, `"`) {
+		return 0, errPreconditionFailed
+	}
+	return version, nil
+}
+
+// RequireIfMatch возвращает middleware, которое отклоняет изменение заметки без If-Match
+// с кодом 428 Precondition Required. Если required == false, запросы пропускаются.
+func RequireIfMatch(required bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if required && c.Get(fiber.HeaderIfMatch) == "" {
+			return fiber.NewError(fiber.StatusPreconditionRequired, "If-Match header is required, send the note ETag")
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"notes-api/internal/domain"
+	"notes-api/internal/service"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// MIMEEventStream тип ответа Server-Sent Events
+	MIMEEventStream = "text/event-stream"
+
+	// eventFeedReset сообщает клиенту, что часть событий потеряна и заметки нужно загрузить заново
+	eventFeedReset = "feed.reset"
+
+	// eventsHeartbeat как часто в тихий поток отправляется пустое сообщение,
+	// чтобы прокси не закрыли соединение, а сервер заметил отключившегося клиента
+	eventsHeartbeat = 15 * time.Second
+
+	// eventsRetry через сколько миллисекунд EventSource переподключается после обрыва
+	eventsRetry = 3000
+)
+
+// EventsHandler отдает поток событий изменения заметок через SSE и WebSocket
+type EventsHandler struct {
+	broker    *service.EventBroker
+	websocket fiber.Handler
+}
+
+// NewEventsHandler создает обработчик потока событий.
+// События одинаковы во всех версиях API, поэтому формат ответов не нужен.
+func NewEventsHandler(broker *service.EventBroker) *EventsHandler {
+	h := &EventsHandler{broker: broker}
+	h.websocket = websocket.New(h.serveWebSocket)
+	return h
+}
+
+// eventsRequest параметры подписки, разобранные до начала потока
+type eventsRequest struct {
+	lastID int64
+	filter func(domain.NoteEvent) bool
+}
+
+// eventsRequestKey ключ Locals, через который параметры подписки передаются в WebSocket соединение
+const eventsRequestKey = "eventsRequest"
+
+// parseEventsRequest разбирает номер последнего полученного события (заголовок Last-Event-ID
+// или ?last_event_id= для клиентов, которые не могут задать заголовок) и фильтр ?ids=
+func parseEventsRequest(c *fiber.Ctx) (eventsRequest, error) {
+	var req eventsRequest
+
+	value := c.Get("Last-Event-ID")
+	if value == "" {
+		value = c.Query("last_event_id")
+	}
+	if value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || id < 0 {
+			return req, domain.NewFieldError("last_event_id", fmt.Sprintf("invalid event ID %q", value))
+		}
+		req.lastID = id
+	}
+
+	if value := c.Query("ids"); value != "" {
+		ids, err := domain.ParseIDs(value)
+		if err != nil {
+			return req, err
+		}
+		wanted := make(map[int64]bool, len(ids))
+		for _, id := range ids {
+			wanted[id] = true
+		}
+		req.filter = func(event domain.NoteEvent) bool { return wanted[event.NoteID] }
+	}
+
+	return req, nil
+}
+
+// Stream отдает события в формате Server-Sent Events. Сначала идут события из журнала после
+// Last-Event-ID, затем новые. Если нужные события уже вытеснены из журнала, первым приходит
+// событие feed.reset.
+func (h *EventsHandler) Stream(c *fiber.Ctx) error {
+	req, err := parseEventsRequest(c)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, MIMEEventStream)
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	// nginx не должен копить поток в буфере
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		sub := h.broker.Subscribe(req.lastID, req.filter)
+		defer sub.Unsubscribe()
+
+		fmt.Fprintf(w, "retry: %d\n\n", eventsRetry)
+		if sub.Gap {
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: {}\n\n", sub.ResumeID, eventFeedReset)
+		}
+		for _, event := range sub.Backlog {
+			if err := writeSSEEvent(w, event); err != nil {
+				log.Printf("Streaming events failed: %v", err)
+				return
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		heartbeat := time.NewTicker(eventsHeartbeat)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case event, ok := <-sub.Events:
+				if !ok {
+					return
+				}
+				if err := writeSSEEvent(w, event); err != nil {
+					log.Printf("Streaming events failed: %v", err)
+					return
+				}
+			case <-heartbeat.C:
+				w.WriteString(": ping\n\n")
+			}
+			// Ошибка записи - клиент отключился
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+	return nil
+}
+
+// writeSSEEvent записывает событие в формате SSE; id позволяет переподключиться с Last-Event-ID
+func writeSSEEvent(w *bufio.Writer, event domain.NoteEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+// WebSocket отдает те же события, что Stream, JSON сообщениями через WebSocket.
+// Номер последнего полученного события передается в ?last_event_id=.
+func (h *EventsHandler) WebSocket(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return fiber.ErrUpgradeRequired
+	}
+	// Параметры проверяются до переключения протокола, чтобы ошибка пришла обычным ответом
+	req, err := parseEventsRequest(c)
+	if err != nil {
+		return err
+	}
+	c.Locals(eventsRequestKey, req)
+	return h.websocket(c)
+}
+
+// feedResetMessage сообщение WebSocket о потерянных событиях
+type feedResetMessage struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"`
+}
+
+func (h *EventsHandler) serveWebSocket(conn *websocket.Conn) {
+	req, _ := conn.Locals(eventsRequestKey).(eventsRequest)
+	sub := h.broker.Subscribe(req.lastID, req.filter)
+	defer sub.Unsubscribe()
+
+	// Клиент ничего не присылает; чтение нужно, чтобы обработать ping/close и заметить отключение
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	if sub.Gap {
+		if err := conn.WriteJSON(feedResetMessage{ID: sub.ResumeID, Type: eventFeedReset}); err != nil {
+			return
+		}
+	}
+	for _, event := range sub.Backlog {
+		if err := conn.WriteJSON(event); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(eventsHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case event, ok := <-sub.Events:
+			if !ok {
+				// Сервер останавливается или клиент не успевал забирать события
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""))
+				return
+			}
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
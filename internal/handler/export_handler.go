@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"time"
+
+	"notes-api/internal/format"
+	"notes-api/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ExportHandler обрабатывает выгрузку всех заметок
+type ExportHandler struct {
+	service *service.ExportService
+}
+
+// NewExportHandler создает новый обработчик выгрузки.
+// Выгрузка одинакова во всех версиях API, поэтому формат ответов не нужен.
+func NewExportHandler(service *service.ExportService) *ExportHandler {
+	return &ExportHandler{service: service}
+}
+
+// Export отдает все заметки файлом в формате ?format= (markdown-zip, json, ndjson).
+// Тело пишется по мере чтения заметок из репозитория; ошибка после начала ответа
+// только записывается в лог и обрывает выгрузку.
+func (h *ExportHandler) Export(c *fiber.Ctx) error {
+	exportFormat, err := format.ParseExportFormat(c.Query("format"))
+	if err != nil {
+		return err
+	}
+
+	filename := fmt.Sprintf("notes-%s%s", time.Now().UTC().Format("20060102-150405"), exportFormat.Extension())
+	c.Attachment(filename)
+	c.Set(fiber.HeaderContentType, exportFormat.ContentType())
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := h.service.Export(w, exportFormat); err != nil {
+			log.Printf("Export failed: %v", err)
+			return
+		}
+		if err := w.Flush(); err != nil {
+			log.Printf("Export failed: %v", err)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"encoding/json"
+	"strings"
+
+	"notes-api/internal/gql"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MIMEGraphQL тело запроса - текст запроса GraphQL
+const MIMEGraphQL = "application/graphql"
+
+// graphiQLPage страница GraphiQL; файлы загружаются с CDN, запросы отправляются на адрес страницы
+const graphiQLPage = `<!doctype html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Notes API - GraphiQL</title>
+  <link rel="stylesheet" href="https://unpkg.com/graphiql@3/graphiql.min.css">
+</head>
+<body style="margin: 0">
+  <div id="graphiql" style="height: 100vh"></div>
+  <script crossorigin src="https://unpkg.com/react@18/umd/react.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/react-dom@18/umd/react-dom.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/graphiql@3/graphiql.min.js"></script>
+  <script>
+    const fetcher = GraphiQL.createFetcher({ url: window.location.pathname });
+    ReactDOM.createRoot(document.getElementById('graphiql')).render(
+      React.createElement(GraphiQL, { fetcher: fetcher, defaultEditorToolsVisibility: true })
+    );
+  </script>
+</body>
+</html>
+`
+
+// GraphQLHandler обрабатывает запросы GraphQL
+type GraphQLHandler struct {
+	executor *gql.Executor
+	graphiQL bool
+}
+
+// NewGraphQLHandler создает обработчик GraphQL; graphiQL включает страницу GraphiQL (режим разработки)
+func NewGraphQLHandler(executor *gql.Executor, graphiQL bool) *GraphQLHandler {
+	return &GraphQLHandler{executor: executor, graphiQL: graphiQL}
+}
+
+// Query выполняет запрос GraphQL. POST принимает JSON {query, variables, operationName}
+// или текст запроса (application/graphql), GET - параметры query, variables и operationName
+// и выполняет только запросы без мутаций. Ответ - результат GraphQL со статусом 200,
+// ошибки выполнения передаются в поле errors.
+func (h *GraphQLHandler) Query(c *fiber.Ctx) error {
+	var req gql.Request
+
+	switch c.Method() {
+	case fiber.MethodGet:
+		if h.graphiQL && c.Query("query") == "" && c.Accepts(fiber.MIMEApplicationJSON, fiber.MIMETextHTML) == fiber.MIMETextHTML {
+			c.Type("html")
+			return c.SendString(graphiQLPage)
+		}
+
+		req.Query = c.Query("query")
+		req.OperationName = c.Query("operationName")
+		req.ReadOnly = true
+		if variables := c.Query("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "variables must be a JSON object")
+			}
+		}
+	default:
+		if strings.HasPrefix(c.Get(fiber.HeaderContentType), MIMEGraphQL) {
+			req.Query = string(c.Body())
+		} else if err := c.BodyParser(&req); err != nil {
+			return errInvalidBody
+		}
+	}
+
+	if strings.TrimSpace(req.Query) == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "GraphQL query is required")
+	}
+
+	return c.JSON(h.executor.Execute(c.UserContext(), req))
+}
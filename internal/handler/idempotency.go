@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"strings"
+
+	"notes-api/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxIdempotencyKeyLength максимальная длина заголовка Idempotency-Key
+const maxIdempotencyKeyLength = 255
+
+// Idempotency возвращает middleware для заголовка Idempotency-Key.
+// Ответ на запрос с ключом сохраняется, и повтор запроса с тем же ключом и телом получает
+// исходный ответ с заголовком Idempotent-Replayed: true. Тот же ключ с другим телом дает 422,
+// ключ запроса, который еще выполняется, - 409. Ответы 5xx не сохраняются.
+func Idempotency(s *service.IdempotencyService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Значение заголовка действительно только до конца запроса, а ключ хранится дольше
+		key := strings.Clone(c.Get("Idempotency-Key"))
+		if key == "" || !s.Enabled() {
+			return c.Next()
+		}
+		if len(key) > maxIdempotencyKeyLength || !printableASCII(key) {
+			return fiber.NewError(fiber.StatusBadRequest, "Idempotency-Key must be 1-255 printable ASCII characters")
+		}
+
+		hash := sha256.New()
+		hash.Write([]byte(c.Method() + " " + c.Path() + "\n"))
+		hash.Write(c.Body())
+		requestHash := hex.EncodeToString(hash.Sum(nil))
+
+		saved, err := s.Begin(key, requestHash)
+		if err != nil {
+			return err
+		}
+
+		// Повтор запроса: отдаем сохраненный ответ
+		if saved != nil {
+			c.Set("Idempotent-Replayed", "true")
+			if saved.ETag != "" {
+				c.Set(fiber.HeaderETag, saved.ETag)
+			}
+			c.Set(fiber.HeaderContentType, saved.ContentType)
+			return c.Status(saved.StatusCode).Send(saved.Body)
+		}
+
+		// Ошибку обработчика сразу превращаем в ответ, чтобы сохранить его, как и успешный
+		if err := c.Next(); err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				if releaseErr := s.Release(key); releaseErr != nil {
+					log.Printf("Failed to release idempotency key: %v", releaseErr)
+				}
+				return err
+			}
+		}
+
+		// Ответ с ошибкой сервера не сохраняем, чтобы запрос можно было повторить
+		response := c.Response()
+		status := response.StatusCode()
+		if status >= fiber.StatusInternalServerError {
+			if err := s.Release(key); err != nil {
+				log.Printf("Failed to release idempotency key: %v", err)
+			}
+			return nil
+		}
+
+		body := append([]byte(nil), response.Body()...)
+		contentType := string(response.Header.ContentType())
+		etag := string(response.Header.Peek(fiber.HeaderETag))
+		if err := s.Complete(key, requestHash, status, contentType, etag, body); err != nil {
+			log.Printf("Failed to save idempotent response: %v", err)
+		}
+		return nil
+	}
+}
+
+// printableASCII проверяет, что строка состоит только из печатных ASCII символов
+func printableASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"io"
+	"strings"
+
+	"notes-api/internal/format"
+	"notes-api/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ImportHandler обрабатывает импорт заметок из других приложений
+type ImportHandler struct {
+	service  *service.ImportService
+	envelope Envelope
+}
+
+// NewImportHandler создает новый обработчик импорта с форматом ответов версии API
+func NewImportHandler(service *service.ImportService, envelope Envelope) *ImportHandler {
+	return &ImportHandler{service: service, envelope: envelope}
+}
+
+// Import обрабатывает импорт файла: тело запроса целиком или поле file формы multipart/form-data.
+// ?format= задает формат (по умолчанию определяется по содержимому), ?dry_run=true - только отчет без сохранения.
+func (h *ImportHandler) Import(c *fiber.Ctx) error {
+	importFormat, err := format.ParseImportFormat(c.Query("format"))
+	if err != nil {
+		return err
+	}
+
+	data, err := importFile(c)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "import file is empty")
+	}
+
+	report, err := h.service.Import(data, importFormat, c.QueryBool("dry_run"))
+	if err != nil {
+		return err
+	}
+
+	return h.envelope.item(c, fiber.StatusOK, report)
+}
+
+// importFile возвращает содержимое файла импорта
+func importFile(c *fiber.Ctx) ([]byte, error) {
+	if !strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		return c.Body(), nil
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "multipart form must contain the import file in field 'file'")
+	}
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return io.ReadAll(file)
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"mime"
+	"strconv"
+	"strings"
+
+	"notes-api/internal/domain"
+	"notes-api/internal/format"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// noteMediaTypes представления заметки; первое отдается, если Accept не задан
+var noteMediaTypes = []string{fiber.MIMEApplicationJSON, format.MIMEMarkdown, format.MIMEText}
+
+// noteListMediaTypes представления списка заметок
+var noteListMediaTypes = []string{fiber.MIMEApplicationJSON, format.MIMECSV, format.MIMEYAML, "text/yaml"}
+
+// negotiate выбирает представление ответа по заголовку Accept; если ни одно не подходит - 406
+func negotiate(c *fiber.Ctx, offers ...string) (string, error) {
+	c.Vary(fiber.HeaderAccept)
+	mediaType := c.Accepts(offers...)
+	if mediaType == "" {
+		return "", fiber.NewError(fiber.StatusNotAcceptable, "acceptable media types: "+strings.Join(offers, ", "))
+	}
+	return mediaType, nil
+}
+
+// representationETag возвращает ETag заметки в представлении mediaType:
+// у разных представлений одной версии сильные ETag должны различаться
+func representationETag(note *domain.Note, mediaType string) string {
+	switch mediaType {
+	case format.MIMEMarkdown:
+		return `"` + strconv.FormatInt(note.Version, 10) + `.md"`
+	case format.MIMEText:
+		return `"` + strconv.FormatInt(note.Version, 10) + `.txt"`
+	default:
+		return noteETag(note)
+	}
+}
+
+// sendNote отправляет заметку с ETag в представлении mediaType; JSON - в формате версии API
+func sendNote(c *fiber.Ctx, envelope Envelope, status int, note *domain.Note, mediaType string) error {
+	c.Set(fiber.HeaderETag, representationETag(note, mediaType))
+
+	switch mediaType {
+	case format.MIMEMarkdown:
+		body, err := format.EncodeMarkdown(note)
+		if err != nil {
+			return err
+		}
+		c.Set(fiber.HeaderContentType, format.MIMEMarkdown+"; charset=utf-8")
+		return c.Status(status).Send(body)
+	case format.MIMEText:
+		c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+		return c.Status(status).Send(format.EncodeText(note))
+	default:
+		return envelope.item(c, status, note)
+	}
+}
+
+// parseCreateNoteRequest разбирает тело запроса создания заметки по Content-Type:
+// JSON, Markdown с необязательным YAML front matter (title, tags, notebook_id) или простой текст,
+// у которого заголовком становится первая строка
+func parseCreateNoteRequest(c *fiber.Ctx) (domain.CreateNoteRequest, error) {
+	var req domain.CreateNoteRequest
+
+	mediaType, _, _ := mime.ParseMediaType(c.Get(fiber.HeaderContentType))
+	switch mediaType {
+	case format.MIMEMarkdown:
+		meta, content, err := format.ParseMarkdown(c.Body())
+		if err != nil {
+			return req, err
+		}
+		req = domain.CreateNoteRequest{Title: meta.Title, Content: content, Tags: meta.Tags, NotebookID: meta.NotebookID}
+	case format.MIMEText:
+		req.Title, req.Content = format.ParseText(c.Body())
+	default:
+		if err := c.BodyParser(&req); err != nil {
+			return req, errInvalidBody
+		}
+	}
+
+	return req, nil
+}
+
+// notesListEnvelope отдает страницы заметок в CSV или YAML; число заметок по фильтру - в X-Total-Count,
+// ссылки на соседние страницы при пагинации курсором - в заголовке Link. Остальные ответы - как у Envelope.
+type notesListEnvelope struct {
+	Envelope
+	mediaType string
+}
+
+// listEnvelope возвращает формат ответа со списком заметок для представления mediaType
+func listEnvelope(envelope Envelope, mediaType string) Envelope {
+	if mediaType == fiber.MIMEApplicationJSON {
+		return envelope
+	}
+	return notesListEnvelope{Envelope: envelope, mediaType: mediaType}
+}
+
+func (e notesListEnvelope) page(c *fiber.Ctx, items any, meta pageMeta) error {
+	return e.send(c, items, meta.Total)
+}
+
+func (e notesListEnvelope) cursorPage(c *fiber.Ctx, items any, meta cursorMeta) error {
+	return e.send(c, items, meta.Total)
+}
+
+// send пишет заметки в представлении e.mediaType
+func (e notesListEnvelope) send(c *fiber.Ctx, items any, total int) error {
+	notes, ok := items.([]*domain.Note)
+	if !ok {
+		return e.Envelope.list(c, items)
+	}
+	c.Set("X-Total-Count", strconv.Itoa(total))
+
+	if e.mediaType == format.MIMECSV {
+		c.Set(fiber.HeaderContentType, format.MIMECSV+"; charset=utf-8; header=present")
+		return format.WriteCSV(c, notes)
+	}
+	c.Set(fiber.HeaderContentType, e.mediaType+"; charset=utf-8")
+	return format.WriteYAML(c, notes)
+}
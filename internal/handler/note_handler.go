@@ -1,186 +1,484 @@
 package handler
 
 import (
+	"bufio"
+	"encoding/json"
+	"log"
+	"mime"
 	"strconv"
+	"strings"
+	"time"
 
 	"notes-api/internal/domain"
+	"notes-api/internal/format"
 	"notes-api/internal/service"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// streamFlushInterval через сколько заметок StreamNotes отправляет накопленные данные клиенту
+const streamFlushInterval = 100
+
 // NoteHandler обрабатывает HTTP запросы для заметок
 type NoteHandler struct {
-	service *service.NoteService
+	service  *service.NoteService
+	envelope Envelope
 }
 
-// NewNoteHandler создает новый обработчик
-func NewNoteHandler(service *service.NoteService) *NoteHandler {
-	return &NoteHandler{service: service}
+// NewNoteHandler создает новый обработчик с форматом ответов версии API
+func NewNoteHandler(service *service.NoteService, envelope Envelope) *NoteHandler {
+	return &NoteHandler{service: service, envelope: envelope}
 }
 
 // CreateNote обрабатывает создание заметки
 func (h *NoteHandler) CreateNote(c *fiber.Ctx) error {
-	var req domain.CreateNoteRequest
+	// Заметку можно прислать в JSON, Markdown или простым текстом
+	req, err := parseCreateNoteRequest(c)
+	if err != nil {
+		return err
+	}
 
-	// Парсим JSON тело запроса
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "invalid request body",
-		})
+	// Ответ отдаем в представлении из Accept
+	mediaType, err := negotiate(c, noteMediaTypes...)
+	if err != nil {
+		return err
 	}
 
 	// Создаем заметку через сервис
 	note, err := h.service.CreateNote(req)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	// Возвращаем ответ
-	return c.Status(fiber.StatusCreated).JSON(note)
+	return sendNote(c, h.envelope, fiber.StatusCreated, note, mediaType)
 }
 
 // GetAllNotes обрабатывает получение всех заметок с пагинацией
 func (h *NoteHandler) GetAllNotes(c *fiber.Ctx) error {
-	// Получаем параметры пагинации из query string
-	page, _ := strconv.Atoi(c.Query("page", "1"))
-	limit, _ := strconv.Atoi(c.Query("limit", "10"))
-
-	// Валидация параметров
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 {
-		limit = 10
+	// Получаем параметры фильтрации, сортировки и пагинации из query string
+	opts, page, err := parseNoteListOptions(c)
+	if err != nil {
+		return err
 	}
-	if limit > 100 {
-		limit = 100 // Ограничиваем максимальный лимит
+
+	// Список отдаем в JSON, CSV или YAML
+	mediaType, err := negotiate(c, noteListMediaTypes...)
+	if err != nil {
+		return err
 	}
+	envelope := listEnvelope(h.envelope, mediaType)
 
-	// Вычисляем offset
-	offset := (page - 1) * limit
+	// В режиме курсора отдаем страницу относительно курсора
+	if cursorMode(c) {
+		page, err := fetchCursorPage(opts, h.service.GetAllNotes)
+		if err != nil {
+			return err
+		}
+		if notModified(c, notesPageETag(page.notes, page.total, mediaType), notesLastModified(page.notes)) {
+			return nil
+		}
+		return cursorResponse(c, envelope, page, opts.Limit)
+	}
 
 	// Получаем заметки через сервис с пагинацией
-	notes, total, err := h.service.GetAllNotes(limit, offset)
+	notes, total, err := h.service.GetAllNotes(opts)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "internal server error",
-		})
+		return err
 	}
 
-	// Рассчитываем метаданные пагинации
-	totalPages := 0
-	if total > 0 {
-		totalPages = (total + limit - 1) / limit // ceil деление
+	// Клиент с актуальной копией страницы получает 304 без тела
+	if notModified(c, notesPageETag(notes, total, mediaType), notesLastModified(notes)) {
+		return nil
 	}
 
 	// Возвращаем ответ с пагинацией
-	return c.JSON(fiber.Map{
-		"data": notes,
-		"meta": fiber.Map{
-			"page":       page,
-			"limit":      limit,
-			"total":      total,
-			"totalPages": totalPages,
-			"hasNext":    page < totalPages,
-			"hasPrev":    page > 1,
-		},
+	return paginatedResponse(c, envelope, notes, total, page, opts.Limit)
+}
+
+// StreamNotes отдает все заметки по фильтру и сортировке списка в формате NDJSON (заметка на строку)
+// без пагинации. Заметки пишутся в ответ по мере чтения из репозитория; ошибка после начала ответа
+// только записывается в лог и обрывает поток.
+func (h *NoteHandler) StreamNotes(c *fiber.Ctx) error {
+	filter, err := parseNoteFilter(c)
+	if err != nil {
+		return err
+	}
+	sort, err := domain.ParseSort(c.Query("sort"))
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, format.MIMENDJSON)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		encoder := json.NewEncoder(w)
+		count := 0
+		for note, err := range h.service.StreamNotes(filter, sort) {
+			if err == nil {
+				err = encoder.Encode(note)
+			}
+			// Клиент получает заметки пачками, не дожидаясь конца перебора
+			if count++; err == nil && count%streamFlushInterval == 0 {
+				err = w.Flush()
+			}
+			if err != nil {
+				log.Printf("Streaming notes failed: %v", err)
+				return
+			}
+		}
+		if err := w.Flush(); err != nil {
+			log.Printf("Streaming notes failed: %v", err)
+		}
 	})
+	return nil
+}
+
+// SearchNotes обрабатывает полнотекстовый поиск заметок (?q=)
+func (h *NoteHandler) SearchNotes(c *fiber.Ctx) error {
+	page, limit, offset := parsePagination(c)
+
+	query := c.Query("q")
+	if strings.TrimSpace(query) == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "query parameter 'q' is required")
+	}
+
+	results, total, err := h.service.SearchNotes(query, limit, offset)
+	if err != nil {
+		return err
+	}
+
+	return paginatedResponse(c, h.envelope, results, total, page, limit)
 }
 
 // GetNoteByID обрабатывает получение заметки по ID
 func (h *NoteHandler) GetNoteByID(c *fiber.Ctx) error {
-	idStr := c.Params("id")
-
 	// Парсим ID
-	id, err := strconv.ParseInt(idStr, 10, 64)
-	if err != nil || id <= 0 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "invalid note ID",
-		})
+	id, err := parseNoteID(c)
+	if err != nil {
+		return err
+	}
+
+	// Заметку отдаем в JSON, Markdown или простым текстом
+	mediaType, err := negotiate(c, noteMediaTypes...)
+	if err != nil {
+		return err
 	}
 
 	// Получаем заметку через сервис
 	note, err := h.service.GetNoteByID(id)
 	if err != nil {
-		if err.Error() == "note not found" {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error": "note not found",
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "internal server error",
-		})
+		return err
+	}
+
+	// Клиент с актуальной копией заметки получает 304 без тела
+	if notModified(c, representationETag(note, mediaType), note.UpdatedAt) {
+		return nil
 	}
 
 	// Возвращаем ответ
-	return c.JSON(note)
+	return sendNote(c, h.envelope, fiber.StatusOK, note, mediaType)
 }
 
 // UpdateNote обрабатывает обновление заметки
 func (h *NoteHandler) UpdateNote(c *fiber.Ctx) error {
-	idStr := c.Params("id")
-
 	// Парсим ID
-	id, err := strconv.ParseInt(idStr, 10, 64)
-	if err != nil || id <= 0 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "invalid note ID",
-		})
+	id, err := parseNoteID(c)
+	if err != nil {
+		return err
+	}
+
+	// Ожидаемая версия заметки из If-Match
+	version, err := parseIfMatch(c)
+	if err != nil {
+		return err
 	}
 
 	var req domain.UpdateNoteRequest
 
 	// Парсим JSON тело запроса
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "invalid request body",
-		})
+		return errInvalidBody
 	}
 
 	// Обновляем заметку через сервис
-	note, err := h.service.UpdateNote(id, req)
+	note, err := h.service.UpdateNote(id, req, version)
 	if err != nil {
-		if err.Error() == "note not found" {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error": "note not found",
-			})
-		}
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	// Возвращаем ответ
-	return c.JSON(note)
+	return noteJSON(c, h.envelope, note)
+}
+
+// acceptPatch форматы тела, которые принимает PATCH (RFC 5789)
+const acceptPatch = "application/merge-patch+json, application/json-patch+json"
+
+// PatchNote обрабатывает частичное обновление заметки в формате
+// JSON Merge Patch (application/merge-patch+json) или JSON Patch (application/json-patch+json)
+func (h *NoteHandler) PatchNote(c *fiber.Ctx) error {
+	id, err := parseNoteID(c)
+	if err != nil {
+		return err
+	}
+
+	c.Set("Accept-Patch", acceptPatch)
+
+	// Ожидаемая версия заметки из If-Match
+	version, err := parseIfMatch(c)
+	if err != nil {
+		return err
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(c.Get(fiber.HeaderContentType))
+	var format service.PatchFormat
+	switch mediaType {
+	case "application/merge-patch+json", fiber.MIMEApplicationJSON:
+		format = service.MergePatch
+	case "application/json-patch+json":
+		format = service.JSONPatch
+	default:
+		return fiber.NewError(fiber.StatusUnsupportedMediaType, "unsupported patch format, use "+acceptPatch)
+	}
+
+	note, err := h.service.PatchNote(id, format, c.Body(), version)
+	if err != nil {
+		return err
+	}
+
+	return noteJSON(c, h.envelope, note)
 }
 
 // DeleteNote обрабатывает удаление заметки
 func (h *NoteHandler) DeleteNote(c *fiber.Ctx) error {
-	idStr := c.Params("id")
-
 	// Парсим ID
-	id, err := strconv.ParseInt(idStr, 10, 64)
-	if err != nil || id <= 0 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "invalid note ID",
-		})
+	id, err := parseNoteID(c)
+	if err != nil {
+		return err
+	}
+
+	// Ожидаемая версия заметки из If-Match
+	version, err := parseIfMatch(c)
+	if err != nil {
+		return err
 	}
 
 	// Удаляем заметку через сервис
-	if err := h.service.DeleteNote(id); err != nil {
-		if err.Error() == "note not found" {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error": "note not found",
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "internal server error",
-		})
+	if err := h.service.DeleteNote(id, version); err != nil {
+		return err
 	}
 
 	// Возвращаем пустой ответ с кодом 200
 	return c.SendStatus(fiber.StatusOK)
 }
+
+// MoveNote обрабатывает перемещение заметки в блокнот
+func (h *NoteHandler) MoveNote(c *fiber.Ctx) error {
+	// Парсим ID
+	id, err := parseNoteID(c)
+	if err != nil {
+		return err
+	}
+
+	var req domain.MoveNoteRequest
+
+	// Парсим JSON тело запроса
+	if err := c.BodyParser(&req); err != nil {
+		return errInvalidBody
+	}
+
+	// Перемещаем заметку через сервис
+	note, err := h.service.MoveNote(id, req)
+	if err != nil {
+		return err
+	}
+
+	// Возвращаем ответ
+	return noteJSON(c, h.envelope, note)
+}
+
+// parseNoteID разбирает ID заметки из пути
+func parseNoteID(c *fiber.Ctx) (int64, error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fiber.NewError(fiber.StatusBadRequest, "invalid note ID")
+	}
+	return id, nil
+}
+
+// maxPage наибольший номер страницы: дальше списки все равно пусты
+const maxPage = 1_000_000
+
+// parsePagination получает параметры пагинации из query string
+func parsePagination(c *fiber.Ctx) (page, limit, offset int) {
+	page, _ = strconv.Atoi(c.Query("page", "1"))
+	limit, _ = strconv.Atoi(c.Query("limit", "10"))
+
+	// Валидация параметров
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100 // Ограничиваем максимальный лимит
+	}
+	if page > maxPage {
+		page = maxPage // Иначе offset переполняется и становится отрицательным
+	}
+
+	// Вычисляем offset
+	offset = (page - 1) * limit
+
+	return page, limit, offset
+}
+
+// dataResponse ответ со списком в поле data
+type dataResponse struct {
+	Data any `json:"data"`
+}
+
+// pageResponse страница списка с метаданными пагинации (pageMeta или cursorMeta)
+type pageResponse struct {
+	Data any `json:"data"`
+	Meta any `json:"meta"`
+}
+
+// pageMeta метаданные постраничной пагинации (?page=&limit=)
+type pageMeta struct {
+	Page       int  `json:"page"`
+	Limit      int  `json:"limit"`
+	Total      int  `json:"total"`
+	TotalPages int  `json:"totalPages"`
+	HasNext    bool `json:"hasNext"`
+	HasPrev    bool `json:"hasPrev"`
+}
+
+// paginatedResponse отправляет страницу данных с метаданными пагинации
+func paginatedResponse(c *fiber.Ctx, envelope Envelope, data any, total, page, limit int) error {
+	// Рассчитываем метаданные пагинации
+	totalPages := 0
+	if total > 0 {
+		totalPages = (total + limit - 1) / limit // ceil деление
+	}
+
+	return envelope.page(c, data, pageMeta{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+		HasNext:    page < totalPages,
+		HasPrev:    page > 1,
+	})
+}
+
+// parseNoteListOptions разбирает параметры фильтрации, сортировки (?sort=-updated_at,title)
+// и пагинации списка заметок (?page= или ?cursor=); возвращает также номер страницы
+func parseNoteListOptions(c *fiber.Ctx) (domain.NoteListOptions, int, error) {
+	var opts domain.NoteListOptions
+
+	page, limit, offset := parsePagination(c)
+	opts.Limit = limit
+	opts.Offset = offset
+
+	filter, err := parseNoteFilter(c)
+	if err != nil {
+		return opts, page, err
+	}
+	opts.Filter = filter
+
+	// Для ?ids= без явного limit отдаем все запрошенные заметки одной страницей
+	if len(filter.IDs) > 0 && c.Query("limit") == "" {
+		opts.Limit = len(filter.IDs)
+		opts.Offset = (page - 1) * opts.Limit
+	}
+
+	sort, err := domain.ParseSort(c.Query("sort"))
+	if err != nil {
+		return opts, page, err
+	}
+	opts.Sort = sort
+
+	if cursorMode(c) {
+		if !opts.CursorSortSupported() {
+			return opts, page, domain.NewFieldError("sort", "cursor pagination supports only sort=created_at or sort=-created_at")
+		}
+		if value := c.Query("cursor"); value != "" {
+			if opts.Cursor, err = domain.DecodeNoteCursor(value); err != nil {
+				return opts, page, err
+			}
+		}
+	}
+
+	return opts, page, nil
+}
+
+// parseNoteFilter разбирает параметры фильтрации списка заметок
+// (?ids=1,2,3&tag=a&tag=b&tag_mode=and|or&query=...&created_after=&created_before=&updated_since=&title_prefix=)
+func parseNoteFilter(c *fiber.Ctx) (domain.NoteFilter, error) {
+	var filter domain.NoteFilter
+
+	if value := c.Query("ids"); value != "" {
+		ids, err := domain.ParseIDs(value)
+		if err != nil {
+			return filter, err
+		}
+		filter.IDs = ids
+	}
+
+	var names []string
+	for _, value := range c.Context().QueryArgs().PeekMulti("tag") {
+		names = append(names, string(value))
+	}
+	tags, err := domain.NormalizeTags(names)
+	if err != nil {
+		return filter, err
+	}
+	filter.Tags = domain.TagNames(tags)
+
+	switch c.Query("tag_mode", "and") {
+	case "and":
+		filter.MatchAllTags = true
+	case "or":
+		filter.MatchAllTags = false
+	default:
+		return filter, domain.NewFieldError("tag_mode", "tag_mode must be 'and' or 'or'")
+	}
+
+	query, err := service.ParseNoteQuery(c.Query("query"))
+	if err != nil {
+		return filter, err
+	}
+	filter.Query = query
+
+	if filter.CreatedAfter, err = parseTimeParam(c, "created_after"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedBefore, err = parseTimeParam(c, "created_before"); err != nil {
+		return filter, err
+	}
+	if filter.UpdatedSince, err = parseTimeParam(c, "updated_since"); err != nil {
+		return filter, err
+	}
+	filter.TitlePrefix = c.Query("title_prefix")
+
+	return filter, nil
+}
+
+// parseTimeParam разбирает момент времени из query string в формате RFC 3339
+// или дату YYYY-MM-DD (начало дня по местному времени); пустой параметр дает nil
+func parseTimeParam(c *fiber.Ctx, name string) (*time.Time, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err != nil {
+		return nil, domain.NewFieldError(name, name+" must be a date (YYYY-MM-DD) or RFC 3339 time")
+	}
+	return &t, nil
+}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"strconv"
+
+	"notes-api/internal/domain"
+	"notes-api/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// NotebookHandler обрабатывает HTTP запросы для блокнотов
+type NotebookHandler struct {
+	service  *service.NotebookService
+	envelope Envelope
+}
+
+// NewNotebookHandler создает новый обработчик блокнотов с форматом ответов версии API
+func NewNotebookHandler(service *service.NotebookService, envelope Envelope) *NotebookHandler {
+	return &NotebookHandler{service: service, envelope: envelope}
+}
+
+// CreateNotebook обрабатывает создание блокнота
+func (h *NotebookHandler) CreateNotebook(c *fiber.Ctx) error {
+	var req domain.CreateNotebookRequest
+
+	// Парсим JSON тело запроса
+	if err := c.BodyParser(&req); err != nil {
+		return errInvalidBody
+	}
+
+	notebook, err := h.service.CreateNotebook(req)
+	if err != nil {
+		return err
+	}
+
+	return h.envelope.item(c, fiber.StatusCreated, notebook)
+}
+
+// GetAllNotebooks обрабатывает получение всех блокнотов
+func (h *NotebookHandler) GetAllNotebooks(c *fiber.Ctx) error {
+	notebooks, err := h.service.GetAllNotebooks()
+	if err != nil {
+		return err
+	}
+
+	return h.envelope.list(c, notebooks)
+}
+
+// GetNotebookByID обрабатывает получение блокнота по ID
+func (h *NotebookHandler) GetNotebookByID(c *fiber.Ctx) error {
+	id, err := parseNotebookID(c)
+	if err != nil {
+		return err
+	}
+
+	notebook, err := h.service.GetNotebookByID(id)
+	if err != nil {
+		return err
+	}
+
+	return h.envelope.item(c, fiber.StatusOK, notebook)
+}
+
+// UpdateNotebook обрабатывает переименование блокнота
+func (h *NotebookHandler) UpdateNotebook(c *fiber.Ctx) error {
+	id, err := parseNotebookID(c)
+	if err != nil {
+		return err
+	}
+
+	var req domain.UpdateNotebookRequest
+
+	// Парсим JSON тело запроса
+	if err := c.BodyParser(&req); err != nil {
+		return errInvalidBody
+	}
+
+	notebook, err := h.service.UpdateNotebook(id, req)
+	if err != nil {
+		return err
+	}
+
+	return h.envelope.item(c, fiber.StatusOK, notebook)
+}
+
+// MoveNotebook обрабатывает перемещение блокнота к другому родителю
+func (h *NotebookHandler) MoveNotebook(c *fiber.Ctx) error {
+	id, err := parseNotebookID(c)
+	if err != nil {
+		return err
+	}
+
+	var req domain.MoveNotebookRequest
+
+	// Парсим JSON тело запроса
+	if err := c.BodyParser(&req); err != nil {
+		return errInvalidBody
+	}
+
+	notebook, err := h.service.MoveNotebook(id, req)
+	if err != nil {
+		return err
+	}
+
+	return h.envelope.item(c, fiber.StatusOK, notebook)
+}
+
+// DeleteNotebook обрабатывает удаление блокнота
+func (h *NotebookHandler) DeleteNotebook(c *fiber.Ctx) error {
+	id, err := parseNotebookID(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.DeleteNotebook(id); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// GetNotebookNotes обрабатывает получение заметок блокнота вместе с вложенными блокнотами
+func (h *NotebookHandler) GetNotebookNotes(c *fiber.Ctx) error {
+	id, err := parseNotebookID(c)
+	if err != nil {
+		return err
+	}
+
+	opts, page, err := parseNoteListOptions(c)
+	if err != nil {
+		return err
+	}
+
+	fetch := func(opts domain.NoteListOptions) ([]*domain.Note, int, error) {
+		return h.service.GetNotebookNotes(id, opts)
+	}
+	if cursorMode(c) {
+		page, err := fetchCursorPage(opts, fetch)
+		if err != nil {
+			return err
+		}
+		return cursorResponse(c, h.envelope, page, opts.Limit)
+	}
+
+	notes, total, err := fetch(opts)
+	if err != nil {
+		return err
+	}
+
+	return paginatedResponse(c, h.envelope, notes, total, page, opts.Limit)
+}
+
+// parseNotebookID разбирает ID блокнота из пути
+func parseNotebookID(c *fiber.Ctx) (int64, error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fiber.NewError(fiber.StatusBadRequest, "invalid notebook ID")
+	}
+	return id, nil
+}
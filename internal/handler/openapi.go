@@ -0,0 +1,739 @@
+package handler
+
+import (
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"notes-api/internal/domain"
+	"notes-api/internal/format"
+	"notes-api/internal/openapi"
+	"notes-api/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// OpenAPI возвращает обработчик, отдающий описание версии API с путями под prefix в формате OpenAPI 3.1.
+// Документ строится один раз по зарегистрированным маршрутам (app.GetRoutes):
+// маршрут без описания в apiOperations все равно попадает в документ.
+func OpenAPI(routes []fiber.Route, prefix, version string, envelope Envelope) fiber.Handler {
+	body, err := json.Marshal(NewOpenAPIDocument(routes, prefix, version, envelope))
+	return func(c *fiber.Ctx) error {
+		if err != nil {
+			return err
+		}
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+		return c.Send(body)
+	}
+}
+
+// NewOpenAPIDocument строит описание маршрутов под prefix (например /api/v2).
+// Схемы тел запросов и ответов берутся из тех же структур, что использует API, ответы обернуты в envelope.
+func NewOpenAPIDocument(routes []fiber.Route, prefix, version string, envelope Envelope) *openapi.Document {
+	g := openapi.NewGenerator()
+	g.Define(reflect.TypeFor[domain.Tag](), openapi.Schema{"type": "string", "description": "Tag name"})
+	g.Define(reflect.TypeFor[gorm.DeletedAt](), openapi.Schema{"type": []string{"string", "null"}, "format": "date-time"})
+	operations := apiOperations(g, envelope)
+
+	doc := &openapi.Document{
+		OpenAPI: openapi.Version,
+		Info: openapi.Info{
+			Title:   "Notes API",
+			Version: version,
+		},
+		Servers: []openapi.Server{{URL: prefix}},
+		Paths:   make(map[string]openapi.PathItem),
+	}
+
+	for _, route := range routes {
+		if !strings.HasPrefix(route.Path, prefix+"/") || route.Method == fiber.MethodHead {
+			continue
+		}
+
+		path := openAPIPath(strings.TrimPrefix(route.Path, prefix))
+		operation, ok := operations[route.Method+" "+path]
+		if !ok {
+			operation = &openapi.Operation{
+				Summary:   route.Method + " " + path,
+				Responses: map[string]openapi.Response{"200": {Description: "OK"}},
+			}
+		}
+		for _, name := range route.Params {
+			operation.Parameters = append([]openapi.Parameter{pathParam(name)}, operation.Parameters...)
+		}
+		operation.Responses["default"] = problemResponse("Error")
+
+		method := strings.ToLower(route.Method)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(openapi.PathItem)
+		}
+		doc.Paths[path][method] = operation
+	}
+
+	g.Output(reflect.TypeFor[Problem]())
+	doc.Components.Schemas = g.Schemas()
+	return doc
+}
+
+// openAPIPath переводит путь Fiber (/notes/:id) в шаблон OpenAPI (/notes/{id})
+func openAPIPath(path string) string {
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, ":") {
+			parts[i] = "{" + strings.TrimSuffix(part[1:], "?") + "}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// pathParam описывает параметр пути; числовые параметры - ID и номер ревизии
+func pathParam(name string) openapi.Parameter {
+	schema := openapi.Schema{"type": "integer", "format": "int64", "minimum": 1}
+	if name == "name" {
+		schema = openapi.Schema{"type": "string"}
+	}
+	return openapi.Parameter{Name: name, In: "path", Required: true, Schema: schema}
+}
+
+// apiOperations описывает операции по ключу "METHOD /path/{param}" (путь без префикса версии)
+func apiOperations(g *openapi.Generator, env Envelope) map[string]*openapi.Operation {
+	note := g.Output(reflect.TypeFor[domain.Note]())
+	notebook := g.Output(reflect.TypeFor[domain.Notebook]())
+	revision := g.Output(reflect.TypeFor[domain.Revision]())
+	noteList := openapi.Schema{"oneOf": []openapi.Schema{env.pageSchema(g, note), env.cursorPageSchema(g, note)}}
+	noteItem := env.itemSchema(note)
+
+	webhook := g.Output(reflect.TypeFor[domain.Webhook]())
+	webhookRequest := g.Input(reflect.TypeFor[domain.WebhookRequest]())
+	noteEvent := g.Output(reflect.TypeFor[domain.NoteEvent]())
+	eventsParams := []openapi.Parameter{
+		headerParam("Last-Event-ID", "ID of the last received event; missed events are sent first"),
+		queryParam("last_event_id", "Same as Last-Event-ID for clients that cannot set headers", openapi.Schema{"type": "integer", "minimum": 0}),
+		queryParam("ids", "Only events of these comma-separated note IDs (at most "+strconv.Itoa(domain.MaxFilterIDs)+")", openapi.Schema{"type": "string"}),
+	}
+
+	filterParams := []openapi.Parameter{
+		queryParam("ids", "Comma-separated note IDs (at most "+strconv.Itoa(domain.MaxFilterIDs)+")", openapi.Schema{"type": "string"}),
+		{Name: "tag", In: "query", Description: "Tag filter, may be repeated", Schema: openapi.Schema{"type": "array", "items": openapi.Schema{"type": "string"}}},
+		queryParam("tag_mode", "How several tags are combined", openapi.Schema{"type": "string", "enum": []string{"and", "or"}, "default": "and"}),
+		queryParam("query", `Structured query, e.g. tag:work created:>2026-01-01 -title:draft "exact phrase"`, openapi.Schema{"type": "string"}),
+		queryParam("created_after", "Date (YYYY-MM-DD) or RFC 3339 time", openapi.Schema{"type": "string"}),
+		queryParam("created_before", "Date (YYYY-MM-DD) or RFC 3339 time", openapi.Schema{"type": "string"}),
+		queryParam("updated_since", "Date (YYYY-MM-DD) or RFC 3339 time", openapi.Schema{"type": "string"}),
+		queryParam("title_prefix", "Case-insensitive title prefix", openapi.Schema{"type": "string"}),
+		queryParam("sort", "Sort fields, '-' for descending: id, title, created_at, updated_at", openapi.Schema{"type": "string", "default": "-created_at"}),
+	}
+	listParams := append(append(slices.Clip(filterParams),
+		queryParam("cursor", "Cursor pagination; empty value for the first page. Only with sort by created_at", openapi.Schema{"type": "string"}),
+	), pageParams()...)
+	linkHeader := openapi.Header{Description: "first, next and prev pages in cursor mode (RFC 8288)", Schema: openapi.Schema{"type": "string"}}
+	noteRepresentation := noteResponse("Note", noteItem)
+	noteRepresentation.Content = withTextContent(noteRepresentation.Content, format.MIMEMarkdown, format.MIMEText)
+	createdNote := noteRepresentation
+	createdNote.Description = "Created note"
+	createBody := jsonBody(g.Input(reflect.TypeFor[domain.CreateNoteRequest]()))
+	createBody.Content = withTextContent(createBody.Content, format.MIMEMarkdown, format.MIMEText)
+
+	return map[string]*openapi.Operation{
+		"POST /notes": {
+			OperationID: "createNote",
+			Summary:     "Create a note",
+			Tags:        []string{"notes"},
+			Parameters: []openapi.Parameter{
+				headerParam("Idempotency-Key", "Repeating the request with the same key replays the stored response"),
+			},
+			Description: "The note may be sent as JSON, as Markdown with optional YAML front matter " +
+				"(title, tags, notebook_id) or as plain text whose first line becomes the title.",
+			RequestBody: createBody,
+			Responses: map[string]openapi.Response{
+				"201": createdNote,
+				"400": problemResponse("Validation failed"),
+				"406": problemResponse("No acceptable representation"),
+				"409": problemResponse("Request with this Idempotency-Key is still in progress"),
+				"422": problemResponse("Idempotency-Key was used with another request"),
+			},
+		},
+		"GET /notes": {
+			OperationID: "listNotes",
+			Summary:     "List notes",
+			Tags:        []string{"notes"},
+			Parameters:  append(slices.Clip(listParams), headerParam("If-None-Match", "ETag of the cached page")),
+			Responses: map[string]openapi.Response{
+				"200": {
+					Description: "Page of notes; meta depends on page or cursor pagination. " +
+						"CSV and YAML contain only the notes, the total count is in X-Total-Count",
+					Headers: map[string]openapi.Header{"ETag": etagHeader, "Link": linkHeader, "X-Total-Count": totalCountHeader},
+					Content: withTextContent(openapi.JSON(noteList), format.MIMECSV, format.MIMEYAML),
+				},
+				"304": {Description: "Page has not changed (If-None-Match / If-Modified-Since)"},
+				"400": problemResponse("Invalid filter, sort or cursor"),
+				"406": problemResponse("No acceptable representation"),
+			},
+		},
+		"GET /notes/search": {
+			OperationID: "searchNotes",
+			Summary:     "Full-text search",
+			Tags:        []string{"notes"},
+			Parameters: append([]openapi.Parameter{
+				{Name: "q", In: "query", Required: true, Schema: openapi.Schema{"type": "string"}},
+			}, pageParams()...),
+			Responses: map[string]openapi.Response{
+				"200": {
+					Description: "Search results ordered by rank",
+					Content:     openapi.JSON(env.pageSchema(g, g.Output(reflect.TypeFor[domain.SearchResult]()))),
+				},
+				"400": problemResponse("Missing query"),
+			},
+		},
+		"GET /notes/stream": {
+			OperationID: "streamNotes",
+			Summary:     "Stream all notes",
+			Description: "All notes matching the filter without pagination, one JSON note per line. " +
+				"Notes are written as they are read from storage, so memory use does not depend on the number of notes.",
+			Tags:       []string{"notes"},
+			Parameters: filterParams,
+			Responses: map[string]openapi.Response{
+				"200": {Description: "Notes, one per line", Content: map[string]openapi.MediaType{format.MIMENDJSON: {Schema: note}}},
+				"400": problemResponse("Invalid filter or sort"),
+			},
+		},
+		"POST /notes/bulk": {
+			OperationID: "bulkNotes",
+			Summary:     "Create, update and delete notes in one request",
+			Tags:        []string{"notes"},
+			RequestBody: jsonBody(g.Input(reflect.TypeFor[domain.BulkRequest]())),
+			Responses: map[string]openapi.Response{
+				"200": {Description: "Result of every operation", Content: openapi.JSON(env.itemSchema(g.Output(reflect.TypeFor[bulkResponse]())))},
+				"400": problemResponse("Invalid batch"),
+				"422": {Description: "Atomic batch failed, nothing was applied", Content: openapi.JSON(env.itemSchema(g.Output(reflect.TypeFor[bulkResponse]())))},
+			},
+		},
+		"GET /notes/{id}": {
+			OperationID: "getNote",
+			Summary:     "Get a note",
+			Tags:        []string{"notes"},
+			Parameters:  []openapi.Parameter{headerParam("If-None-Match", "ETag of the cached note")},
+			Responses: map[string]openapi.Response{
+				"200": noteRepresentation,
+				"304": {Description: "Note has not changed"},
+				"404": problemResponse("Note not found"),
+				"406": problemResponse("No acceptable representation"),
+			},
+		},
+		"PUT /notes/{id}": {
+			OperationID: "updateNote",
+			Summary:     "Replace title, content and tags of a note",
+			Tags:        []string{"notes"},
+			Parameters:  []openapi.Parameter{ifMatchParam},
+			RequestBody: jsonBody(g.Input(reflect.TypeFor[domain.UpdateNoteRequest]())),
+			Responses:   noteChangeResponses(noteItem),
+		},
+		"PATCH /notes/{id}": {
+			OperationID: "patchNote",
+			Summary:     "Partially update a note",
+			Description: "The patch is applied to the document {title, content, tags}.",
+			Tags:        []string{"notes"},
+			Parameters:  []openapi.Parameter{ifMatchParam},
+			RequestBody: &openapi.RequestBody{
+				Required: true,
+				Content: map[string]openapi.MediaType{
+					"application/merge-patch+json": {Schema: mergePatchSchema},
+					"application/json-patch+json":  {Schema: jsonPatchSchema},
+				},
+			},
+			Responses: withResponses(noteChangeResponses(noteItem), map[string]openapi.Response{
+				"409": problemResponse("JSON Patch test operation failed"),
+				"415": problemResponse("Unsupported patch format"),
+			}),
+		},
+		"DELETE /notes/{id}": {
+			OperationID: "deleteNote",
+			Summary:     "Move a note to the trash",
+			Tags:        []string{"notes"},
+			Parameters:  []openapi.Parameter{ifMatchParam},
+			Responses: map[string]openapi.Response{
+				"200": {Description: "Note moved to the trash"},
+				"404": problemResponse("Note not found"),
+				"412": problemResponse("Note version does not match If-Match"),
+				"428": problemResponse("If-Match is required"),
+			},
+		},
+		"POST /notes/{id}/move": {
+			OperationID: "moveNote",
+			Summary:     "Move a note to a notebook",
+			Tags:        []string{"notes"},
+			RequestBody: jsonBody(g.Input(reflect.TypeFor[domain.MoveNoteRequest]())),
+			Responses: map[string]openapi.Response{
+				"200": noteResponse("Moved note", noteItem),
+				"404": problemResponse("Note or notebook not found"),
+			},
+		},
+		"GET /notes/{id}/revisions": {
+			OperationID: "listRevisions",
+			Summary:     "List revisions of a note",
+			Tags:        []string{"revisions"},
+			Responses: map[string]openapi.Response{
+				"200": {Description: "Revisions", Content: openapi.JSON(env.listSchema(revision))},
+				"404": problemResponse("Note not found"),
+			},
+		},
+		"GET /notes/{id}/revisions/{rev}": {
+			OperationID: "getRevision",
+			Summary:     "Get a revision",
+			Tags:        []string{"revisions"},
+			Responses: map[string]openapi.Response{
+				"200": {Description: "Revision", Content: openapi.JSON(env.itemSchema(revision))},
+				"404": problemResponse("Note or revision not found"),
+			},
+		},
+		"GET /notes/{id}/revisions/{rev}/diff": {
+			OperationID: "diffRevision",
+			Summary:     "Unified diff of a revision",
+			Tags:        []string{"revisions"},
+			Parameters: []openapi.Parameter{
+				queryParam("to", "Revision number to compare with, or current", openapi.Schema{"type": "string", "default": "current"}),
+			},
+			Responses: map[string]openapi.Response{
+				"200": {Description: "Diff", Content: openapi.JSON(env.itemSchema(g.Output(reflect.TypeFor[domain.RevisionDiff]())))},
+				"404": problemResponse("Note or revision not found"),
+			},
+		},
+		"POST /notes/{id}/revisions/{rev}/restore": {
+			OperationID: "restoreRevision",
+			Summary:     "Restore a note from a revision",
+			Tags:        []string{"revisions"},
+			Parameters:  []openapi.Parameter{ifMatchParam},
+			Responses: map[string]openapi.Response{
+				"200": noteResponse("Restored note", noteItem),
+				"404": problemResponse("Note or revision not found"),
+				"412": problemResponse("Note version does not match If-Match"),
+				"428": problemResponse("If-Match is required"),
+			},
+		},
+		"GET /trash": {
+			OperationID: "listTrash",
+			Summary:     "List notes in the trash",
+			Tags:        []string{"trash"},
+			Parameters:  pageParams(),
+			Responses: map[string]openapi.Response{
+				"200": {Description: "Page of deleted notes", Content: openapi.JSON(env.pageSchema(g, note))},
+			},
+		},
+		"POST /trash/{id}/restore": {
+			OperationID: "restoreNote",
+			Summary:     "Restore a note from the trash",
+			Tags:        []string{"trash"},
+			Responses: map[string]openapi.Response{
+				"200": noteResponse("Restored note", noteItem),
+				"404": problemResponse("Note not found in trash"),
+			},
+		},
+		"DELETE /trash/{id}": {
+			OperationID: "purgeNote",
+			Summary:     "Delete a note from the trash permanently",
+			Tags:        []string{"trash"},
+			Responses: map[string]openapi.Response{
+				"200": {Description: "Note deleted"},
+				"404": problemResponse("Note not found in trash"),
+			},
+		},
+		"GET /tags": {
+			OperationID: "listTags",
+			Summary:     "List tags with note counts",
+			Tags:        []string{"tags"},
+			Responses: map[string]openapi.Response{
+				"200": {Description: "Tags", Content: openapi.JSON(env.listSchema(g.Output(reflect.TypeFor[domain.TagUsage]())))},
+			},
+		},
+		"POST /tags/merge": {
+			OperationID: "mergeTags",
+			Summary:     "Merge tags into a target tag",
+			Tags:        []string{"tags"},
+			RequestBody: jsonBody(g.Input(reflect.TypeFor[domain.MergeTagsRequest]())),
+			Responses: map[string]openapi.Response{
+				"200": {Description: "Target tag", Content: openapi.JSON(env.itemSchema(g.Output(reflect.TypeFor[domain.TagUsage]())))},
+				"400": problemResponse("Invalid tag names"),
+			},
+		},
+		"PUT /tags/{name}": {
+			OperationID: "renameTag",
+			Summary:     "Rename a tag",
+			Tags:        []string{"tags"},
+			RequestBody: jsonBody(g.Input(reflect.TypeFor[domain.RenameTagRequest]())),
+			Responses: map[string]openapi.Response{
+				"200": {Description: "Renamed tag", Content: openapi.JSON(env.itemSchema(g.Output(reflect.TypeFor[domain.TagUsage]())))},
+				"404": problemResponse("Tag not found"),
+				"409": problemResponse("Tag with the new name already exists"),
+			},
+		},
+		"POST /notebooks": {
+			OperationID: "createNotebook",
+			Summary:     "Create a notebook",
+			Tags:        []string{"notebooks"},
+			RequestBody: jsonBody(g.Input(reflect.TypeFor[domain.CreateNotebookRequest]())),
+			Responses: map[string]openapi.Response{
+				"201": {Description: "Created notebook", Content: openapi.JSON(env.itemSchema(notebook))},
+				"400": problemResponse("Validation failed"),
+				"404": problemResponse("Parent notebook not found"),
+			},
+		},
+		"GET /notebooks": {
+			OperationID: "listNotebooks",
+			Summary:     "List notebooks",
+			Tags:        []string{"notebooks"},
+			Responses: map[string]openapi.Response{
+				"200": {Description: "Notebooks", Content: openapi.JSON(env.listSchema(notebook))},
+			},
+		},
+		"GET /notebooks/{id}": {
+			OperationID: "getNotebook",
+			Summary:     "Get a notebook",
+			Tags:        []string{"notebooks"},
+			Responses: map[string]openapi.Response{
+				"200": {Description: "Notebook", Content: openapi.JSON(env.itemSchema(notebook))},
+				"404": problemResponse("Notebook not found"),
+			},
+		},
+		"PUT /notebooks/{id}": {
+			OperationID: "updateNotebook",
+			Summary:     "Rename a notebook",
+			Tags:        []string{"notebooks"},
+			RequestBody: jsonBody(g.Input(reflect.TypeFor[domain.UpdateNotebookRequest]())),
+			Responses: map[string]openapi.Response{
+				"200": {Description: "Updated notebook", Content: openapi.JSON(env.itemSchema(notebook))},
+				"400": problemResponse("Validation failed"),
+				"404": problemResponse("Notebook not found"),
+			},
+		},
+		"DELETE /notebooks/{id}": {
+			OperationID: "deleteNotebook",
+			Summary:     "Delete an empty notebook",
+			Tags:        []string{"notebooks"},
+			Responses: map[string]openapi.Response{
+				"200": {Description: "Notebook deleted"},
+				"404": problemResponse("Notebook not found"),
+				"409": problemResponse("Notebook has notes or child notebooks"),
+			},
+		},
+		"POST /notebooks/{id}/move": {
+			OperationID: "moveNotebook",
+			Summary:     "Move a notebook to another parent",
+			Tags:        []string{"notebooks"},
+			RequestBody: jsonBody(g.Input(reflect.TypeFor[domain.MoveNotebookRequest]())),
+			Responses: map[string]openapi.Response{
+				"200": {Description: "Moved notebook", Content: openapi.JSON(env.itemSchema(notebook))},
+				"404": problemResponse("Notebook not found"),
+				"409": problemResponse("Move would create a cycle"),
+			},
+		},
+		"GET /notebooks/{id}/notes": {
+			OperationID: "listNotebookNotes",
+			Summary:     "List notes of a notebook and its child notebooks",
+			Tags:        []string{"notebooks"},
+			Parameters:  listParams,
+			Responses: map[string]openapi.Response{
+				"200": {
+					Description: "Page of notes; meta depends on page or cursor pagination",
+					Headers:     map[string]openapi.Header{"Link": linkHeader},
+					Content:     openapi.JSON(noteList),
+				},
+				"400": problemResponse("Invalid filter, sort or cursor"),
+				"404": problemResponse("Notebook not found"),
+			},
+		},
+		"GET /export": {
+			OperationID: "exportNotes",
+			Summary:     "Export all notes",
+			Description: "markdown-zip contains one Markdown file per note with YAML front matter (id, title, tags, notebook_id, version, timestamps).",
+			Tags:        []string{"export"},
+			Parameters: []openapi.Parameter{
+				queryParam("format", "Export format", openapi.Schema{"type": "string", "enum": format.ExportFormats, "default": format.ExportMarkdownZip}),
+			},
+			Responses: map[string]openapi.Response{
+				"200": {
+					Description: "Export file (Content-Disposition: attachment)",
+					Content: map[string]openapi.MediaType{
+						format.ExportMarkdownZip.ContentType(): {Schema: openapi.Schema{"type": "string", "contentMediaType": "application/zip"}},
+						format.ExportJSON.ContentType():        {Schema: arraySchema(note)},
+						format.ExportNDJSON.ContentType():      {Schema: note},
+					},
+				},
+				"400": problemResponse("Unknown format"),
+			},
+		},
+		"POST /import": {
+			OperationID: "importNotes",
+			Summary:     "Import notes from other applications",
+			Description: "The file is the request body or the 'file' field of a multipart form: a ZIP of Markdown files " +
+				"(front matter honored), an Obsidian vault ZIP, Google Keep Takeout (ZIP or note JSON) or Evernote ENEX. " +
+				"Notes with the same title and content as an existing note or an earlier file are reported as duplicates and skipped.",
+			Tags: []string{"export"},
+			Parameters: []openapi.Parameter{
+				queryParam("format", "Source format; detected from the content by default", openapi.Schema{"type": "string", "enum": format.ImportFormats}),
+				queryParam("dry_run", "Only build the report, save nothing", openapi.Schema{"type": "boolean", "default": false}),
+			},
+			RequestBody: &openapi.RequestBody{
+				Required: true,
+				Content: map[string]openapi.MediaType{
+					"application/octet-stream": {Schema: openapi.Schema{"type": "string", "contentMediaType": "application/octet-stream"}},
+					fiber.MIMEMultipartForm: {Schema: openapi.Schema{
+						"type":       "object",
+						"properties": map[string]openapi.Schema{"file": {"type": "string", "contentMediaType": "application/octet-stream"}},
+						"required":   []string{"file"},
+					}},
+				},
+			},
+			Responses: map[string]openapi.Response{
+				"200": {Description: "Result for every file", Content: openapi.JSON(env.itemSchema(g.Output(reflect.TypeFor[domain.ImportReport]())))},
+				"400": problemResponse("Unknown or undetectable format, invalid archive"),
+				"413": problemResponse("File is larger than MAX_IMPORT_SIZE_MB"),
+			},
+		},
+		"GET /events": {
+			OperationID: "streamEvents",
+			Summary:     "Stream note change events",
+			Description: "Server-Sent Events note.created, note.updated and note.deleted. The event ID is a sequence number: " +
+				"after reconnecting with Last-Event-ID the missed events are sent from the server's bounded event log. " +
+				"If some of them are no longer in the log, a feed.reset event comes first and the client should reload notes.",
+			Tags:       []string{"events"},
+			Parameters: eventsParams,
+			Responses: map[string]openapi.Response{
+				"200": {Description: "Event stream", Content: map[string]openapi.MediaType{MIMEEventStream: {Schema: noteEvent}}},
+				"400": problemResponse("Invalid event ID or ids"),
+			},
+		},
+		"GET /events/ws": {
+			OperationID: "streamEventsWebSocket",
+			Summary:     "Stream note change events over WebSocket",
+			Description: "The same events as GET /events, one JSON message per event. " +
+				"The last received event ID is passed in last_event_id.",
+			Tags:       []string{"events"},
+			Parameters: eventsParams,
+			Responses: map[string]openapi.Response{
+				"101": {Description: "Switching to WebSocket"},
+				"400": problemResponse("Invalid event ID or ids"),
+				"426": problemResponse("Not a WebSocket upgrade request"),
+			},
+		},
+		"POST /webhooks": {
+			OperationID: "createWebhook",
+			Summary:     "Create a webhook",
+			Description: "Events are POSTed as JSON (the same objects as GET /events data) with headers X-Webhook-Event, " +
+				"X-Webhook-Delivery, X-Webhook-Timestamp and X-Webhook-Signature: sha256=<hex HMAC-SHA256 of \"<timestamp>.<body>\">. " +
+				"Failed deliveries are retried with exponential backoff; the webhook is disabled after repeated failed deliveries. " +
+				"The secret is generated if not given and is returned only in this response.",
+			Tags:        []string{"webhooks"},
+			RequestBody: jsonBody(webhookRequest),
+			Responses: map[string]openapi.Response{
+				"201": {Description: "Created webhook with its secret", Content: openapi.JSON(env.itemSchema(webhook))},
+				"400": problemResponse("Validation failed"),
+			},
+		},
+		"GET /webhooks": {
+			OperationID: "listWebhooks",
+			Summary:     "List webhooks",
+			Tags:        []string{"webhooks"},
+			Responses: map[string]openapi.Response{
+				"200": {Description: "Webhooks without secrets", Content: openapi.JSON(env.listSchema(webhook))},
+			},
+		},
+		"GET /webhooks/{id}": {
+			OperationID: "getWebhook",
+			Summary:     "Get a webhook",
+			Tags:        []string{"webhooks"},
+			Responses: map[string]openapi.Response{
+				"200": {Description: "Webhook without secret", Content: openapi.JSON(env.itemSchema(webhook))},
+				"404": problemResponse("Webhook not found"),
+			},
+		},
+		"PUT /webhooks/{id}": {
+			OperationID: "updateWebhook",
+			Summary:     "Update a webhook",
+			Description: "An empty secret keeps the current one, a missing active keeps the current state. " +
+				"active: true re-enables a webhook disabled after failed deliveries.",
+			Tags:        []string{"webhooks"},
+			RequestBody: jsonBody(webhookRequest),
+			Responses: map[string]openapi.Response{
+				"200": {Description: "Updated webhook without secret", Content: openapi.JSON(env.itemSchema(webhook))},
+				"400": problemResponse("Validation failed"),
+				"404": problemResponse("Webhook not found"),
+			},
+		},
+		"DELETE /webhooks/{id}": {
+			OperationID: "deleteWebhook",
+			Summary:     "Delete a webhook and its delivery log",
+			Tags:        []string{"webhooks"},
+			Responses: map[string]openapi.Response{
+				"200": {Description: "Webhook deleted"},
+				"404": problemResponse("Webhook not found"),
+			},
+		},
+		"GET /webhooks/{id}/deliveries": {
+			OperationID: "listWebhookDeliveries",
+			Summary:     "List recent deliveries of a webhook",
+			Description: "Newest first; the log keeps the last " + strconv.Itoa(service.WebhookDeliveryLogSize) + " deliveries of the webhook.",
+			Tags:        []string{"webhooks"},
+			Parameters:  pageParams(),
+			Responses: map[string]openapi.Response{
+				"200": {Description: "Page of deliveries", Content: openapi.JSON(env.pageSchema(g, g.Output(reflect.TypeFor[domain.WebhookDelivery]())))},
+				"404": problemResponse("Webhook not found"),
+			},
+		},
+		"GET /health": {
+			OperationID: "health",
+			Summary:     "Health check",
+			Tags:        []string{"service"},
+			Responses: map[string]openapi.Response{
+				"200": {Description: "Service is up", Content: openapi.JSON(openapi.Schema{
+					"type": "object",
+					"properties": map[string]openapi.Schema{
+						"status":  {"type": "string"},
+						"service": {"type": "string"},
+					},
+				})},
+			},
+		},
+	}
+}
+
+// etagHeader заголовок ETag ответа
+var etagHeader = openapi.Header{Description: "Entity tag of the returned data", Schema: openapi.Schema{"type": "string"}}
+
+// totalCountHeader заголовок с количеством заметок по фильтру в CSV и YAML представлениях списка
+var totalCountHeader = openapi.Header{Description: "Total number of notes matching the filter", Schema: openapi.Schema{"type": "integer"}}
+
+// ifMatchParam заголовок If-Match с ETag заметки для изменения без потери данных
+var ifMatchParam = openapi.Parameter{
+	Name:        "If-Match",
+	In:          "header",
+	Description: "ETag of the note; the request fails with 412 if the note has been modified",
+	Schema:      openapi.Schema{"type": "string"},
+}
+
+// mergePatchSchema тело JSON Merge Patch (RFC 7396)
+var mergePatchSchema = openapi.Schema{
+	"type": "object",
+	"properties": map[string]openapi.Schema{
+		"title":   {"type": "string"},
+		"content": {"type": "string"},
+		"tags":    {"type": []string{"array", "null"}, "items": openapi.Schema{"type": "string"}},
+	},
+	"additionalProperties": false,
+}
+
+// jsonPatchSchema тело JSON Patch (RFC 6902)
+var jsonPatchSchema = openapi.Schema{
+	"type": "array",
+	"items": openapi.Schema{
+		"type": "object",
+		"properties": map[string]openapi.Schema{
+			"op":    {"type": "string", "enum": []string{"add", "remove", "replace", "move", "copy", "test"}},
+			"path":  {"type": "string"},
+			"from":  {"type": "string"},
+			"value": {},
+		},
+		"required": []string{"op", "path"},
+	},
+}
+
+// pageParams параметры постраничной пагинации
+func pageParams() []openapi.Parameter {
+	return []openapi.Parameter{
+		queryParam("page", "Page number", openapi.Schema{"type": "integer", "minimum": 1, "default": 1}),
+		queryParam("limit", "Page size", openapi.Schema{"type": "integer", "minimum": 1, "maximum": 100, "default": 10}),
+	}
+}
+
+// queryParam описывает необязательный параметр строки запроса
+func queryParam(name, description string, schema openapi.Schema) openapi.Parameter {
+	return openapi.Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+// headerParam описывает необязательный заголовок запроса
+func headerParam(name, description string) openapi.Parameter {
+	return openapi.Parameter{Name: name, In: "header", Description: description, Schema: openapi.Schema{"type": "string"}}
+}
+
+// jsonBody описывает обязательное JSON тело запроса
+func jsonBody(schema openapi.Schema) *openapi.RequestBody {
+	return &openapi.RequestBody{Required: true, Content: openapi.JSON(schema)}
+}
+
+// noteResponse ответ с заметкой и ее ETag
+func noteResponse(description string, note openapi.Schema) openapi.Response {
+	return openapi.Response{
+		Description: description,
+		Headers:     map[string]openapi.Header{"ETag": etagHeader},
+		Content:     openapi.JSON(note),
+	}
+}
+
+// withTextContent возвращает копию содержимого с текстовыми представлениями mediaTypes
+func withTextContent(content map[string]openapi.MediaType, mediaTypes ...string) map[string]openapi.MediaType {
+	merged := maps.Clone(content)
+	for _, mediaType := range mediaTypes {
+		merged[mediaType] = openapi.MediaType{Schema: openapi.Schema{"type": "string"}}
+	}
+	return merged
+}
+
+// noteChangeResponses ответы операций изменения заметки
+func noteChangeResponses(note openapi.Schema) map[string]openapi.Response {
+	return map[string]openapi.Response{
+		"200": noteResponse("Updated note", note),
+		"400": problemResponse("Validation failed"),
+		"404": problemResponse("Note not found"),
+		"412": problemResponse("Note version does not match If-Match"),
+		"428": problemResponse("If-Match is required"),
+	}
+}
+
+// problemResponse ответ с ошибкой в формате application/problem+json
+func problemResponse(description string) openapi.Response {
+	return openapi.Response{
+		Description: description,
+		Content:     map[string]openapi.MediaType{MIMEApplicationProblemJSON: {Schema: openapi.Ref("Problem")}},
+	}
+}
+
+// withResponses возвращает копию ответов с добавленными extra
+func withResponses(responses, extra map[string]openapi.Response) map[string]openapi.Response {
+	merged := make(map[string]openapi.Response, len(responses)+len(extra))
+	for code, response := range responses {
+		merged[code] = response
+	}
+	for code, response := range extra {
+		merged[code] = response
+	}
+	return merged
+}
+
+// arraySchema схема списка элементов item
+func arraySchema(item openapi.Schema) openapi.Schema {
+	return openapi.Schema{"type": "array", "items": item}
+}
+
+// dataSchema схема объекта с результатом в поле data
+func dataSchema(data openapi.Schema) openapi.Schema {
+	return openapi.Schema{
+		"type":       "object",
+		"properties": map[string]openapi.Schema{"data": data},
+		"required":   []string{"data"},
+	}
+}
+
+// listSchema схема страницы списка элементов item с метаданными пагинации meta
+func listSchema(item, meta openapi.Schema) openapi.Schema {
+	return openapi.Schema{
+		"type": "object",
+		"properties": map[string]openapi.Schema{
+			"data": arraySchema(item),
+			"meta": meta,
+		},
+		"required": []string{"data", "meta"},
+	}
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"strconv"
+
+	"notes-api/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RevisionHandler обрабатывает HTTP запросы для истории изменений заметок
+type RevisionHandler struct {
+	service  *service.RevisionService
+	envelope Envelope
+}
+
+// NewRevisionHandler создает новый обработчик ревизий с форматом ответов версии API
+func NewRevisionHandler(service *service.RevisionService, envelope Envelope) *RevisionHandler {
+	return &RevisionHandler{service: service, envelope: envelope}
+}
+
+// GetRevisions обрабатывает получение списка ревизий заметки
+func (h *RevisionHandler) GetRevisions(c *fiber.Ctx) error {
+	noteID, err := parseNoteID(c)
+	if err != nil {
+		return err
+	}
+
+	revisions, err := h.service.GetRevisions(noteID)
+	if err != nil {
+		return err
+	}
+
+	return h.envelope.list(c, revisions)
+}
+
+// GetRevision обрабатывает получение ревизии по номеру
+func (h *RevisionHandler) GetRevision(c *fiber.Ctx) error {
+	noteID, number, err := parseRevisionParams(c)
+	if err != nil {
+		return err
+	}
+
+	revision, err := h.service.GetRevision(noteID, number)
+	if err != nil {
+		return err
+	}
+
+	return h.envelope.item(c, fiber.StatusOK, revision)
+}
+
+// DiffRevision обрабатывает получение diff между ревизией и другой ревизией (?to=)
+// или текущей версией заметки
+func (h *RevisionHandler) DiffRevision(c *fiber.Ctx) error {
+	noteID, number, err := parseRevisionParams(c)
+	if err != nil {
+		return err
+	}
+
+	var to *int
+	if toStr := c.Query("to"); toStr != "" && toStr != "current" {
+		toNumber, err := strconv.Atoi(toStr)
+		if err != nil || toNumber <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid revision number in 'to'")
+		}
+		to = &toNumber
+	}
+
+	diff, err := h.service.DiffRevisions(noteID, number, to)
+	if err != nil {
+		return err
+	}
+
+	return h.envelope.item(c, fiber.StatusOK, diff)
+}
+
+// RestoreRevision обрабатывает восстановление заметки из ревизии
+func (h *RevisionHandler) RestoreRevision(c *fiber.Ctx) error {
+	noteID, number, err := parseRevisionParams(c)
+	if err != nil {
+		return err
+	}
+
+	// Ожидаемая версия заметки из If-Match
+	version, err := parseIfMatch(c)
+	if err != nil {
+		return err
+	}
+
+	note, err := h.service.RestoreRevision(noteID, number, version)
+	if err != nil {
+		return err
+	}
+
+	return noteJSON(c, h.envelope, note)
+}
+
+// parseRevisionParams разбирает ID заметки и номер ревизии из пути
+func parseRevisionParams(c *fiber.Ctx) (int64, int, error) {
+	noteID, err := parseNoteID(c)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	number, err := strconv.Atoi(c.Params("rev"))
+	if err != nil || number <= 0 {
+		return 0, 0, fiber.NewError(fiber.StatusBadRequest, "invalid revision number")
+	}
+
+	return noteID, number, nil
+}
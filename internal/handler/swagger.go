@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/filesystem"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+// swaggerInitializer заменяет swagger-initializer.js из дистрибутива, который открывает демо Petstore
+const swaggerInitializer = `window.onload = function() {
+  window.ui = SwaggerUIBundle({
+    urls: %s,
+    dom_id: '#swagger-ui',
+    deepLinking: true,
+    presets: [
+      SwaggerUIBundle.presets.apis,
+      SwaggerUIStandalonePreset
+    ],
+    plugins: [
+      SwaggerUIBundle.plugins.DownloadUrl
+    ],
+    layout: "StandaloneLayout"
+  });
+};
+`
+
+// SwaggerSpec описание API, которое можно выбрать в Swagger UI
+type SwaggerSpec struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// SwaggerUI возвращает middleware, которое отдает Swagger UI из встроенных в бинарник файлов
+// и открывает в нем описания API specs (первое - по умолчанию). Подключается через app.Use(prefix, ...).
+func SwaggerUI(specs ...SwaggerSpec) fiber.Handler {
+	files := filesystem.New(filesystem.Config{
+		Root:  http.FS(swaggerFiles.FS),
+		Index: "index.html",
+	})
+	urls, _ := json.Marshal(specs)
+	initializer := fmt.Sprintf(swaggerInitializer, urls)
+
+	return func(c *fiber.Ctx) error {
+		prefix := c.Route().Path
+		switch strings.TrimPrefix(c.Path(), prefix) {
+		case "":
+			// Страница ссылается на файлы относительными путями, поэтому нужен завершающий слэш
+			return c.Redirect(prefix+"/", fiber.StatusMovedPermanently)
+		case "/swagger-initializer.js":
+			c.Type("js")
+			return c.SendString(initializer)
+		}
+		return files(c)
+	}
+}
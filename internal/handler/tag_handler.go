@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/url"
+
+	"notes-api/internal/domain"
+	"notes-api/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TagHandler обрабатывает HTTP запросы для тегов
+type TagHandler struct {
+	service  *service.TagService
+	envelope Envelope
+}
+
+// NewTagHandler создает новый обработчик тегов с форматом ответов версии API
+func NewTagHandler(service *service.TagService, envelope Envelope) *TagHandler {
+	return &TagHandler{service: service, envelope: envelope}
+}
+
+// GetAllTags обрабатывает получение тегов с количеством заметок
+func (h *TagHandler) GetAllTags(c *fiber.Ctx) error {
+	tags, err := h.service.ListTags()
+	if err != nil {
+		return err
+	}
+
+	return h.envelope.list(c, tags)
+}
+
+// RenameTag обрабатывает переименование тега
+func (h *TagHandler) RenameTag(c *fiber.Ctx) error {
+	name, err := url.PathUnescape(c.Params("name"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid tag name")
+	}
+
+	var req domain.RenameTagRequest
+
+	// Парсим JSON тело запроса
+	if err := c.BodyParser(&req); err != nil {
+		return errInvalidBody
+	}
+
+	tag, err := h.service.RenameTag(name, req)
+	if err != nil {
+		return err
+	}
+
+	return h.envelope.item(c, fiber.StatusOK, tag)
+}
+
+// MergeTags обрабатывает слияние тегов
+func (h *TagHandler) MergeTags(c *fiber.Ctx) error {
+	var req domain.MergeTagsRequest
+
+	// Парсим JSON тело запроса
+	if err := c.BodyParser(&req); err != nil {
+		return errInvalidBody
+	}
+
+	tag, err := h.service.MergeTags(req)
+	if err != nil {
+		return err
+	}
+
+	return h.envelope.item(c, fiber.StatusOK, tag)
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"errors"
+
+	"notes-api/internal/domain"
+	"notes-api/internal/repository"
+	"notes-api/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TrashHandler обрабатывает HTTP запросы для корзины
+type TrashHandler struct {
+	service  *service.TrashService
+	envelope Envelope
+}
+
+// NewTrashHandler создает новый обработчик корзины с форматом ответов версии API
+func NewTrashHandler(service *service.TrashService, envelope Envelope) *TrashHandler {
+	return &TrashHandler{service: service, envelope: envelope}
+}
+
+// GetTrash обрабатывает получение заметок из корзины с пагинацией
+func (h *TrashHandler) GetTrash(c *fiber.Ctx) error {
+	page, limit, offset := parsePagination(c)
+
+	notes, total, err := h.service.GetTrash(limit, offset)
+	if err != nil {
+		return err
+	}
+
+	return paginatedResponse(c, h.envelope, notes, total, page, limit)
+}
+
+// RestoreNote обрабатывает восстановление заметки из корзины
+func (h *TrashHandler) RestoreNote(c *fiber.Ctx) error {
+	id, err := parseNoteID(c)
+	if err != nil {
+		return err
+	}
+
+	note, err := h.service.RestoreNote(id)
+	if err != nil {
+		return trashError(err)
+	}
+
+	return noteJSON(c, h.envelope, note)
+}
+
+// PurgeNote обрабатывает окончательное удаление заметки из корзины
+func (h *TrashHandler) PurgeNote(c *fiber.Ctx) error {
+	id, err := parseNoteID(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.PurgeNote(id); err != nil {
+		return trashError(err)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// trashError преобразует ошибку сервиса корзины в HTTP ответ
+func trashError(err error) error {
+	if errors.Is(err, repository.ErrNoteNotFound) {
+		return domain.NewNotFoundError("note not found in trash")
+	}
+	return err
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"strconv"
+
+	"notes-api/internal/domain"
+	"notes-api/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// WebhookHandler обрабатывает HTTP запросы для вебхуков
+type WebhookHandler struct {
+	service  *service.WebhookService
+	envelope Envelope
+}
+
+// NewWebhookHandler создает новый обработчик вебхуков с форматом ответов версии API
+func NewWebhookHandler(service *service.WebhookService, envelope Envelope) *WebhookHandler {
+	return &WebhookHandler{service: service, envelope: envelope}
+}
+
+// CreateWebhook обрабатывает создание вебхука. Ответ содержит ключ подписи - единственный раз.
+func (h *WebhookHandler) CreateWebhook(c *fiber.Ctx) error {
+	var req domain.WebhookRequest
+
+	// Парсим JSON тело запроса
+	if err := c.BodyParser(&req); err != nil {
+		return errInvalidBody
+	}
+
+	webhook, err := h.service.CreateWebhook(req)
+	if err != nil {
+		return err
+	}
+
+	return h.envelope.item(c, fiber.StatusCreated, webhook)
+}
+
+// GetAllWebhooks обрабатывает получение всех вебхуков
+func (h *WebhookHandler) GetAllWebhooks(c *fiber.Ctx) error {
+	webhooks, err := h.service.GetAllWebhooks()
+	if err != nil {
+		return err
+	}
+
+	for i, webhook := range webhooks {
+		webhooks[i] = withoutSecret(webhook)
+	}
+	return h.envelope.list(c, webhooks)
+}
+
+// GetWebhookByID обрабатывает получение вебхука по ID
+func (h *WebhookHandler) GetWebhookByID(c *fiber.Ctx) error {
+	id, err := parseWebhookID(c)
+	if err != nil {
+		return err
+	}
+
+	webhook, err := h.service.GetWebhookByID(id)
+	if err != nil {
+		return err
+	}
+
+	return h.envelope.item(c, fiber.StatusOK, withoutSecret(webhook))
+}
+
+// UpdateWebhook обрабатывает изменение вебхука
+func (h *WebhookHandler) UpdateWebhook(c *fiber.Ctx) error {
+	id, err := parseWebhookID(c)
+	if err != nil {
+		return err
+	}
+
+	var req domain.WebhookRequest
+
+	// Парсим JSON тело запроса
+	if err := c.BodyParser(&req); err != nil {
+		return errInvalidBody
+	}
+
+	webhook, err := h.service.UpdateWebhook(id, req)
+	if err != nil {
+		return err
+	}
+
+	return h.envelope.item(c, fiber.StatusOK, withoutSecret(webhook))
+}
+
+// DeleteWebhook обрабатывает удаление вебхука
+func (h *WebhookHandler) DeleteWebhook(c *fiber.Ctx) error {
+	id, err := parseWebhookID(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.DeleteWebhook(id); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// GetDeliveries обрабатывает получение журнала доставок вебхука с пагинацией
+func (h *WebhookHandler) GetDeliveries(c *fiber.Ctx) error {
+	id, err := parseWebhookID(c)
+	if err != nil {
+		return err
+	}
+	page, limit, offset := parsePagination(c)
+
+	deliveries, total, err := h.service.GetDeliveries(id, limit, offset)
+	if err != nil {
+		return err
+	}
+
+	return paginatedResponse(c, h.envelope, deliveries, total, page, limit)
+}
+
+// withoutSecret возвращает копию вебхука без ключа подписи
+func withoutSecret(webhook *domain.Webhook) *domain.Webhook {
+	copied := *webhook
+	copied.Secret = ""
+	return &copied
+}
+
+// parseWebhookID разбирает ID вебхука из пути
+func parseWebhookID(c *fiber.Ctx) (int64, error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fiber.NewError(fiber.StatusBadRequest, "invalid webhook ID")
+	}
+	return id, nil
+}
@@ -0,0 +1,85 @@
+package openapi
+
+// Version версия спецификации OpenAPI, по которой строится документ
+const Version = "3.1.0"
+
+// Document корневой объект описания API
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Servers    []Server            `json:"servers,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+// Info общие сведения об API
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Server адрес, относительно которого заданы пути
+type Server struct {
+	URL string `json:"url"`
+}
+
+// PathItem операции одного пути по HTTP методам в нижнем регистре ("get", "post", ...)
+type PathItem map[string]*Operation
+
+// Operation описание одной операции API
+type Operation struct {
+	OperationID string              `json:"operationId,omitempty"`
+	Summary     string              `json:"summary,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// Parameter параметр пути, строки запроса или заголовка
+type Parameter struct {
+	Name        string `json:"name"`
+	In          string `json:"in"` // path, query или header
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Schema      Schema `json:"schema"`
+}
+
+// RequestBody тело запроса по типам содержимого
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response ответ операции
+type Response struct {
+	Description string               `json:"description"`
+	Headers     map[string]Header    `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// Header заголовок ответа
+type Header struct {
+	Description string `json:"description,omitempty"`
+	Schema      Schema `json:"schema"`
+}
+
+// MediaType схема содержимого одного типа
+type MediaType struct {
+	Schema Schema `json:"schema"`
+}
+
+// Components переиспользуемые схемы, на которые ссылаются операции
+type Components struct {
+	Schemas map[string]Schema `json:"schemas"`
+}
+
+// Schema JSON Schema (OpenAPI 3.1 использует JSON Schema 2020-12)
+type Schema map[string]any
+
+// JSON возвращает содержимое application/json с указанной схемой
+func JSON(schema Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
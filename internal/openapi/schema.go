@@ -0,0 +1,180 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Generator строит JSON Schema по Go типам, чтобы описание API не расходилось со структурами.
+// Именованные структуры попадают в components/schemas, а в операциях на них ставится $ref.
+type Generator struct {
+	schemas map[string]Schema
+	defined map[reflect.Type]Schema
+	input   bool
+}
+
+// NewGenerator создает генератор схем
+func NewGenerator() *Generator {
+	return &Generator{
+		schemas: make(map[string]Schema),
+		defined: map[reflect.Type]Schema{
+			reflect.TypeFor[time.Time](): {"type": "string", "format": "date-time"},
+		},
+	}
+}
+
+// Define задает схему для типа с собственной JSON сериализацией
+func (g *Generator) Define(t reflect.Type, schema Schema) {
+	g.defined[t] = schema
+}
+
+// Schemas возвращает схемы, собранные для components/schemas
+func (g *Generator) Schemas() map[string]Schema {
+	return g.schemas
+}
+
+// Output возвращает схему типа в ответе сервера.
+// Обязательны все поля без omitempty/omitzero: сервер отдает их всегда.
+func (g *Generator) Output(t reflect.Type) Schema {
+	g.input = false
+	return g.schema(t)
+}
+
+// Input возвращает схему типа в теле запроса.
+// Клиент может не передавать поля, поэтому обязательны только поля с тегом required:"true".
+func (g *Generator) Input(t reflect.Type) Schema {
+	g.input = true
+	return g.schema(t)
+}
+
+// schema возвращает схему типа; для именованной структуры - ссылку на нее
+func (g *Generator) schema(t reflect.Type) Schema {
+	if schema, ok := g.defined[t]; ok {
+		return schema
+	}
+	if t.Kind() != reflect.Pointer && t.Implements(reflect.TypeFor[json.Marshaler]()) {
+		return Schema{} // Формат задает сам тип, а схема для него не определена
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schema(t.Elem())
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return Schema{"type": "integer", "format": "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return Schema{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return Schema{"type": "string", "contentEncoding": "base64"}
+		}
+		return Schema{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		return g.structSchema(t)
+	default:
+		return Schema{}
+	}
+}
+
+// structSchema описывает структуру по ее JSON тегам
+func (g *Generator) structSchema(t reflect.Type) Schema {
+	name := schemaName(t)
+	if name != "" {
+		if _, ok := g.schemas[name]; ok {
+			return Ref(name)
+		}
+		// Заглушка до построения полей, чтобы рекурсивные типы ссылались сами на себя
+		g.schemas[name] = Schema{}
+	}
+
+	properties := make(map[string]Schema)
+	var required []string
+	g.addFields(t, properties, &required)
+
+	schema := Schema{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	if name == "" {
+		return schema
+	}
+	g.schemas[name] = schema
+	return Ref(name)
+}
+
+// addFields добавляет свойства структуры; поля встроенных структур поднимаются наверх, как в encoding/json
+func (g *Generator) addFields(t reflect.Type, properties map[string]Schema, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			g.addFields(field.Type, properties, required)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema := g.schema(field.Type)
+		if field.Type.Kind() == reflect.Pointer || field.Type.Kind() == reflect.Interface {
+			schema = Nullable(schema)
+		}
+		properties[name] = schema
+
+		optional := strings.Contains(options, "omitempty") || strings.Contains(options, "omitzero")
+		if field.Tag.Get("required") == "true" || (!g.input && !optional) {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// schemaName возвращает имя схемы типа с заглавной буквы; у анонимных и обобщенных типов имени нет
+func schemaName(t reflect.Type) string {
+	name := t.Name()
+	if name == "" || strings.Contains(name, "[") {
+		return ""
+	}
+	first, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(first)) + name[size:]
+}
+
+// Ref возвращает ссылку на схему из components/schemas
+func Ref(name string) Schema {
+	return Schema{"$ref": "#/components/schemas/" + name}
+}
+
+// Nullable разрешает в схеме значение null
+func Nullable(schema Schema) Schema {
+	switch typ := schema["type"].(type) {
+	case string:
+		nullable := make(Schema, len(schema))
+		for key, value := range schema {
+			nullable[key] = value
+		}
+		nullable["type"] = []string{typ, "null"}
+		return nullable
+	case nil:
+		if len(schema) == 0 {
+			return schema
+		}
+		return Schema{"anyOf": []Schema{schema, {"type": "null"}}}
+	default:
+		return schema // Тип уже задан списком
+	}
+}
@@ -13,7 +13,7 @@ type Config struct {
 }
 
 // NewRepository создает репозиторий на основе конфигурации
-func NewRepository(cfg Config) (NoteRepository, error) {
+func NewRepository(cfg Config) (Repository, error) {
 	switch cfg.Type {
 	case "json":
 		return newJSONRepository(cfg)
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"notes-api/internal/domain"
+)
+
+// jsonSnapshot состояние заметок и ревизий для отката пакетного изменения
+type jsonSnapshot struct {
+	notes     map[int64]domain.Note
+	nextID    int64
+	revisions map[int64][]*domain.Revision
+}
+
+// Bulk выполняет операции пакетного запроса в памяти и сохраняет результат одной записью файлов
+func (r *JSONRepository) Bulk(ops []domain.BulkNoteOp, atomic bool) ([]domain.BulkResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	snapshot := r.snapshot()
+	results := make([]domain.BulkResult, len(ops))
+	touched := make(map[int64]bool)
+	failed := false
+
+	for i, op := range ops {
+		results[i] = domain.BulkResult{Index: op.Index, Op: op.Op, ID: op.ID}
+		if atomic && failed {
+			results[i].Err = ErrBulkNotApplied
+			continue
+		}
+
+		note, err := r.applyBulkOp(op)
+		if err != nil {
+			results[i].Err = err
+			failed = true
+			continue
+		}
+		results[i].ID = note.ID
+		if op.Op != domain.BulkDelete {
+			results[i].Note = copyNote(note)
+		}
+		touched[note.ID] = true
+	}
+
+	// В атомарном режиме одна ошибка отменяет все операции
+	if atomic && failed {
+		r.restore(snapshot)
+		for i := range results {
+			if results[i].Err == nil {
+				results[i] = domain.BulkResult{Index: ops[i].Index, Op: ops[i].Op, ID: ops[i].ID, Err: ErrBulkNotApplied}
+			}
+		}
+		return results, nil
+	}
+	if len(touched) == 0 {
+		return results, nil
+	}
+
+	// Сохраняем все изменения одной записью
+	err := r.saveToFile()
+	if err == nil {
+		err = r.saveRevisions()
+	}
+	if err != nil {
+		r.restore(snapshot)
+		return nil, err
+	}
+
+	for id := range touched {
+		if note, exists := r.liveNote(id); exists {
+			r.index.Add(id, note.Language, note.Title, note.Content)
+		} else {
+			r.index.Remove(id)
+		}
+	}
+
+	return results, nil
+}
+
+// applyBulkOp применяет одну операцию в памяти (вызывать под блокировкой)
+func (r *JSONRepository) applyBulkOp(op domain.BulkNoteOp) (*domain.Note, error) {
+	switch op.Op {
+	case domain.BulkCreate:
+		if err := r.createNote(op.Note); err != nil {
+			return nil, err
+		}
+		return op.Note, nil
+	case domain.BulkUpdate:
+		note, _, err := r.patchNote(op.ID, op.Patch)
+		return note, err
+	default:
+		return r.deleteNote(op.ID, op.Version)
+	}
+}
+
+// snapshot запоминает состояние заметок и ревизий (вызывать под блокировкой)
+func (r *JSONRepository) snapshot() *jsonSnapshot {
+	s := &jsonSnapshot{
+		notes:     make(map[int64]domain.Note, len(r.notes)),
+		nextID:    r.nextID,
+		revisions: make(map[int64][]*domain.Revision, len(r.revisions)),
+	}
+	for id, note := range r.notes {
+		s.notes[id] = *note
+	}
+	for id, revisions := range r.revisions {
+		s.revisions[id] = revisions
+	}
+	return s
+}
+
+// restore возвращает заметки и ревизии к запомненному состоянию (вызывать под блокировкой).
+// Заметки восстанавливаются на месте, чтобы указатели на них оставались действительными.
+func (r *JSONRepository) restore(s *jsonSnapshot) {
+	for id, note := range r.notes {
+		saved, exists := s.notes[id]
+		if !exists {
+			delete(r.notes, id)
+			continue
+		}
+		*note = saved
+	}
+	r.nextID = s.nextID
+	r.revisions = s.revisions
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"sort"
+	"time"
+
+	"notes-api/internal/domain"
+)
+
+// idempotencyFile - имя файла с ключами идемпотентности рядом с файлом заметок
+const idempotencyFile = "idempotency.json"
+
+// loadIdempotencyKeys загружает ключи идемпотентности из JSON файла
+func (r *JSONRepository) loadIdempotencyKeys() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var keys []*domain.IdempotencyKey
+	if err := readJSONFile(r.siblingFile(idempotencyFile), &keys); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		r.idempotencyKeys[key.Key] = key
+	}
+
+	return nil
+}
+
+// saveIdempotencyKeys сохраняет ключи идемпотентности в JSON файл
+func (r *JSONRepository) saveIdempotencyKeys() error {
+	keys := make([]*domain.IdempotencyKey, 0, len(r.idempotencyKeys))
+	for _, key := range r.idempotencyKeys {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+
+	return writeJSONFile(r.siblingFile(idempotencyFile), keys)
+}
+
+// ReserveIdempotencyKey сохраняет новый ключ, если его нет или срок его хранения истек.
+// Иначе возвращает существующую запись и не меняет ее.
+func (r *JSONRepository) ReserveIdempotencyKey(record *domain.IdempotencyKey) (*domain.IdempotencyKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous, exists := r.idempotencyKeys[record.Key]
+	if exists && !previous.Expired(record.CreatedAt) {
+		return previous, nil
+	}
+
+	r.idempotencyKeys[record.Key] = record
+	if err := r.saveIdempotencyKeys(); err != nil {
+		// Откатываем изменение в случае ошибки
+		if exists {
+			r.idempotencyKeys[record.Key] = previous
+		} else {
+			delete(r.idempotencyKeys, record.Key)
+		}
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// CompleteIdempotencyKey сохраняет ответ на запрос и новый срок хранения ключа
+func (r *JSONRepository) CompleteIdempotencyKey(record *domain.IdempotencyKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous, exists := r.idempotencyKeys[record.Key]
+	r.idempotencyKeys[record.Key] = record
+	if err := r.saveIdempotencyKeys(); err != nil {
+		if exists {
+			r.idempotencyKeys[record.Key] = previous
+		} else {
+			delete(r.idempotencyKeys, record.Key)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// DeleteIdempotencyKey удаляет ключ, чтобы запрос с ним можно было повторить
+func (r *JSONRepository) DeleteIdempotencyKey(key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous, exists := r.idempotencyKeys[key]
+	if !exists {
+		return nil
+	}
+
+	delete(r.idempotencyKeys, key)
+	if err := r.saveIdempotencyKeys(); err != nil {
+		r.idempotencyKeys[key] = previous // Откатываем изменение в случае ошибки
+		return err
+	}
+
+	return nil
+}
+
+// PurgeIdempotencyKeys удаляет ключи, срок хранения которых истек к моменту now
+func (r *JSONRepository) PurgeIdempotencyKeys(now time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	expired := make(map[string]*domain.IdempotencyKey)
+	for key, record := range r.idempotencyKeys {
+		if record.Expired(now) {
+			expired[key] = record
+		}
+	}
+	if len(expired) == 0 {
+		return 0, nil
+	}
+
+	for key := range expired {
+		delete(r.idempotencyKeys, key)
+	}
+	if err := r.saveIdempotencyKeys(); err != nil {
+		for key, record := range expired {
+			r.idempotencyKeys[key] = record // Откатываем изменение в случае ошибки
+		}
+		return 0, err
+	}
+
+	return len(expired), nil
+}
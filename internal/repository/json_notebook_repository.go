@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"sort"
+	"time"
+
+	"notes-api/internal/domain"
+)
+
+// notebooksFile - имя файла с блокнотами рядом с файлом заметок
+const notebooksFile = "notebooks.json"
+
+// loadNotebooks загружает блокноты из JSON файла
+func (r *JSONRepository) loadNotebooks() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var notebooks []*domain.Notebook
+	if err := readJSONFile(r.siblingFile(notebooksFile), &notebooks); err != nil {
+		return err
+	}
+
+	for _, notebook := range notebooks {
+		r.notebooks[notebook.ID] = notebook
+	}
+
+	return nil
+}
+
+// saveNotebooks сохраняет блокноты в JSON файл
+func (r *JSONRepository) saveNotebooks() error {
+	notebooks := make([]*domain.Notebook, 0, len(r.notebooks))
+	for _, notebook := range r.notebooks {
+		notebooks = append(notebooks, notebook)
+	}
+
+	sort.Slice(notebooks, func(i, j int) bool {
+		return notebooks[i].ID < notebooks[j].ID
+	})
+
+	return writeJSONFile(r.siblingFile(notebooksFile), notebooks)
+}
+
+// CreateNotebook создает новый блокнот
+func (r *JSONRepository) CreateNotebook(notebook *domain.Notebook) (*domain.Notebook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Родительский блокнот должен существовать
+	if notebook.ParentID != nil {
+		if _, exists := r.notebooks[*notebook.ParentID]; !exists {
+			return nil, ErrNotebookNotFound
+		}
+	}
+
+	notebook.ID = r.nextNotebookID
+	now := time.Now()
+	notebook.CreatedAt = now
+	notebook.UpdatedAt = now
+
+	r.notebooks[notebook.ID] = notebook
+	r.nextNotebookID++
+
+	if err := r.saveNotebooks(); err != nil {
+		delete(r.notebooks, notebook.ID) // Откатываем изменение в случае ошибки
+		return nil, err
+	}
+
+	return notebook, nil
+}
+
+// GetAllNotebooks возвращает все блокноты, отсортированные по имени
+func (r *JSONRepository) GetAllNotebooks() ([]*domain.Notebook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	notebooks := make([]*domain.Notebook, 0, len(r.notebooks))
+	for _, notebook := range r.notebooks {
+		notebooks = append(notebooks, notebook)
+	}
+
+	sort.Slice(notebooks, func(i, j int) bool {
+		if notebooks[i].Name != notebooks[j].Name {
+			return notebooks[i].Name < notebooks[j].Name
+		}
+		return notebooks[i].ID < notebooks[j].ID
+	})
+
+	return notebooks, nil
+}
+
+// GetNotebookByID возвращает блокнот по ID
+func (r *JSONRepository) GetNotebookByID(id int64) (*domain.Notebook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	notebook, exists := r.notebooks[id]
+	if !exists {
+		return nil, ErrNotebookNotFound
+	}
+
+	return notebook, nil
+}
+
+// UpdateNotebook переименовывает блокнот
+func (r *JSONRepository) UpdateNotebook(id int64, notebook *domain.Notebook) (*domain.Notebook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, exists := r.notebooks[id]
+	if !exists {
+		return nil, ErrNotebookNotFound
+	}
+
+	existing.Name = notebook.Name
+	existing.UpdatedAt = time.Now()
+
+	if err := r.saveNotebooks(); err != nil {
+		return nil, err
+	}
+
+	return existing, nil
+}
+
+// MoveNotebook перемещает блокнот к другому родителю
+func (r *JSONRepository) MoveNotebook(id int64, parentID *int64) (*domain.Notebook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	notebook, exists := r.notebooks[id]
+	if !exists {
+		return nil, ErrNotebookNotFound
+	}
+
+	if parentID != nil {
+		if _, exists := r.notebooks[*parentID]; !exists {
+			return nil, ErrNotebookNotFound
+		}
+		// Нельзя переместить блокнот внутрь самого себя
+		for _, descendant := range r.subtree(id) {
+			if descendant == *parentID {
+				return nil, ErrNotebookCycle
+			}
+		}
+	}
+
+	previous := notebook.ParentID
+	notebook.ParentID = parentID
+	notebook.UpdatedAt = time.Now()
+
+	if err := r.saveNotebooks(); err != nil {
+		notebook.ParentID = previous // Откатываем изменение в случае ошибки
+		return nil, err
+	}
+
+	return notebook, nil
+}
+
+// DeleteNotebook удаляет пустой блокнот
+func (r *JSONRepository) DeleteNotebook(id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.notebooks[id]; !exists {
+		return ErrNotebookNotFound
+	}
+
+	// Блокнот не должен содержать вложенных блокнотов и заметок
+	for _, notebook := range r.notebooks {
+		if notebook.ParentID != nil && *notebook.ParentID == id {
+			return ErrNotebookNotEmpty
+		}
+	}
+	for _, note := range r.notes {
+		if !isDeleted(note) && note.NotebookID != nil && *note.NotebookID == id {
+			return ErrNotebookNotEmpty
+		}
+	}
+
+	delete(r.notebooks, id)
+
+	return r.saveNotebooks()
+}
+
+// GetNotebookSubtree возвращает ID блокнота и всех его потомков
+func (r *JSONRepository) GetNotebookSubtree(id int64) ([]int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, exists := r.notebooks[id]; !exists {
+		return nil, ErrNotebookNotFound
+	}
+
+	return r.subtree(id), nil
+}
+
+// subtree обходит дерево блокнотов в ширину (вызывать под блокировкой)
+func (r *JSONRepository) subtree(id int64) []int64 {
+	children := make(map[int64][]int64)
+	for _, notebook := range r.notebooks {
+		if notebook.ParentID != nil {
+			children[*notebook.ParentID] = append(children[*notebook.ParentID], notebook.ID)
+		}
+	}
+
+	ids := []int64{id}
+	for i := 0; i < len(ids); i++ {
+		ids = append(ids, children[ids[i]]...)
+	}
+
+	return ids
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"strings"
+
+	"notes-api/internal/domain"
+)
+
+// matchesQuery вычисляет условие структурированного запроса для заметки
+func matchesQuery(note *domain.Note, node domain.QueryNode) bool {
+	switch node := node.(type) {
+	case domain.QueryAnd:
+		for _, child := range node.Nodes {
+			if !matchesQuery(note, child) {
+				return false
+			}
+		}
+		return true
+	case domain.QueryOr:
+		for _, child := range node.Nodes {
+			if matchesQuery(note, child) {
+				return true
+			}
+		}
+		return false
+	case domain.QueryNot:
+		return !matchesQuery(note, node.Node)
+	case domain.QueryText:
+		value := strings.ToLower(node.Value)
+		title := strings.Contains(strings.ToLower(note.Title), value)
+		content := strings.Contains(strings.ToLower(note.Content), value)
+		switch node.Field {
+		case domain.QueryFieldTitle:
+			return title
+		case domain.QueryFieldContent:
+			return content
+		default:
+			return title || content
+		}
+	case domain.QueryTag:
+		return hasTag(note, node.Name)
+	case domain.QueryNotebook:
+		return note.NotebookID != nil && *note.NotebookID == node.ID
+	case domain.QueryTime:
+		value := note.CreatedAt
+		if node.Field == domain.QueryFieldUpdated {
+			value = note.UpdatedAt
+		}
+		switch node.Op {
+		case domain.QueryOpLess:
+			return value.Before(node.Value)
+		case domain.QueryOpLessEqual:
+			return !value.After(node.Value)
+		case domain.QueryOpGreater:
+			return value.After(node.Value)
+		default:
+			return !value.Before(node.Value)
+		}
+	default:
+		return false
+	}
+}
@@ -1,52 +1,164 @@
 package repository
 
 import (
+	"cmp"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"iter"
 	"os"
+	"path/filepath"
+	"slices"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"notes-api/internal/domain"
+	"notes-api/internal/search"
+
+	"gorm.io/gorm"
 )
 
+// Ошибки хранилища. Их типы из domain определяют код HTTP ответа.
 var (
-	ErrNoteNotFound = errors.New("note not found")
+	ErrNoteNotFound        = domain.NewNotFoundError("note not found")
+	ErrNoteVersionMismatch = domain.NewPreconditionError("note version does not match")
+	ErrTagNotFound         = domain.NewNotFoundError("tag not found")
+	ErrTagExists           = domain.NewConflictError("tag already exists")
+
+	ErrNotebookNotFound = domain.NewNotFoundError("notebook not found")
+	ErrNotebookNotEmpty = domain.NewConflictError("notebook is not empty")
+	ErrNotebookCycle    = domain.NewConflictError("notebook cannot be moved into itself or its descendant")
+
+	ErrRevisionNotFound = domain.NewNotFoundError("revision not found")
+
+	ErrWebhookNotFound = domain.NewNotFoundError("webhook not found")
+
+	ErrBulkNotApplied = errors.New("operation was not applied because another operation failed")
 )
 
-// JSONRepository реализует хранение заметок в JSON файле
+// JSONRepository реализует хранение заметок в JSON файле.
+// Блокноты, ревизии, ключи идемпотентности и вебхуки хранятся в отдельных файлах рядом с файлом заметок.
 type JSONRepository struct {
 	filename string
 	mu       sync.RWMutex
 	notes    map[int64]*domain.Note
 	nextID   int64
+
+	notebooks      map[int64]*domain.Notebook
+	nextNotebookID int64
+
+	revisions map[int64][]*domain.Revision // Ревизии по ID заметки в порядке возрастания номера
+
+	idempotencyKeys map[string]*domain.IdempotencyKey
+
+	webhooks       map[int64]*domain.Webhook
+	nextWebhookID  int64
+	deliveries     map[int64][]*domain.WebhookDelivery // Журнал доставок по ID вебхука в порядке возрастания ID
+	nextDeliveryID int64
+	// deliveryLines сколько строк в файле журнала доставок; deliveriesBroken - последняя запись
+	// в файл не удалась и его нужно переписать целиком
+	deliveryLines    int
+	deliveriesBroken bool
+
+	index *search.Index // Полнотекстовый индекс заметок не из корзины
 }
 
 // NewJSONRepository создает новый JSON репозиторий
 func NewJSONRepository(filename string) (*JSONRepository, error) {
 	repo := &JSONRepository{
-		filename: filename,
-		notes:    make(map[int64]*domain.Note),
-		nextID:   1,
+		filename:        filename,
+		notes:           make(map[int64]*domain.Note),
+		nextID:          1,
+		notebooks:       make(map[int64]*domain.Notebook),
+		nextNotebookID:  1,
+		revisions:       make(map[int64][]*domain.Revision),
+		idempotencyKeys: make(map[string]*domain.IdempotencyKey),
+		webhooks:        make(map[int64]*domain.Webhook),
+		nextWebhookID:   1,
+		deliveries:      make(map[int64][]*domain.WebhookDelivery),
+		nextDeliveryID:  1,
+		index:           search.NewIndex(),
 	}
 
 	// Загружаем данные из файла при старте
 	if err := repo.loadFromFile(); err != nil {
 		return nil, fmt.Errorf("failed to load data from file: %w", err)
 	}
+	if err := repo.loadNotebooks(); err != nil {
+		return nil, fmt.Errorf("failed to load notebooks: %w", err)
+	}
+	if err := repo.loadRevisions(); err != nil {
+		return nil, fmt.Errorf("failed to load revisions: %w", err)
+	}
+	if err := repo.loadIdempotencyKeys(); err != nil {
+		return nil, fmt.Errorf("failed to load idempotency keys: %w", err)
+	}
+	if err := repo.loadWebhooks(); err != nil {
+		return nil, fmt.Errorf("failed to load webhooks: %w", err)
+	}
 
-	// Находим максимальный ID для генерации новых
-	for id := range repo.notes {
+	// Находим максимальный ID для генерации новых и строим поисковый индекс
+	for id, note := range repo.notes {
 		if id >= repo.nextID {
 			repo.nextID = id + 1
 		}
+		if note.Language == "" {
+			note.Language = search.DetectLanguage(note.Title, note.Content)
+		}
+		if note.Version == 0 {
+			note.Version = 1
+		}
+		if !isDeleted(note) {
+			repo.index.Add(id, note.Language, note.Title, note.Content)
+		}
+	}
+	for id := range repo.notebooks {
+		if id >= repo.nextNotebookID {
+			repo.nextNotebookID = id + 1
+		}
 	}
 
 	return repo, nil
 }
 
+// siblingFile возвращает путь к дополнительному файлу хранилища
+// в той же директории, что и файл заметок
+func (r *JSONRepository) siblingFile(name string) string {
+	return filepath.Join(filepath.Dir(r.filename), name)
+}
+
+// readJSONFile читает JSON файл в v. Отсутствующий или пустой файл не считается ошибкой.
+func readJSONFile(filename string, v any) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	return nil
+}
+
+// writeJSONFile сериализует v в JSON файл с отступами
+func writeJSONFile(filename string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return &domain.UnavailableError{Err: fmt.Errorf("failed to write file: %w", err)}
+	}
+	return nil
+}
+
 // loadFromFile загружает данные из JSON файла
 func (r *JSONRepository) loadFromFile() error {
 	r.mu.Lock()
@@ -73,6 +185,9 @@ func (r *JSONRepository) loadFromFile() error {
 
 		// Загружаем в map
 		for _, note := range notes {
+			if note.Tags == nil {
+				note.Tags = []domain.Tag{}
+			}
 			r.notes[note.ID] = note
 		}
 	}
@@ -88,18 +203,8 @@ func (r *JSONRepository) saveToFile() error {
 		notes = append(notes, note)
 	}
 
-	// Сериализуем в JSON с отступами
-	data, err := json.MarshalIndent(notes, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %w", err)
-	}
-
-	// Записываем в файл
-	if err := os.WriteFile(r.filename, data, 0644); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
-	}
-
-	return nil
+	// Сериализуем в JSON с отступами и записываем в файл
+	return writeJSONFile(r.filename, notes)
 }
 
 // Create создает новую заметку
@@ -107,55 +212,136 @@ func (r *JSONRepository) Create(note *domain.Note) (*domain.Note, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	// Устанавливаем ID и временные метки
-	note.ID = r.nextID
-	now := time.Now()
-	note.CreatedAt = now
-	note.UpdatedAt = now
-
-	// Сохраняем в map
-	r.notes[note.ID] = note
-	r.nextID++
+	if err := r.createNote(note); err != nil {
+		return nil, err
+	}
 
 	// Сохраняем в файл
 	if err := r.saveToFile(); err != nil {
 		delete(r.notes, note.ID) // Откатываем изменение в случае ошибки
 		return nil, err
 	}
+	r.index.Add(note.ID, note.Language, note.Title, note.Content)
 
 	return note, nil
 }
 
-// GetAll возвращает заметки с пагинацией
-func (r *JSONRepository) GetAll(limit, offset int) ([]*domain.Note, int, error) {
+// createNote добавляет новую заметку в память без сохранения в файл (вызывать под блокировкой)
+func (r *JSONRepository) createNote(note *domain.Note) error {
+	// Блокнот должен существовать
+	if note.NotebookID != nil {
+		if _, exists := r.notebooks[*note.NotebookID]; !exists {
+			return ErrNotebookNotFound
+		}
+	}
+
+	// Устанавливаем ID и временные метки; заданные метки (при импорте) сохраняются, как в PostgreSQL
+	note.ID = r.nextID
+	now := time.Now()
+	if note.CreatedAt.IsZero() {
+		note.CreatedAt = now
+	}
+	if note.UpdatedAt.IsZero() {
+		note.UpdatedAt = now
+	}
+	if note.Tags == nil {
+		note.Tags = []domain.Tag{}
+	}
+	note.Language = search.DetectLanguage(note.Title, note.Content)
+	note.Version = 1
+
+	// Сохраняем в map копию: переданная заметка остается у вызывающего кода
+	r.notes[note.ID] = copyNote(note)
+	r.nextID++
+
+	return nil
+}
+
+// GetAll возвращает заметки с фильтрацией, сортировкой и пагинацией
+func (r *JSONRepository) GetAll(opts domain.NoteListOptions) ([]*domain.Note, int, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	// Общее количество записей
-	total := len(r.notes)
-
-	// Получаем все заметки в нужном порядке
+	// Получаем все подходящие под фильтр заметки
 	allNotes := make([]*domain.Note, 0, len(r.notes))
 	for _, note := range r.notes {
-		allNotes = append(allNotes, note)
+		if !isDeleted(note) && matchesFilter(note, opts.Filter) {
+			allNotes = append(allNotes, note)
+		}
 	}
 
-	// Сортируем по created_at DESC (новые первыми)
+	// Общее количество записей
+	total := len(allNotes)
+
+	// Сортируем в запрошенном порядке
+	fields := opts.NoteSort()
 	sort.Slice(allNotes, func(i, j int) bool {
-		return allNotes[i].CreatedAt.After(allNotes[j].CreatedAt)
+		return compareNotes(allNotes[i], allNotes[j], fields) < 0
 	})
 
 	// Применяем пагинацию
-	start := offset
-	end := offset + limit
-	if start > len(allNotes) {
-		start = len(allNotes)
+	if opts.Cursor != nil {
+		return copyNotes(cursorPage(allNotes, opts.Cursor, fields, opts.Limit)), total, nil
 	}
-	if end > len(allNotes) {
-		end = len(allNotes)
+	start := min(opts.Offset, total)
+	end := min(opts.Offset+opts.Limit, total)
+
+	return copyNotes(allNotes[start:end]), total, nil
+}
+
+// copyNotes возвращает копии заметок (вызывать под блокировкой)
+func copyNotes(notes []*domain.Note) []*domain.Note {
+	copies := make([]*domain.Note, len(notes))
+	for i, note := range notes {
+		copies[i] = copyNote(note)
 	}
+	return copies
+}
+
+// All перебирает копии заметок, сделанные в начале перебора: изменения во время перебора в него не попадают,
+// а блокировка не держится, пока потребитель обрабатывает заметки
+func (r *JSONRepository) All(filter domain.NoteFilter, sortFields []domain.SortField) iter.Seq2[*domain.Note, error] {
+	return func(yield func(*domain.Note, error) bool) {
+		r.mu.RLock()
+		snapshot := make([]*domain.Note, 0, len(r.notes))
+		for _, note := range r.notes {
+			if !isDeleted(note) && matchesFilter(note, filter) {
+				snapshot = append(snapshot, copyNote(note))
+			}
+		}
+		r.mu.RUnlock()
+
+		fields := domain.NoteListOptions{Sort: sortFields}.NoteSort()
+		sort.Slice(snapshot, func(i, j int) bool {
+			return compareNotes(snapshot[i], snapshot[j], fields) < 0
+		})
 
-	return allNotes[start:end], total, nil
+		for _, note := range snapshot {
+			if !yield(note, nil) {
+				return
+			}
+		}
+	}
+}
+
+// cursorPage возвращает до limit отсортированных заметок сразу после позиции курсора
+// или сразу перед ней
+func cursorPage(notes []*domain.Note, cursor *domain.NoteCursor, fields []domain.SortField, limit int) []*domain.Note {
+	pivot := &domain.Note{ID: cursor.ID, CreatedAt: cursor.CreatedAt}
+
+	if cursor.Before {
+		// Конец заметок, идущих строго перед позицией курсора
+		end := sort.Search(len(notes), func(i int) bool {
+			return compareNotes(notes[i], pivot, fields) >= 0
+		})
+		return notes[max(0, end-limit):end]
+	}
+
+	// Первая заметка, идущая строго после позиции курсора
+	start := sort.Search(len(notes), func(i int) bool {
+		return compareNotes(notes[i], pivot, fields) > 0
+	})
+	return notes[start:min(start+limit, len(notes))]
 }
 
 // GetByID возвращает заметку по ID
@@ -163,55 +349,428 @@ func (r *JSONRepository) GetByID(id int64) (*domain.Note, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	note, exists := r.notes[id]
+	note, exists := r.liveNote(id)
 	if !exists {
 		return nil, ErrNoteNotFound
 	}
 
-	return note, nil
+	return copyNote(note), nil
 }
 
 // Update обновляет заметку
 func (r *JSONRepository) Update(id int64, note *domain.Note) (*domain.Note, error) {
+	return r.Patch(id, domain.NotePatchFromNote(note))
+}
+
+// Patch изменяет переданные поля заметки
+func (r *JSONRepository) Patch(id int64, patch *domain.NotePatch) (*domain.Note, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	existingNote, previous, err := r.patchNote(id, patch)
+	if err != nil {
+		return nil, err
+	}
+	if previous == nil {
+		return copyNote(existingNote), nil
+	}
+
+	// Сохраняем в файлы
+	err = r.saveToFile()
+	if err == nil {
+		err = r.saveRevisions()
+	}
+	if err != nil {
+		// Откатываем изменения в случае ошибки
+		*existingNote = *previous
+		r.dropLastRevision(id)
+		return nil, err
+	}
+	r.index.Add(id, existingNote.Language, existingNote.Title, existingNote.Content)
+
+	return copyNote(existingNote), nil
+}
+
+// patchNote изменяет заметку в памяти без сохранения в файлы (вызывать под блокировкой).
+// Возвращает заметку и ее состояние до изменения; previous == nil, если ничего не изменилось.
+func (r *JSONRepository) patchNote(id int64, patch *domain.NotePatch) (note, previous *domain.Note, err error) {
 	// Проверяем существование заметки
-	existingNote, exists := r.notes[id]
+	existingNote, exists := r.liveNote(id)
 	if !exists {
-		return nil, ErrNoteNotFound
+		return nil, nil, ErrNoteNotFound
+	}
+	if patch.Version != 0 && patch.Version != existingNote.Version {
+		return nil, nil, ErrNoteVersionMismatch
 	}
 
+	// Если ничего не меняется, заметку не трогаем
+	if !patch.Changes(existingNote) {
+		return existingNote, nil, nil
+	}
+
+	// Сохраняем прежнюю версию
+	r.addRevision(existingNote)
+	saved := *existingNote
+
 	// Обновляем поля
-	existingNote.Title = note.Title
-	existingNote.Content = note.Content
+	patch.Apply(existingNote)
+	existingNote.Language = search.DetectLanguage(existingNote.Title, existingNote.Content)
 	existingNote.UpdatedAt = time.Now()
+	existingNote.Version++
+
+	return existingNote, &saved, nil
+}
+
+// Delete перемещает заметку в корзину
+func (r *JSONRepository) Delete(id int64, version int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	note, err := r.deleteNote(id, version)
+	if err != nil {
+		return err
+	}
 
 	// Сохраняем в файл
 	if err := r.saveToFile(); err != nil {
+		note.DeletedAt = gorm.DeletedAt{} // Откатываем изменение в случае ошибки
+		return err
+	}
+	r.index.Remove(id)
+
+	return nil
+}
+
+// deleteNote помечает заметку удаленной в памяти без сохранения в файл (вызывать под блокировкой)
+func (r *JSONRepository) deleteNote(id int64, version int64) (*domain.Note, error) {
+	// Проверяем существование и версию заметки
+	note, exists := r.liveNote(id)
+	if !exists {
+		return nil, ErrNoteNotFound
+	}
+	if version != 0 && version != note.Version {
+		return nil, ErrNoteVersionMismatch
+	}
+
+	// Помечаем заметку удаленной, как это делает gorm в PostgreSQL
+	note.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+
+	return note, nil
+}
+
+// MoveNote перемещает заметку в другой блокнот
+func (r *JSONRepository) MoveNote(id int64, notebookID *int64) (*domain.Note, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	note, exists := r.liveNote(id)
+	if !exists {
+		return nil, ErrNoteNotFound
+	}
+	if notebookID != nil {
+		if _, exists := r.notebooks[*notebookID]; !exists {
+			return nil, ErrNotebookNotFound
+		}
+	}
+
+	previous := *note
+	note.NotebookID = notebookID
+	note.UpdatedAt = time.Now()
+	note.Version++
+
+	if err := r.saveToFile(); err != nil {
+		*note = previous // Откатываем изменение в случае ошибки
 		return nil, err
 	}
 
-	return existingNote, nil
+	return copyNote(note), nil
 }
 
-// Delete удаляет заметку
-func (r *JSONRepository) Delete(id int64) error {
+// Search ищет заметки по заголовку и содержимому через инвертированный индекс
+func (r *JSONRepository) Search(query string, limit, offset int) ([]*domain.SearchResult, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	hits := r.index.Search(query)
+
+	// При равной релевантности новые заметки первыми
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return r.notes[hits[i].ID].CreatedAt.After(r.notes[hits[j].ID].CreatedAt)
+	})
+
+	total := len(hits)
+
+	// Применяем пагинацию
+	start := min(offset, total)
+	end := min(offset+limit, total)
+
+	results := make([]*domain.SearchResult, 0, end-start)
+	for _, hit := range hits[start:end] {
+		note := r.notes[hit.ID]
+		results = append(results, &domain.SearchResult{
+			Note:    copyNote(note),
+			Rank:    hit.Score,
+			Snippet: search.Snippet(note.Content, note.Language, search.ParseQuery(query, note.Language).Include),
+		})
+	}
+
+	return results, total, nil
+}
+
+// ListTags возвращает все используемые теги с количеством заметок
+func (r *JSONRepository) ListTags() ([]domain.TagUsage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, note := range r.notes {
+		if isDeleted(note) {
+			continue
+		}
+		for _, tag := range note.Tags {
+			counts[tag.Name]++
+		}
+	}
+
+	usages := make([]domain.TagUsage, 0, len(counts))
+	for name, count := range counts {
+		usages = append(usages, domain.TagUsage{Name: name, Count: count})
+	}
+
+	// Сортируем по популярности, затем по имени
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].Count != usages[j].Count {
+			return usages[i].Count > usages[j].Count
+		}
+		return usages[i].Name < usages[j].Name
+	})
+
+	return usages, nil
+}
+
+// RenameTag переименовывает тег во всех заметках
+func (r *JSONRepository) RenameTag(oldName, newName string) (*domain.TagUsage, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	// Проверяем существование заметки
-	if _, exists := r.notes[id]; !exists {
-		return ErrNoteNotFound
+	if r.tagUsage(oldName) == 0 {
+		return nil, ErrTagNotFound
+	}
+	if oldName != newName && r.tagUsage(newName) > 0 {
+		return nil, ErrTagExists
 	}
 
-	// Удаляем из map
-	delete(r.notes, id)
+	now := time.Now()
+	previous := make(map[int64]domain.Note)
+	for _, note := range r.notes {
+		if oldName == newName || !hasTag(note, oldName) {
+			continue
+		}
+		previous[note.ID] = *note
+
+		// Новый срез: снимок для отката ссылается на прежние теги.
+		// Заметка в корзине может уже иметь newName, тогда тег остается один.
+		tags := make([]domain.Tag, 0, len(note.Tags))
+		for _, tag := range note.Tags {
+			if tag.Name == oldName {
+				tag.Name = newName
+			}
+			if !slices.ContainsFunc(tags, func(t domain.Tag) bool { return t.Name == tag.Name }) {
+				tags = append(tags, tag)
+			}
+		}
+		note.Tags = tags
+		note.UpdatedAt = now
+		note.Version++
+	}
 
-	// Сохраняем в файл
 	if err := r.saveToFile(); err != nil {
-		return err
+		r.restoreNotes(previous) // Откатываем изменение в случае ошибки
+		return nil, err
 	}
 
-	return nil
+	return &domain.TagUsage{Name: newName, Count: r.tagUsage(newName)}, nil
+}
+
+// MergeTags заменяет теги sources на target во всех заметках
+func (r *JSONRepository) MergeTags(sources []string, target string) (*domain.TagUsage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, source := range sources {
+		if r.tagUsage(source) == 0 {
+			return nil, ErrTagNotFound
+		}
+	}
+
+	merged := make(map[string]bool, len(sources))
+	for _, source := range sources {
+		merged[source] = true
+	}
+
+	now := time.Now()
+	previous := make(map[int64]domain.Note)
+	for _, note := range r.notes {
+		tags := make([]domain.Tag, 0, len(note.Tags))
+		hasTarget, changed := false, false
+		for _, tag := range note.Tags {
+			if merged[tag.Name] && tag.Name != target {
+				changed = true
+				continue
+			}
+			if tag.Name == target {
+				hasTarget = true
+			}
+			tags = append(tags, tag)
+		}
+		if changed {
+			if !hasTarget {
+				tags = append(tags, domain.Tag{Name: target})
+			}
+			previous[note.ID] = *note
+			note.Tags = tags
+			note.UpdatedAt = now
+			note.Version++
+		}
+	}
+
+	if err := r.saveToFile(); err != nil {
+		r.restoreNotes(previous) // Откатываем изменение в случае ошибки
+		return nil, err
+	}
+
+	return &domain.TagUsage{Name: target, Count: r.tagUsage(target)}, nil
+}
+
+// restoreNotes возвращает заметкам состояние из снимка (вызывать под блокировкой)
+func (r *JSONRepository) restoreNotes(previous map[int64]domain.Note) {
+	for id, note := range previous {
+		*r.notes[id] = note
+	}
+}
+
+// tagUsage возвращает количество заметок с тегом (вызывать под блокировкой)
+func (r *JSONRepository) tagUsage(name string) int {
+	count := 0
+	for _, note := range r.notes {
+		if !isDeleted(note) && hasTag(note, name) {
+			count++
+		}
+	}
+	return count
+}
+
+// hasTag проверяет, есть ли у заметки тег
+func hasTag(note *domain.Note, name string) bool {
+	for _, tag := range note.Tags {
+		if tag.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesFilter проверяет, подходит ли заметка под фильтр
+func matchesFilter(note *domain.Note, filter domain.NoteFilter) bool {
+	if filter.IDs != nil && !slices.Contains(filter.IDs, note.ID) {
+		return false
+	}
+	if filter.NotebookIDs != nil && !inNotebooks(note, filter.NotebookIDs) {
+		return false
+	}
+	if filter.Query != nil && !matchesQuery(note, filter.Query) {
+		return false
+	}
+	if filter.CreatedAfter != nil && !note.CreatedAt.After(*filter.CreatedAfter) {
+		return false
+	}
+	if filter.CreatedBefore != nil && !note.CreatedAt.Before(*filter.CreatedBefore) {
+		return false
+	}
+	if filter.UpdatedSince != nil && note.UpdatedAt.Before(*filter.UpdatedSince) {
+		return false
+	}
+	if filter.TitlePrefix != "" && !strings.HasPrefix(strings.ToLower(note.Title), strings.ToLower(filter.TitlePrefix)) {
+		return false
+	}
+
+	if len(filter.Tags) == 0 {
+		return true
+	}
+
+	for _, name := range filter.Tags {
+		found := hasTag(note, name)
+		if filter.MatchAllTags && !found {
+			return false
+		}
+		if !filter.MatchAllTags && found {
+			return true
+		}
+	}
+
+	return filter.MatchAllTags
+}
+
+// compareNotes сравнивает заметки по полям сортировки.
+// Заголовки сравниваются побайтово, как COLLATE "C" в PostgreSQL.
+func compareNotes(a, b *domain.Note, fields []domain.SortField) int {
+	for _, field := range fields {
+		var result int
+		switch field.Field {
+		case domain.SortByID:
+			result = cmp.Compare(a.ID, b.ID)
+		case domain.SortByTitle:
+			result = strings.Compare(a.Title, b.Title)
+		case domain.SortByCreatedAt:
+			result = a.CreatedAt.Compare(b.CreatedAt)
+		case domain.SortByUpdatedAt:
+			result = a.UpdatedAt.Compare(b.UpdatedAt)
+		}
+		if field.Desc {
+			result = -result
+		}
+		if result != 0 {
+			return result
+		}
+	}
+	return 0
+}
+
+// inNotebooks проверяет, лежит ли заметка в одном из блокнотов
+func inNotebooks(note *domain.Note, ids []int64) bool {
+	if note.NotebookID == nil {
+		return false
+	}
+	for _, id := range ids {
+		if *note.NotebookID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// copyNote возвращает копию заметки (вызывать под блокировкой). Наружу отдаются только копии:
+// заметки в памяти меняются под блокировкой, а вызывающий код читает результат без нее,
+// например при публикации события.
+func copyNote(note *domain.Note) *domain.Note {
+	copied := *note
+	copied.Tags = slices.Clone(note.Tags)
+	return &copied
+}
+
+// liveNote возвращает заметку, если она существует и не в корзине (вызывать под блокировкой)
+func (r *JSONRepository) liveNote(id int64) (*domain.Note, bool) {
+	note, exists := r.notes[id]
+	if !exists || isDeleted(note) {
+		return nil, false
+	}
+	return note, true
+}
+
+// isDeleted проверяет, находится ли заметка в корзине
+func isDeleted(note *domain.Note) bool {
+	return note.DeletedAt.Valid
 }
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"sort"
+
+	"notes-api/internal/domain"
+)
+
+// revisionsFile - имя файла с ревизиями рядом с файлом заметок
+const revisionsFile = "revisions.json"
+
+// loadRevisions загружает ревизии из JSON файла
+func (r *JSONRepository) loadRevisions() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var revisions []*domain.Revision
+	if err := readJSONFile(r.siblingFile(revisionsFile), &revisions); err != nil {
+		return err
+	}
+
+	for _, revision := range revisions {
+		r.revisions[revision.NoteID] = append(r.revisions[revision.NoteID], revision)
+	}
+	for _, noteRevisions := range r.revisions {
+		sort.Slice(noteRevisions, func(i, j int) bool {
+			return noteRevisions[i].Number < noteRevisions[j].Number
+		})
+	}
+
+	return nil
+}
+
+// saveRevisions сохраняет ревизии в JSON файл
+func (r *JSONRepository) saveRevisions() error {
+	revisions := make([]*domain.Revision, 0)
+	for _, noteRevisions := range r.revisions {
+		revisions = append(revisions, noteRevisions...)
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		if revisions[i].NoteID != revisions[j].NoteID {
+			return revisions[i].NoteID < revisions[j].NoteID
+		}
+		return revisions[i].Number < revisions[j].Number
+	})
+
+	return writeJSONFile(r.siblingFile(revisionsFile), revisions)
+}
+
+// addRevision сохраняет текущее состояние заметки как новую ревизию (вызывать под блокировкой)
+func (r *JSONRepository) addRevision(note *domain.Note) {
+	number := 1
+	if existing := r.revisions[note.ID]; len(existing) > 0 {
+		number = existing[len(existing)-1].Number + 1
+	}
+	r.revisions[note.ID] = append(r.revisions[note.ID], domain.NewRevision(note, number))
+}
+
+// dropLastRevision удаляет последнюю ревизию заметки при откате (вызывать под блокировкой)
+func (r *JSONRepository) dropLastRevision(noteID int64) {
+	existing := r.revisions[noteID]
+	if len(existing) > 0 {
+		r.revisions[noteID] = existing[:len(existing)-1]
+	}
+}
+
+// GetRevisions возвращает ревизии заметки, новые первыми
+func (r *JSONRepository) GetRevisions(noteID int64) ([]*domain.Revision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, exists := r.liveNote(noteID); !exists {
+		return nil, ErrNoteNotFound
+	}
+
+	existing := r.revisions[noteID]
+	revisions := make([]*domain.Revision, len(existing))
+	for i, revision := range existing {
+		revisions[len(existing)-1-i] = revision
+	}
+
+	return revisions, nil
+}
+
+// GetRevision возвращает ревизию заметки по номеру
+func (r *JSONRepository) GetRevision(noteID int64, number int) (*domain.Revision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, exists := r.liveNote(noteID); !exists {
+		return nil, ErrNoteNotFound
+	}
+
+	for _, revision := range r.revisions[noteID] {
+		if revision.Number == number {
+			return revision, nil
+		}
+	}
+
+	return nil, ErrRevisionNotFound
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"maps"
+	"sort"
+	"time"
+
+	"notes-api/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// GetTrash возвращает заметки из корзины, недавно удаленные первыми
+func (r *JSONRepository) GetTrash(limit, offset int) ([]*domain.Note, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	trashed := make([]*domain.Note, 0)
+	for _, note := range r.notes {
+		if isDeleted(note) {
+			trashed = append(trashed, note)
+		}
+	}
+
+	sort.Slice(trashed, func(i, j int) bool {
+		return trashed[i].DeletedAt.Time.After(trashed[j].DeletedAt.Time)
+	})
+
+	total := len(trashed)
+
+	// Применяем пагинацию
+	start := min(offset, total)
+	end := min(offset+limit, total)
+
+	return copyNotes(trashed[start:end]), total, nil
+}
+
+// RestoreNote возвращает заметку из корзины.
+// Если блокнот заметки был удален, заметка возвращается в корень.
+func (r *JSONRepository) RestoreNote(id int64) (*domain.Note, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	note, exists := r.notes[id]
+	if !exists || !isDeleted(note) {
+		return nil, ErrNoteNotFound
+	}
+
+	previous := *note
+	note.DeletedAt = gorm.DeletedAt{}
+	note.UpdatedAt = time.Now()
+	note.Version++
+	if note.NotebookID != nil {
+		if _, exists := r.notebooks[*note.NotebookID]; !exists {
+			note.NotebookID = nil
+		}
+	}
+
+	if err := r.saveToFile(); err != nil {
+		*note = previous // Откатываем изменение в случае ошибки
+		return nil, err
+	}
+	r.index.Add(id, note.Language, note.Title, note.Content)
+
+	return copyNote(note), nil
+}
+
+// PurgeNote удаляет заметку из корзины навсегда вместе с ее ревизиями
+func (r *JSONRepository) PurgeNote(id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	note, exists := r.notes[id]
+	if !exists || !isDeleted(note) {
+		return ErrNoteNotFound
+	}
+
+	return r.purge([]int64{id})
+}
+
+// PurgeTrash удаляет навсегда заметки, удаленные раньше deletedBefore
+func (r *JSONRepository) PurgeTrash(deletedBefore time.Time) ([]int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ids []int64
+	for id, note := range r.notes {
+		if isDeleted(note) && note.DeletedAt.Time.Before(deletedBefore) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	if err := r.purge(ids); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// purge удаляет заметки и их ревизии и сохраняет файлы (вызывать под блокировкой)
+func (r *JSONRepository) purge(ids []int64) error {
+	notes := make(map[int64]*domain.Note, len(ids))
+	revisions := make(map[int64][]*domain.Revision, len(ids))
+	for _, id := range ids {
+		notes[id] = r.notes[id]
+		if existing, ok := r.revisions[id]; ok {
+			revisions[id] = existing
+		}
+		delete(r.notes, id)
+		delete(r.revisions, id)
+	}
+
+	// Сохраняем в файлы
+	err := r.saveToFile()
+	if err == nil {
+		err = r.saveRevisions()
+	}
+	if err != nil {
+		// Откатываем изменения в случае ошибки
+		maps.Copy(r.notes, notes)
+		maps.Copy(r.revisions, revisions)
+		return err
+	}
+	return nil
+}
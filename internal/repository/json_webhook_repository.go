@@ -0,0 +1,416 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"time"
+
+	"notes-api/internal/domain"
+)
+
+// Имена файлов с вебхуками и журналом доставок рядом с файлом заметок
+const (
+	webhooksFile   = "webhooks.json"
+	deliveriesFile = "webhook_deliveries.jsonl"
+)
+
+// deliveriesCompactLines после скольких строк журнал доставок сжимается, если устаревших строк
+// в нем больше, чем актуальных записей
+const deliveriesCompactLines = 1000
+
+// deliveryLine строка файла журнала доставок. Каждое сохранение дописывает запись целиком,
+// при загрузке последняя строка с тем же ID заменяет предыдущие. Keep у новой записи повторяет
+// вытеснение старых записей вебхука так же, как оно произошло в памяти.
+type deliveryLine struct {
+	*domain.WebhookDelivery
+	Keep int `json:"keep,omitempty"`
+}
+
+// loadWebhooks загружает вебхуки и журнал доставок из JSON файлов
+func (r *JSONRepository) loadWebhooks() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var webhooks []*domain.Webhook
+	if err := readJSONFile(r.siblingFile(webhooksFile), &webhooks); err != nil {
+		return err
+	}
+	for _, webhook := range webhooks {
+		r.webhooks[webhook.ID] = webhook
+		if webhook.ID >= r.nextWebhookID {
+			r.nextWebhookID = webhook.ID + 1
+		}
+	}
+
+	return r.loadDeliveries()
+}
+
+// loadDeliveries восстанавливает журнал доставок, повторяя записанные в файл изменения
+func (r *JSONRepository) loadDeliveries() error {
+	data, err := os.ReadFile(r.siblingFile(deliveriesFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(data), []byte("\n"))
+	for i, raw := range lines {
+		if len(raw) == 0 {
+			continue
+		}
+		var line deliveryLine
+		if err := json.Unmarshal(raw, &line); err != nil || line.WebhookDelivery == nil {
+			if i == len(lines)-1 {
+				// Последняя строка могла остаться недописанной при аварийной остановке
+				break
+			}
+			return fmt.Errorf("failed to parse delivery log line %d: %v", i+1, err)
+		}
+		if _, exists := r.webhooks[line.WebhookID]; !exists {
+			continue // Вебхук удален
+		}
+		r.applyDelivery(line.WebhookDelivery, line.Keep)
+		if line.ID >= r.nextDeliveryID {
+			r.nextDeliveryID = line.ID + 1
+		}
+	}
+	r.deliveryLines = len(lines)
+
+	return nil
+}
+
+// saveWebhooks сохраняет вебхуки в JSON файл
+func (r *JSONRepository) saveWebhooks() error {
+	webhooks := make([]*domain.Webhook, 0, len(r.webhooks))
+	for _, webhook := range r.webhooks {
+		webhooks = append(webhooks, webhook)
+	}
+
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].ID < webhooks[j].ID
+	})
+
+	return writeJSONFile(r.siblingFile(webhooksFile), webhooks)
+}
+
+// applyDelivery добавляет новую запись в журнал вебхука, вытесняя самые старые сверх keep,
+// или заменяет существующую. ID новых записей растут, поэтому запись с ID меньше последнего,
+// которой нет в журнале, уже вытеснена; такая запись пропускается и возвращается false.
+func (r *JSONRepository) applyDelivery(delivery *domain.WebhookDelivery, keep int) bool {
+	entries := r.deliveries[delivery.WebhookID]
+	if len(entries) == 0 || delivery.ID > entries[len(entries)-1].ID {
+		entries = append(slices.Clip(entries), delivery)
+		if keep > 0 && len(entries) > keep {
+			entries = entries[len(entries)-keep:]
+		}
+		r.deliveries[delivery.WebhookID] = entries
+		return true
+	}
+
+	i := slices.IndexFunc(entries, func(d *domain.WebhookDelivery) bool { return d.ID == delivery.ID })
+	if i < 0 {
+		return false
+	}
+	entries = slices.Clone(entries)
+	entries[i] = delivery
+	r.deliveries[delivery.WebhookID] = entries
+	return true
+}
+
+// appendDelivery дописывает запись в файл журнала доставок. Когда устаревших строк в файле
+// становится больше, чем актуальных записей, файл вместо этого переписывается из памяти.
+func (r *JSONRepository) appendDelivery(line deliveryLine) error {
+	if r.deliveriesBroken || r.deliveryLines >= max(deliveriesCompactLines, 2*r.deliveryCount()) {
+		return r.compactDeliveries()
+	}
+
+	data, err := json.Marshal(line)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	file, err := os.OpenFile(r.siblingFile(deliveriesFile), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return &domain.UnavailableError{Err: fmt.Errorf("failed to open file: %w", err)}
+	}
+	_, err = file.Write(append(data, '\n'))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// В файле могла остаться недописанная строка: следующее сохранение перепишет его целиком
+		r.deliveriesBroken = true
+		return &domain.UnavailableError{Err: fmt.Errorf("failed to write file: %w", err)}
+	}
+
+	r.deliveryLines++
+	return nil
+}
+
+// compactDeliveries переписывает файл журнала доставок актуальными записями. Новый файл
+// пишется рядом и заменяет старый, поэтому при ошибке прежний журнал остается целым.
+func (r *JSONRepository) compactDeliveries() error {
+	var deliveries []*domain.WebhookDelivery
+	for _, entries := range r.deliveries {
+		deliveries = append(deliveries, entries...)
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].ID < deliveries[j].ID
+	})
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, delivery := range deliveries {
+		if err := encoder.Encode(deliveryLine{WebhookDelivery: delivery}); err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+	}
+
+	filename := r.siblingFile(deliveriesFile)
+	if err := os.WriteFile(filename+".tmp", buf.Bytes(), 0644); err != nil {
+		return &domain.UnavailableError{Err: fmt.Errorf("failed to write file: %w", err)}
+	}
+	if err := os.Rename(filename+".tmp", filename); err != nil {
+		return &domain.UnavailableError{Err: fmt.Errorf("failed to replace file: %w", err)}
+	}
+
+	r.deliveryLines = len(deliveries)
+	r.deliveriesBroken = false
+	return nil
+}
+
+// deliveryCount возвращает число записей во всех журналах доставок
+func (r *JSONRepository) deliveryCount() int {
+	count := 0
+	for _, entries := range r.deliveries {
+		count += len(entries)
+	}
+	return count
+}
+
+// copyWebhook возвращает копию вебхука: доставка событий читает вебхуки параллельно с их изменением
+func copyWebhook(webhook *domain.Webhook) *domain.Webhook {
+	copied := *webhook
+	copied.Events = slices.Clone(webhook.Events)
+	return &copied
+}
+
+// CreateWebhook создает новый вебхук
+func (r *JSONRepository) CreateWebhook(webhook *domain.Webhook) (*domain.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	webhook.ID = r.nextWebhookID
+	now := time.Now()
+	webhook.CreatedAt = now
+	webhook.UpdatedAt = now
+
+	r.webhooks[webhook.ID] = copyWebhook(webhook)
+	r.nextWebhookID++
+
+	if err := r.saveWebhooks(); err != nil {
+		delete(r.webhooks, webhook.ID) // Откатываем изменение в случае ошибки
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// GetAllWebhooks возвращает все вебхуки в порядке создания
+func (r *JSONRepository) GetAllWebhooks() ([]*domain.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	webhooks := make([]*domain.Webhook, 0, len(r.webhooks))
+	for _, webhook := range r.webhooks {
+		webhooks = append(webhooks, copyWebhook(webhook))
+	}
+
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].ID < webhooks[j].ID
+	})
+
+	return webhooks, nil
+}
+
+// GetWebhookByID возвращает вебхук по ID
+func (r *JSONRepository) GetWebhookByID(id int64) (*domain.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	webhook, exists := r.webhooks[id]
+	if !exists {
+		return nil, ErrWebhookNotFound
+	}
+
+	return copyWebhook(webhook), nil
+}
+
+// UpdateWebhook меняет адрес, события, ключ и состояние вебхука
+func (r *JSONRepository) UpdateWebhook(id int64, webhook *domain.Webhook) (*domain.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, exists := r.webhooks[id]
+	if !exists {
+		return nil, ErrWebhookNotFound
+	}
+
+	updated := copyWebhook(existing)
+	updated.URL = webhook.URL
+	updated.Events = slices.Clone(webhook.Events)
+	updated.Secret = webhook.Secret
+	updated.Active = webhook.Active
+	if webhook.Active {
+		updated.FailureCount = 0
+		updated.DisabledAt = nil
+	}
+	updated.UpdatedAt = time.Now()
+
+	r.webhooks[id] = updated
+	if err := r.saveWebhooks(); err != nil {
+		r.webhooks[id] = existing // Откатываем изменение в случае ошибки
+		return nil, err
+	}
+
+	return copyWebhook(updated), nil
+}
+
+// DeleteWebhook удаляет вебхук и его журнал доставок
+func (r *JSONRepository) DeleteWebhook(id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, exists := r.webhooks[id]
+	if !exists {
+		return ErrWebhookNotFound
+	}
+
+	delete(r.webhooks, id)
+	if err := r.saveWebhooks(); err != nil {
+		r.webhooks[id] = existing // Откатываем изменение в случае ошибки
+		return err
+	}
+
+	if _, ok := r.deliveries[id]; ok {
+		delete(r.deliveries, id)
+		// ID удаленного вебхука может достаться новому, поэтому его записи убираются из файла сразу.
+		// Вебхук уже удален: если переписать файл не удалось, это сделает следующее сохранение.
+		if err := r.compactDeliveries(); err != nil {
+			r.deliveriesBroken = true
+		}
+	}
+
+	return nil
+}
+
+// RecordWebhookFailure учитывает неудачную доставку и отключает вебхук после disableAfter неудач подряд
+func (r *JSONRepository) RecordWebhookFailure(id int64, disableAfter int) (*domain.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, exists := r.webhooks[id]
+	if !exists {
+		return nil, ErrWebhookNotFound
+	}
+
+	updated := copyWebhook(existing)
+	updated.FailureCount++
+	if updated.Active && disableAfter > 0 && updated.FailureCount >= disableAfter {
+		now := time.Now()
+		updated.Active = false
+		updated.DisabledAt = &now
+	}
+
+	r.webhooks[id] = updated
+	if err := r.saveWebhooks(); err != nil {
+		r.webhooks[id] = existing // Откатываем изменение в случае ошибки
+		return nil, err
+	}
+
+	return copyWebhook(updated), nil
+}
+
+// ResetWebhookFailures обнуляет счетчик неудачных доставок
+func (r *JSONRepository) ResetWebhookFailures(id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, exists := r.webhooks[id]
+	if !exists {
+		return ErrWebhookNotFound
+	}
+	if existing.FailureCount == 0 {
+		return nil
+	}
+
+	updated := copyWebhook(existing)
+	updated.FailureCount = 0
+
+	r.webhooks[id] = updated
+	if err := r.saveWebhooks(); err != nil {
+		r.webhooks[id] = existing // Откатываем изменение в случае ошибки
+		return err
+	}
+
+	return nil
+}
+
+// SaveWebhookDelivery создает или обновляет запись журнала доставки.
+// Самые старые записи сверх keep удаляются.
+func (r *JSONRepository) SaveWebhookDelivery(delivery *domain.WebhookDelivery, keep int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.webhooks[delivery.WebhookID]; !exists {
+		return ErrWebhookNotFound
+	}
+
+	previous := r.deliveries[delivery.WebhookID]
+	saved := *delivery
+	line := deliveryLine{WebhookDelivery: &saved}
+	if delivery.ID == 0 {
+		saved.ID = r.nextDeliveryID
+		line.Keep = keep
+	}
+	if !r.applyDelivery(&saved, line.Keep) {
+		// Запись уже вытеснена из журнала более новыми
+		return nil
+	}
+
+	if err := r.appendDelivery(line); err != nil {
+		r.deliveries[delivery.WebhookID] = previous // Откатываем изменение в случае ошибки
+		return err
+	}
+
+	if delivery.ID == 0 {
+		delivery.ID = saved.ID
+		r.nextDeliveryID++
+	}
+	return nil
+}
+
+// GetWebhookDeliveries возвращает страницу журнала доставок вебхука, новые записи первыми
+func (r *JSONRepository) GetWebhookDeliveries(webhookID int64, limit, offset int) ([]*domain.WebhookDelivery, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, exists := r.webhooks[webhookID]; !exists {
+		return nil, 0, ErrWebhookNotFound
+	}
+
+	entries := r.deliveries[webhookID]
+	total := len(entries)
+	deliveries := make([]*domain.WebhookDelivery, 0, limit)
+	for i := total - 1 - offset; i >= 0 && len(deliveries) < limit; i-- {
+		delivery := *entries[i]
+		deliveries = append(deliveries, &delivery)
+	}
+
+	return deliveries, total, nil
+}
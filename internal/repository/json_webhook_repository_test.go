@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"notes-api/internal/domain"
+)
+
+func TestJSONDeliveryLog(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "notes.json")
+	repo, err := NewJSONRepository(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	webhook, err := repo.CreateWebhook(&domain.Webhook{URL: "https://example.com/hook", Active: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deleted, err := repo.CreateWebhook(&domain.Webhook{URL: "https://example.com/deleted", Active: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const keep = 3
+	var last *domain.WebhookDelivery
+	// Записи удаленного вебхука убираются из файла, чтобы не достаться вебхуку с тем же ID
+	if err := repo.SaveWebhookDelivery(&domain.WebhookDelivery{WebhookID: deleted.ID, EventID: 1}, keep); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.DeleteWebhook(deleted.ID); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 5; i++ {
+		last = &domain.WebhookDelivery{WebhookID: webhook.ID, EventID: int64(i), Status: domain.DeliveryPending}
+		if err := repo.SaveWebhookDelivery(last, keep); err != nil {
+			t.Fatal(err)
+		}
+	}
+	last.Status = domain.DeliverySucceeded
+	last.Attempts = 1
+	if err := repo.SaveWebhookDelivery(last, keep); err != nil {
+		t.Fatal(err)
+	}
+
+	// Каждое сохранение дописывает одну строку, файл не переписывается
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(filename), deliveriesFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := bytes.Count(data, []byte("\n")); lines != 6 {
+		t.Errorf("delivery log has %d lines, want 6", lines)
+	}
+
+	check := func(repo *JSONRepository) {
+		t.Helper()
+		deliveries, total, err := repo.GetWebhookDeliveries(webhook.ID, 10, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != keep {
+			t.Fatalf("got %d deliveries, want %d", total, keep)
+		}
+		for i, delivery := range deliveries {
+			if want := int64(5 - i); delivery.EventID != want {
+				t.Errorf("delivery %d has event %d, want %d", i, delivery.EventID, want)
+			}
+		}
+		if deliveries[0].ID != last.ID || deliveries[0].Status != domain.DeliverySucceeded || deliveries[0].Attempts != 1 {
+			t.Errorf("latest delivery = %+v, want updated delivery %d", deliveries[0], last.ID)
+		}
+		if got := repo.deliveryCount(); got != keep {
+			t.Errorf("delivery log holds %d entries, want %d", got, keep)
+		}
+	}
+
+	reloaded, err := NewJSONRepository(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(reloaded)
+
+	// Сжатие оставляет только актуальные записи и не меняет журнал
+	if err := reloaded.compactDeliveries(); err != nil {
+		t.Fatal(err)
+	}
+	compacted, err := NewJSONRepository(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(compacted)
+	if compacted.deliveryLines != keep {
+		t.Errorf("compacted delivery log has %d lines, want %d", compacted.deliveryLines, keep)
+	}
+
+	// Новая запись после перезагрузки получает следующий ID
+	next := &domain.WebhookDelivery{WebhookID: webhook.ID, EventID: 6}
+	if err := compacted.SaveWebhookDelivery(next, keep); err != nil {
+		t.Fatal(err)
+	}
+	if next.ID <= last.ID {
+		t.Errorf("new delivery ID = %d, want greater than %d", next.ID, last.ID)
+	}
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"errors"
+
+	"notes-api/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// errBulkRollback откатывает транзакцию атомарного пакетного изменения после ошибки операции
+var errBulkRollback = errors.New("bulk operation failed")
+
+// Bulk выполняет операции пакетного запроса в одной транзакции.
+// Без atomic каждая операция выполняется внутри точки сохранения, и ее ошибка откатывает только ее.
+func (r *PostgresRepository) Bulk(ops []domain.BulkNoteOp, atomic bool) ([]domain.BulkResult, error) {
+	results := make([]domain.BulkResult, len(ops))
+	for i, op := range ops {
+		results[i] = domain.BulkResult{Index: op.Index, Op: op.Op, ID: op.ID}
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		failed := false
+		for i, op := range ops {
+			if atomic && failed {
+				results[i].Err = ErrBulkNotApplied
+				continue
+			}
+
+			if !atomic {
+				if err := tx.SavePoint("bulk_op").Error; err != nil {
+					return err
+				}
+			}
+			if err := applyBulkOp(tx, op); err != nil {
+				results[i].Err = err
+				failed = true
+				if !atomic {
+					if err := tx.RollbackTo("bulk_op").Error; err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if op.Op == domain.BulkCreate {
+				results[i].ID = op.Note.ID
+			}
+		}
+
+		if atomic && failed {
+			return errBulkRollback
+		}
+		return nil
+	})
+
+	if errors.Is(err, errBulkRollback) {
+		for i := range results {
+			if results[i].Err == nil {
+				results[i] = domain.BulkResult{Index: ops[i].Index, Op: ops[i].Op, ID: ops[i].ID, Err: ErrBulkNotApplied}
+			}
+		}
+		return results, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Получаем созданные и измененные заметки после фиксации транзакции
+	var ids []int64
+	for _, result := range results {
+		if result.Err == nil && result.Op != domain.BulkDelete {
+			ids = append(ids, result.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return results, nil
+	}
+
+	var notes []*domain.Note
+	if err := r.db.Preload("Tags").Where("id IN ?", ids).Find(&notes).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]*domain.Note, len(notes))
+	for _, note := range notes {
+		byID[note.ID] = note
+	}
+	for i := range results {
+		if results[i].Err == nil && results[i].Op != domain.BulkDelete {
+			results[i].Note = byID[results[i].ID]
+		}
+	}
+
+	return results, nil
+}
+
+// applyBulkOp выполняет одну операцию в транзакции tx
+func applyBulkOp(tx *gorm.DB, op domain.BulkNoteOp) error {
+	switch op.Op {
+	case domain.BulkCreate:
+		return createNote(tx, op.Note)
+	case domain.BulkUpdate:
+		return patchNote(tx, op.ID, op.Patch)
+	default:
+		return deleteNote(tx, op.ID, op.Version)
+	}
+}
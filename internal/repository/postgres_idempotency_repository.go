@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"time"
+
+	"notes-api/internal/domain"
+
+	"gorm.io/gorm/clause"
+)
+
+func (r *PostgresRepository) ReserveIdempotencyKey(record *domain.IdempotencyKey) (*domain.IdempotencyKey, error) {
+	// Вставка занимает ключ атомарно: существующий ключ перезаписывается, только если его срок истек
+	result := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"request_hash", "status_code", "content_type", "etag", "body", "created_at", "expires_at",
+		}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "idempotency_keys.expires_at <= ?", Vars: []interface{}{record.CreatedAt}},
+		}},
+	}).Create(record)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil, nil
+	}
+
+	var existing domain.IdempotencyKey
+	if err := r.db.First(&existing, "key = ?", record.Key).Error; err != nil {
+		return nil, err
+	}
+	return &existing, nil
+}
+
+func (r *PostgresRepository) CompleteIdempotencyKey(record *domain.IdempotencyKey) error {
+	return r.db.Save(record).Error
+}
+
+func (r *PostgresRepository) DeleteIdempotencyKey(key string) error {
+	return r.db.Delete(&domain.IdempotencyKey{}, "key = ?", key).Error
+}
+
+func (r *PostgresRepository) PurgeIdempotencyKeys(now time.Time) (int, error) {
+	result := r.db.Where("expires_at <= ?", now).Delete(&domain.IdempotencyKey{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return int(result.RowsAffected), nil
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"notes-api/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// subtreeQuery рекурсивно выбирает ID блокнота и всех его потомков
+const subtreeQuery = `WITH RECURSIVE subtree AS (
+	SELECT id FROM notebooks WHERE id = ?
+	UNION ALL
+	SELECT n.id FROM notebooks n JOIN subtree s ON n.parent_id = s.id
+)
+SELECT id FROM subtree`
+
+func (r *PostgresRepository) CreateNotebook(notebook *domain.Notebook) (*domain.Notebook, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Родительский блокнот должен существовать
+		if notebook.ParentID != nil {
+			if err := notebookExists(tx, *notebook.ParentID); err != nil {
+				return err
+			}
+		}
+		return tx.Create(notebook).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return notebook, nil
+}
+
+func (r *PostgresRepository) GetAllNotebooks() ([]*domain.Notebook, error) {
+	var notebooks []*domain.Notebook
+	result := r.db.Order("name ASC, id ASC").Find(&notebooks)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return notebooks, nil
+}
+
+func (r *PostgresRepository) GetNotebookByID(id int64) (*domain.Notebook, error) {
+	var notebook domain.Notebook
+	result := r.db.First(&notebook, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, ErrNotebookNotFound
+		}
+		return nil, result.Error
+	}
+	return &notebook, nil
+}
+
+func (r *PostgresRepository) UpdateNotebook(id int64, notebook *domain.Notebook) (*domain.Notebook, error) {
+	result := r.db.Model(&domain.Notebook{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"name":       notebook.Name,
+		"updated_at": gorm.Expr("NOW()"),
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrNotebookNotFound
+	}
+
+	return r.GetNotebookByID(id)
+}
+
+func (r *PostgresRepository) MoveNotebook(id int64, parentID *int64) (*domain.Notebook, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		subtree, err := notebookSubtree(tx, id)
+		if err != nil {
+			return err
+		}
+
+		if parentID != nil {
+			if err := notebookExists(tx, *parentID); err != nil {
+				return err
+			}
+			// Нельзя переместить блокнот внутрь самого себя
+			for _, descendant := range subtree {
+				if descendant == *parentID {
+					return ErrNotebookCycle
+				}
+			}
+		}
+
+		return tx.Model(&domain.Notebook{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"parent_id":  parentID,
+			"updated_at": gorm.Expr("NOW()"),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetNotebookByID(id)
+}
+
+func (r *PostgresRepository) DeleteNotebook(id int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := notebookExists(tx, id); err != nil {
+			return err
+		}
+
+		// Блокнот не должен содержать вложенных блокнотов и заметок
+		var children, notes int64
+		if err := tx.Model(&domain.Notebook{}).Where("parent_id = ?", id).Count(&children).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&domain.Note{}).Where("notebook_id = ?", id).Count(&notes).Error; err != nil {
+			return err
+		}
+		if children > 0 || notes > 0 {
+			return ErrNotebookNotEmpty
+		}
+
+		return tx.Delete(&domain.Notebook{}, id).Error
+	})
+}
+
+func (r *PostgresRepository) GetNotebookSubtree(id int64) ([]int64, error) {
+	return notebookSubtree(r.db, id)
+}
+
+// notebookSubtree возвращает ID блокнота и всех его потомков
+func notebookSubtree(tx *gorm.DB, id int64) ([]int64, error) {
+	var ids []int64
+	if err := tx.Raw(subtreeQuery, id).Scan(&ids).Error; err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, ErrNotebookNotFound
+	}
+	return ids, nil
+}
+
+// notebookExists проверяет существование блокнота
+func notebookExists(tx *gorm.DB, id int64) error {
+	var count int64
+	if err := tx.Model(&domain.Notebook{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrNotebookNotFound
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"strings"
+
+	"notes-api/internal/domain"
+)
+
+// likeEscaper экранирует спецсимволы шаблона LIKE
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// compileQuery переводит структурированный запрос в SQL условие с параметрами
+func compileQuery(node domain.QueryNode) (string, []any) {
+	switch node := node.(type) {
+	case domain.QueryAnd:
+		return compileQueryList(node.Nodes, " AND ")
+	case domain.QueryOr:
+		return compileQueryList(node.Nodes, " OR ")
+	case domain.QueryNot:
+		sql, args := compileQuery(node.Node)
+		return "NOT (" + sql + ")", args
+	case domain.QueryText:
+		pattern := "%" + likeEscaper.Replace(node.Value) + "%"
+		switch node.Field {
+		case domain.QueryFieldTitle:
+			return "notes.title ILIKE ?", []any{pattern}
+		case domain.QueryFieldContent:
+			return "notes.content ILIKE ?", []any{pattern}
+		default:
+			return "(notes.title ILIKE ? OR notes.content ILIKE ?)", []any{pattern, pattern}
+		}
+	case domain.QueryTag:
+		return `notes.id IN (SELECT note_tags.note_id FROM note_tags
+			JOIN tags ON tags.id = note_tags.tag_id WHERE tags.name = ?)`, []any{node.Name}
+	case domain.QueryNotebook:
+		return "notes.notebook_id = ?", []any{node.ID}
+	case domain.QueryTime:
+		column := "notes.created_at"
+		if node.Field == domain.QueryFieldUpdated {
+			column = "notes.updated_at"
+		}
+		return column + " " + node.Op + " ?", []any{node.Value}
+	default:
+		return "FALSE", nil
+	}
+}
+
+// compileQueryList объединяет условия через оператор
+func compileQueryList(nodes []domain.QueryNode, operator string) (string, []any) {
+	parts := make([]string, len(nodes))
+	var args []any
+	for i, node := range nodes {
+		sql, nodeArgs := compileQuery(node)
+		parts[i] = "(" + sql + ")"
+		args = append(args, nodeArgs...)
+	}
+	return strings.Join(parts, operator), args
+}
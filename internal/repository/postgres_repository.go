@@ -1,12 +1,22 @@
 package repository
 
 import (
+	"database/sql/driver"
+	"errors"
 	"fmt"
+	"io"
+	"iter"
+	"maps"
+	"net"
+	"slices"
+	"strings"
 
 	"notes-api/internal/domain"
+	"notes-api/internal/search"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 )
 
@@ -22,36 +32,110 @@ func NewPostgresRepository(dsn string) (*PostgresRepository, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %v", err)
 	}
+	if err := registerUnavailableCallbacks(db); err != nil {
+		return nil, err
+	}
 
 	// Автомиграция - создаст таблицу если её нет
-	if err := db.AutoMigrate(&domain.Note{}); err != nil {
+	if err := db.AutoMigrate(&domain.Note{}, &domain.Tag{}, &domain.Notebook{}, &domain.Revision{}, &domain.IdempotencyKey{},
+		&domain.Webhook{}, &domain.WebhookDelivery{}); err != nil {
 		return nil, fmt.Errorf("failed to migrate database: %v", err)
 	}
+	if err := migrateSearch(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate search index: %v", err)
+	}
 
 	return &PostgresRepository{db: db}, nil
 }
 
+// registerUnavailableCallbacks добавляет после каждой операции gorm проверку,
+// которая оборачивает ошибки соединения с базой данных в domain.UnavailableError
+func registerUnavailableCallbacks(db *gorm.DB) error {
+	callbacks := db.Callback()
+	for _, register := range []func(string, func(*gorm.DB)) error{
+		callbacks.Create().Register,
+		callbacks.Query().Register,
+		callbacks.Update().Register,
+		callbacks.Delete().Register,
+		callbacks.Row().Register,
+		callbacks.Raw().Register,
+	} {
+		if err := register("notes:unavailable", markUnavailable); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markUnavailable помечает ошибку соединения как временную недоступность хранилища
+func markUnavailable(db *gorm.DB) {
+	if db.Error != nil && isConnectionError(db.Error) {
+		db.Error = &domain.UnavailableError{Err: db.Error}
+	}
+}
+
+// isConnectionError проверяет, вызвана ли ошибка потерей соединения с базой данных
+func isConnectionError(err error) bool {
+	var netErr net.Error
+	var unavailable *domain.UnavailableError
+	if errors.As(err, &unavailable) {
+		return false
+	}
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.ErrUnexpectedEOF) || errors.As(err, &netErr)
+}
+
 func (r *PostgresRepository) Create(note *domain.Note) (*domain.Note, error) {
-	result := r.db.Create(note)
-	if result.Error != nil {
-		return nil, result.Error
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		return createNote(tx, note)
+	})
+	if err != nil {
+		return nil, err
 	}
 	return note, nil
 }
 
-func (r *PostgresRepository) GetAll(limit, offset int) ([]*domain.Note, int, error) {
+// createNote создает заметку в транзакции tx
+func createNote(tx *gorm.DB, note *domain.Note) error {
+	note.Language = search.DetectLanguage(note.Title, note.Content)
+
+	// Блокнот должен существовать
+	if note.NotebookID != nil {
+		if err := notebookExists(tx, *note.NotebookID); err != nil {
+			return err
+		}
+	}
+
+	// Теги должны существовать до создания связей
+	if err := resolveTags(tx, note.Tags); err != nil {
+		return err
+	}
+	return tx.Create(note).Error
+}
+
+func (r *PostgresRepository) GetAll(opts domain.NoteListOptions) ([]*domain.Note, int, error) {
 	var notes []*domain.Note
 	var total int64
 
 	// Сначала получаем общее количество записей
-	if err := r.db.Model(&domain.Note{}).Count(&total).Error; err != nil {
+	if err := applyFilter(r.db.Model(&domain.Note{}), opts.Filter).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	// Затем получаем данные с пагинацией
-	result := r.db.Order("created_at DESC").Limit(limit).Offset(offset).Find(&notes)
-	if result.Error != nil {
-		return nil, 0, result.Error
+	// Затем получаем данные с сортировкой и пагинацией
+	fields := opts.NoteSort()
+	query := applyFilter(r.db.Preload("Tags"), opts.Filter).Limit(opts.Limit)
+	if opts.Cursor != nil {
+		query = applyCursor(query, opts.Cursor, fields)
+	} else {
+		query = query.Order(orderClause(fields)).Offset(opts.Offset)
+	}
+	if err := query.Find(&notes).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Страница перед курсором выбиралась в обратном порядке
+	if opts.Cursor != nil && opts.Cursor.Before {
+		slices.Reverse(notes)
 	}
 
 	return notes, int(total), nil
@@ -59,7 +143,7 @@ func (r *PostgresRepository) GetAll(limit, offset int) ([]*domain.Note, int, err
 
 func (r *PostgresRepository) GetByID(id int64) (*domain.Note, error) {
 	var note domain.Note
-	result := r.db.First(&note, id)
+	result := r.db.Preload("Tags").First(&note, id)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
 			return nil, ErrNoteNotFound
@@ -70,43 +154,463 @@ func (r *PostgresRepository) GetByID(id int64) (*domain.Note, error) {
 }
 
 func (r *PostgresRepository) Update(id int64, note *domain.Note) (*domain.Note, error) {
-	// Сначала проверяем существование заметки
+	return r.Patch(id, domain.NotePatchFromNote(note))
+}
+
+func (r *PostgresRepository) Patch(id int64, patch *domain.NotePatch) (*domain.Note, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		return patchNote(tx, id, patch)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Получаем обновленную запись; ее могли удалить сразу после изменения
+	return r.GetByID(id)
+}
+
+// patchNote изменяет переданные поля заметки в транзакции tx
+func patchNote(tx *gorm.DB, id int64, patch *domain.NotePatch) error {
+	// Сначала проверяем существование заметки и блокируем ее до конца транзакции
 	var existingNote domain.Note
-	result := r.db.First(&existingNote, id)
+	result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Tags").First(&existingNote, id)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
-			return nil, ErrNoteNotFound
+			return ErrNoteNotFound
 		}
-		return nil, result.Error
+		return result.Error
+	}
+
+	if patch.Version != 0 && patch.Version != existingNote.Version {
+		return ErrNoteVersionMismatch
 	}
 
-	// Обновляем только необходимые поля
+	// Если ничего не меняется, заметку не трогаем
+	if !patch.Changes(&existingNote) {
+		return nil
+	}
+
+	// Сохраняем прежнюю версию
+	if err := createRevision(tx, &existingNote); err != nil {
+		return err
+	}
+
+	// Обновляем только изменившиеся колонки
 	updates := map[string]interface{}{
-		"title":      note.Title,
-		"content":    note.Content,
 		"updated_at": gorm.Expr("NOW()"),
 	}
+	if patch.TitleChanged(&existingNote) {
+		updates["title"] = *patch.Title
+	}
+	if patch.ContentChanged(&existingNote) {
+		updates["content"] = *patch.Content
+	}
+	tagsChanged := patch.TagsChanged(&existingNote)
+	patch.Apply(&existingNote)
+	if language := search.DetectLanguage(existingNote.Title, existingNote.Content); language != existingNote.Language {
+		updates["language"] = language
+	}
 
-	result = r.db.Model(&domain.Note{}).Where("id = ?", id).Updates(updates)
-	if result.Error != nil {
-		return nil, result.Error
+	// Условное обновление: версия не должна была измениться с момента проверки
+	version := existingNote.Version
+	if patch.Version != 0 {
+		version = patch.Version
+	}
+	updates["version"] = gorm.Expr("version + 1")
+	updated := tx.Model(&domain.Note{}).Where("id = ? AND version = ?", id, version).Updates(updates)
+	if updated.Error != nil {
+		return updated.Error
+	}
+	if updated.RowsAffected == 0 {
+		return ErrNoteVersionMismatch
 	}
 
-	// Получаем обновленную запись
-	var updatedNote domain.Note
-	r.db.First(&updatedNote, id)
-	return &updatedNote, nil
+	// Теги заменяем только если их набор изменился
+	if !tagsChanged {
+		return nil
+	}
+	if err := resolveTags(tx, patch.Tags); err != nil {
+		return err
+	}
+	return tx.Model(&existingNote).Association("Tags").Replace(patch.Tags)
 }
 
-func (r *PostgresRepository) Delete(id int64) error {
-	result := r.db.Delete(&domain.Note{}, id)
+func (r *PostgresRepository) Delete(id int64, version int64) error {
+	return deleteNote(r.db, id, version)
+}
+
+// deleteNote перемещает заметку в корзину в транзакции tx
+func deleteNote(tx *gorm.DB, id int64, version int64) error {
+	query := tx
+	if version != 0 {
+		query = query.Where("version = ?", version)
+	}
+	result := query.Delete(&domain.Note{}, id)
 	if result.Error != nil {
 		return result.Error
 	}
 
 	if result.RowsAffected == 0 {
+		// Различаем отсутствующую заметку и несовпадение версии
+		if version != 0 {
+			var count int64
+			if err := tx.Model(&domain.Note{}).Where("id = ?", id).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return ErrNoteVersionMismatch
+			}
+		}
 		return ErrNoteNotFound
 	}
 
 	return nil
 }
+
+func (r *PostgresRepository) MoveNote(id int64, notebookID *int64) (*domain.Note, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if notebookID != nil {
+			if err := notebookExists(tx, *notebookID); err != nil {
+				return err
+			}
+		}
+
+		result := tx.Model(&domain.Note{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"notebook_id": notebookID,
+			"updated_at":  gorm.Expr("NOW()"),
+			"version":     gorm.Expr("version + 1"),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNoteNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(id)
+}
+
+func (r *PostgresRepository) ListTags() ([]domain.TagUsage, error) {
+	var usages []domain.TagUsage
+	result := r.db.Table("tags").
+		Select("tags.name AS name, COUNT(notes.id) AS count").
+		Joins("JOIN note_tags ON note_tags.tag_id = tags.id").
+		Joins("JOIN notes ON notes.id = note_tags.note_id AND notes.deleted_at IS NULL").
+		Group("tags.name").
+		Order("count DESC, name ASC").
+		Scan(&usages)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return usages, nil
+}
+
+func (r *PostgresRepository) RenameTag(oldName, newName string) (*domain.TagUsage, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Как и в ListTags, тег существует, пока он есть у заметки вне корзины
+		count, err := liveTagCount(tx, oldName)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return ErrTagNotFound
+		}
+		if oldName == newName {
+			return nil
+		}
+
+		count, err = liveTagCount(tx, newName)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrTagExists
+		}
+
+		var tag domain.Tag
+		if err := tx.Where("name = ?", oldName).First(&tag).Error; err != nil {
+			return err
+		}
+		if err := bumpTaggedNotes(tx, []int64{tag.ID}); err != nil {
+			return err
+		}
+
+		// Строка newName может остаться от удаленных заметок или заметок в корзине: связи переносятся на нее
+		var target domain.Tag
+		err = tx.Where("name = ?", newName).First(&target).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Model(&tag).Update("name", newName).Error
+		}
+		if err != nil {
+			return err
+		}
+		return moveTagLinks(tx, []int64{tag.ID}, target.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.tagUsage(newName)
+}
+
+func (r *PostgresRepository) MergeTags(sources []string, target string) (*domain.TagUsage, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Все исходные теги (кроме целевого) должны быть у заметок вне корзины, как в ListTags
+		for _, source := range sources {
+			count, err := liveTagCount(tx, source)
+			if err != nil {
+				return err
+			}
+			if count == 0 {
+				return ErrTagNotFound
+			}
+		}
+
+		var sourceTags []domain.Tag
+		if err := tx.Where("name IN ? AND name <> ?", sources, target).Find(&sourceTags).Error; err != nil {
+			return err
+		}
+		if len(sourceTags) == 0 {
+			return nil
+		}
+
+		targetTag := domain.Tag{Name: target}
+		if err := tx.Where(domain.Tag{Name: target}).FirstOrCreate(&targetTag).Error; err != nil {
+			return err
+		}
+
+		sourceIDs := make([]int64, len(sourceTags))
+		for i, tag := range sourceTags {
+			sourceIDs[i] = tag.ID
+		}
+
+		if err := bumpTaggedNotes(tx, sourceIDs); err != nil {
+			return err
+		}
+		return moveTagLinks(tx, sourceIDs, targetTag.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.tagUsage(target)
+}
+
+// tagUsage возвращает тег с количеством неудаленных заметок
+func (r *PostgresRepository) tagUsage(name string) (*domain.TagUsage, error) {
+	count, err := liveTagCount(r.db, name)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.TagUsage{Name: name, Count: int(count)}, nil
+}
+
+// liveTagCount возвращает количество заметок вне корзины с тегом name
+func liveTagCount(db *gorm.DB, name string) (int64, error) {
+	var count int64
+	result := db.Table("note_tags").
+		Joins("JOIN tags ON tags.id = note_tags.tag_id").
+		Joins("JOIN notes ON notes.id = note_tags.note_id AND notes.deleted_at IS NULL").
+		Where("tags.name = ?", name).
+		Count(&count)
+	return count, result.Error
+}
+
+// moveTagLinks переносит связи заметок с тегов sourceIDs на тег targetID и удаляет исходные теги.
+// Заметка, у которой уже есть целевой тег, сохраняет одну связь.
+func moveTagLinks(tx *gorm.DB, sourceIDs []int64, targetID int64) error {
+	if err := tx.Exec(`INSERT INTO note_tags (note_id, tag_id)
+		SELECT DISTINCT note_id, ? FROM note_tags WHERE tag_id IN ?
+		ON CONFLICT DO NOTHING`, targetID, sourceIDs).Error; err != nil {
+		return err
+	}
+	if err := tx.Exec("DELETE FROM note_tags WHERE tag_id IN ?", sourceIDs).Error; err != nil {
+		return err
+	}
+	return tx.Delete(&domain.Tag{}, sourceIDs).Error
+}
+
+// resolveTags находит или создает теги по имени и проставляет им ID
+func resolveTags(tx *gorm.DB, tags []domain.Tag) error {
+	for i := range tags {
+		if err := tx.Where(domain.Tag{Name: tags[i].Name}).FirstOrCreate(&tags[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bumpTaggedNotes увеличивает версию и время изменения заметок с тегами tagIDs, так как меняется их представление
+func bumpTaggedNotes(tx *gorm.DB, tagIDs []int64) error {
+	return tx.Exec(`UPDATE notes SET version = version + 1, updated_at = NOW()
+		WHERE id IN (SELECT note_id FROM note_tags WHERE tag_id IN ?)`, tagIDs).Error
+}
+
+// applyFilter добавляет в запрос условия фильтра
+func applyFilter(query *gorm.DB, filter domain.NoteFilter) *gorm.DB {
+	if filter.IDs != nil {
+		query = query.Where("notes.id IN ?", filter.IDs)
+	}
+	if filter.NotebookIDs != nil {
+		query = query.Where("notes.notebook_id IN ?", filter.NotebookIDs)
+	}
+	if len(filter.Tags) > 0 {
+		tagged := query.Session(&gorm.Session{NewDB: true}).
+			Table("note_tags").
+			Select("note_tags.note_id").
+			Joins("JOIN tags ON tags.id = note_tags.tag_id").
+			Where("tags.name IN ?", filter.Tags)
+		if filter.MatchAllTags {
+			tagged = tagged.Group("note_tags.note_id").
+				Having("COUNT(DISTINCT tags.name) = ?", len(filter.Tags))
+		}
+		query = query.Where("notes.id IN (?)", tagged)
+	}
+	if filter.Query != nil {
+		sql, args := compileQuery(filter.Query)
+		query = query.Where(sql, args...)
+	}
+	if filter.CreatedAfter != nil {
+		query = query.Where("notes.created_at > ?", *filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		query = query.Where("notes.created_at < ?", *filter.CreatedBefore)
+	}
+	if filter.UpdatedSince != nil {
+		query = query.Where("notes.updated_at >= ?", *filter.UpdatedSince)
+	}
+	if filter.TitlePrefix != "" {
+		query = query.Where("notes.title ILIKE ?", likeEscaper.Replace(filter.TitlePrefix)+"%")
+	}
+	return query
+}
+
+// iterateBatchSize сколько заметок All читает из курсора, прежде чем загрузить их теги одним запросом
+const iterateBatchSize = 100
+
+// All читает заметки курсором строк базы данных; теги загружаются отдельным запросом на каждую пачку заметок
+func (r *PostgresRepository) All(filter domain.NoteFilter, sort []domain.SortField) iter.Seq2[*domain.Note, error] {
+	return func(yield func(*domain.Note, error) bool) {
+		fields := domain.NoteListOptions{Sort: sort}.NoteSort()
+		rows, err := applyFilter(r.db.Model(&domain.Note{}), filter).Order(orderClause(fields)).Rows()
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer rows.Close()
+
+		batch := make([]*domain.Note, 0, iterateBatchSize)
+		// flush загружает теги пачки и отдает ее заметки; false - перебор прерван
+		flush := func() bool {
+			if err := r.loadTags(batch); err != nil {
+				yield(nil, err)
+				return false
+			}
+			for _, note := range batch {
+				if !yield(note, nil) {
+					return false
+				}
+			}
+			batch = batch[:0]
+			return true
+		}
+
+		for rows.Next() {
+			var note domain.Note
+			if err := r.db.ScanRows(rows, &note); err != nil {
+				yield(nil, err)
+				return
+			}
+			batch = append(batch, &note)
+			if len(batch) == iterateBatchSize && !flush() {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			if isConnectionError(err) {
+				err = &domain.UnavailableError{Err: err}
+			}
+			yield(nil, err)
+			return
+		}
+		flush()
+	}
+}
+
+// loadTags загружает теги заметок одним запросом
+func (r *PostgresRepository) loadTags(notes []*domain.Note) error {
+	if len(notes) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*domain.Note, len(notes))
+	for _, note := range notes {
+		note.Tags = []domain.Tag{}
+		byID[note.ID] = note
+	}
+
+	var rows []struct {
+		NoteID int64
+		TagID  int64
+		Name   string
+	}
+	err := r.db.Table("note_tags").
+		Select("note_tags.note_id, tags.id AS tag_id, tags.name").
+		Joins("JOIN tags ON tags.id = note_tags.tag_id").
+		Where("note_tags.note_id IN ?", slices.Collect(maps.Keys(byID))).
+		Order("tags.name").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		note := byID[row.NoteID]
+		note.Tags = append(note.Tags, domain.Tag{ID: row.TagID, Name: row.Name})
+	}
+	return nil
+}
+
+// applyCursor ограничивает выборку заметками после позиции курсора (или перед ней)
+// сравнением пары (created_at, id), которое использует индекс idx_notes_created_at_id
+func applyCursor(query *gorm.DB, cursor *domain.NoteCursor, fields []domain.SortField) *gorm.DB {
+	// Перед курсором идем в обратном порядке, чтобы LIMIT взял ближайшие заметки
+	desc := fields[0].Desc != cursor.Before
+	if desc {
+		query = query.Where("(notes.created_at, notes.id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+	} else {
+		query = query.Where("(notes.created_at, notes.id) > (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+
+	return query.Order(orderClause([]domain.SortField{
+		{Field: domain.SortByCreatedAt, Desc: desc},
+		{Field: domain.SortByID, Desc: desc},
+	}))
+}
+
+// sortColumns колонки для полей сортировки; заголовки сравниваются побайтово,
+// чтобы порядок совпадал с JSON хранилищем
+var sortColumns = map[string]string{
+	domain.SortByID:        "notes.id",
+	domain.SortByTitle:     `notes.title COLLATE "C"`,
+	domain.SortByCreatedAt: "notes.created_at",
+	domain.SortByUpdatedAt: "notes.updated_at",
+}
+
+// orderClause строит ORDER BY для полей сортировки
+func orderClause(fields []domain.SortField) string {
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		direction := " ASC"
+		if field.Desc {
+			direction = " DESC"
+		}
+		parts[i] = sortColumns[field.Field] + direction
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"notes-api/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+func (r *PostgresRepository) GetRevisions(noteID int64) ([]*domain.Revision, error) {
+	if err := noteExists(r.db, noteID); err != nil {
+		return nil, err
+	}
+
+	var revisions []*domain.Revision
+	result := r.db.Where("note_id = ?", noteID).Order("number DESC").Find(&revisions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return revisions, nil
+}
+
+func (r *PostgresRepository) GetRevision(noteID int64, number int) (*domain.Revision, error) {
+	if err := noteExists(r.db, noteID); err != nil {
+		return nil, err
+	}
+
+	var revision domain.Revision
+	result := r.db.Where("note_id = ? AND number = ?", noteID, number).First(&revision)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, result.Error
+	}
+	return &revision, nil
+}
+
+// createRevision сохраняет текущее состояние заметки как новую ревизию.
+// Заметка должна быть заблокирована в транзакции tx, чтобы номера не конфликтовали.
+func createRevision(tx *gorm.DB, note *domain.Note) error {
+	var last int
+	if err := tx.Model(&domain.Revision{}).
+		Where("note_id = ?", note.ID).
+		Select("COALESCE(MAX(number), 0)").
+		Scan(&last).Error; err != nil {
+		return err
+	}
+
+	return tx.Create(domain.NewRevision(note, last+1)).Error
+}
+
+// noteExists проверяет существование заметки
+func noteExists(tx *gorm.DB, id int64) error {
+	var count int64
+	if err := tx.Model(&domain.Note{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrNoteNotFound
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"fmt"
+
+	"notes-api/internal/domain"
+	"notes-api/internal/search"
+
+	"gorm.io/gorm"
+)
+
+// searchMigrations добавляют в notes генерируемую колонку tsvector с GIN индексом.
+// Текст разбирается конфигурацией языка заметки, заголовок имеет вес A, содержимое - вес B.
+// Колонка из прежней версии с конфигурацией 'simple' пересоздается.
+var searchMigrations = []string{
+	`DO $$
+	BEGIN
+		IF EXISTS (
+			SELECT 1 FROM information_schema.columns
+			WHERE table_name = 'notes' AND column_name = 'search_vector'
+				AND generation_expression NOT LIKE '%russian%'
+		) THEN
+			ALTER TABLE notes DROP COLUMN search_vector;
+		END IF;
+	END $$`,
+	`ALTER TABLE notes ADD COLUMN IF NOT EXISTS search_vector tsvector
+		GENERATED ALWAYS AS (
+			CASE WHEN language = 'russian' THEN
+				setweight(to_tsvector('russian', coalesce(title, '')), 'A') ||
+				setweight(to_tsvector('russian', coalesce(content, '')), 'B')
+			ELSE
+				setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+				setweight(to_tsvector('english', coalesce(content, '')), 'B')
+			END
+		) STORED`,
+	`CREATE INDEX IF NOT EXISTS idx_notes_search_vector ON notes USING GIN (search_vector)`,
+}
+
+// searchCondition отбирает заметки, подходящие под запрос на их языке
+const searchCondition = `(n.language = 'russian' AND n.search_vector @@ qr) OR (n.language <> 'russian' AND n.search_vector @@ qe)`
+
+// searchQuery выбирает ID найденных заметок с релевантностью и сниппетом
+const searchQuery = `SELECT n.id,
+	ts_rank(n.search_vector, CASE WHEN n.language = 'russian' THEN qr ELSE qe END) AS rank,
+	CASE WHEN n.language = 'russian'
+		THEN ts_headline('russian', n.content, qr, ?)
+		ELSE ts_headline('english', n.content, qe, ?)
+	END AS snippet
+FROM notes n, websearch_to_tsquery('russian', ?) qr, websearch_to_tsquery('english', ?) qe
+WHERE n.deleted_at IS NULL AND (` + searchCondition + `)
+ORDER BY rank DESC, n.created_at DESC
+LIMIT ? OFFSET ?`
+
+// searchCountQuery считает количество найденных заметок
+const searchCountQuery = `SELECT COUNT(*)
+FROM notes n, websearch_to_tsquery('russian', ?) qr, websearch_to_tsquery('english', ?) qe
+WHERE n.deleted_at IS NULL AND (` + searchCondition + `)`
+
+// headlineOptions настройки ts_headline, согласованные с search.Snippet
+var headlineOptions = fmt.Sprintf("StartSel=%s, StopSel=%s, MaxWords=20, MinWords=10",
+	search.HighlightStart, search.HighlightStop)
+
+// searchRow строка результата поиска
+type searchRow struct {
+	ID      int64
+	Rank    float64
+	Snippet string
+}
+
+// migrateSearch создает колонку и индекс для полнотекстового поиска
+// и определяет язык заметок, созданных до его появления
+func migrateSearch(db *gorm.DB) error {
+	for _, migration := range searchMigrations {
+		if err := db.Exec(migration).Error; err != nil {
+			return err
+		}
+	}
+
+	var notes []*domain.Note
+	if err := db.Unscoped().Select("id", "title", "content").Where("language = ''").Find(&notes).Error; err != nil {
+		return err
+	}
+	for _, note := range notes {
+		language := search.DetectLanguage(note.Title, note.Content)
+		if err := db.Unscoped().Model(&domain.Note{}).Where("id = ?", note.ID).UpdateColumn("language", language).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *PostgresRepository) Search(query string, limit, offset int) ([]*domain.SearchResult, int, error) {
+	var total int64
+	if err := r.db.Raw(searchCountQuery, query, query).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []searchRow
+	if err := r.db.Raw(searchQuery, headlineOptions, headlineOptions, query, query, limit, offset).Scan(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	if len(rows) == 0 {
+		return []*domain.SearchResult{}, int(total), nil
+	}
+
+	// Загружаем найденные заметки вместе с тегами
+	ids := make([]int64, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	var notes []*domain.Note
+	if err := r.db.Preload("Tags").Find(&notes, ids).Error; err != nil {
+		return nil, 0, err
+	}
+	byID := make(map[int64]*domain.Note, len(notes))
+	for _, note := range notes {
+		byID[note.ID] = note
+	}
+
+	results := make([]*domain.SearchResult, 0, len(rows))
+	for _, row := range rows {
+		if note, found := byID[row.ID]; found {
+			results = append(results, &domain.SearchResult{
+				Note:    note,
+				Rank:    row.Rank,
+				Snippet: row.Snippet,
+			})
+		}
+	}
+
+	return results, int(total), nil
+}
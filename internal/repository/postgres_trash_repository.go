@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"time"
+
+	"notes-api/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+func (r *PostgresRepository) GetTrash(limit, offset int) ([]*domain.Note, int, error) {
+	var notes []*domain.Note
+	var total int64
+
+	trash := func() *gorm.DB {
+		return r.db.Unscoped().Model(&domain.Note{}).Where("deleted_at IS NOT NULL")
+	}
+
+	if err := trash().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	result := trash().Preload("Tags").Order("deleted_at DESC").Limit(limit).Offset(offset).Find(&notes)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return notes, int(total), nil
+}
+
+func (r *PostgresRepository) RestoreNote(id int64) (*domain.Note, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Model(&domain.Note{}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Updates(map[string]interface{}{
+				"deleted_at": nil,
+				"version":    gorm.Expr("version + 1"),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNoteNotFound
+		}
+
+		// Если блокнот заметки был удален, возвращаем заметку в корень
+		return tx.Exec(`UPDATE notes SET notebook_id = NULL
+			WHERE id = ? AND notebook_id IS NOT NULL
+			AND NOT EXISTS (SELECT 1 FROM notebooks WHERE notebooks.id = notes.notebook_id)`, id).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(id)
+}
+
+func (r *PostgresRepository) PurgeNote(id int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Unscoped().Model(&domain.Note{}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return ErrNoteNotFound
+		}
+
+		return purgeNotes(tx, []int64{id})
+	})
+}
+
+func (r *PostgresRepository) PurgeTrash(deletedBefore time.Time) ([]int64, error) {
+	var ids []int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&domain.Note{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		return purgeNotes(tx, ids)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// purgeNotes удаляет заметки навсегда вместе со связями с тегами и ревизиями
+func purgeNotes(tx *gorm.DB, ids []int64) error {
+	if err := tx.Exec("DELETE FROM note_tags WHERE note_id IN ?", ids).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("note_id IN ?", ids).Delete(&domain.Revision{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Delete(&domain.Note{}, ids).Error
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"notes-api/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+func (r *PostgresRepository) CreateWebhook(webhook *domain.Webhook) (*domain.Webhook, error) {
+	if err := r.db.Create(webhook).Error; err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (r *PostgresRepository) GetAllWebhooks() ([]*domain.Webhook, error) {
+	var webhooks []*domain.Webhook
+	if err := r.db.Order("id ASC").Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (r *PostgresRepository) GetWebhookByID(id int64) (*domain.Webhook, error) {
+	var webhook domain.Webhook
+	result := r.db.First(&webhook, id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, result.Error
+	}
+	return &webhook, nil
+}
+
+func (r *PostgresRepository) UpdateWebhook(id int64, webhook *domain.Webhook) (*domain.Webhook, error) {
+	columns := []string{"url", "events", "secret", "active", "updated_at"}
+	if webhook.Active {
+		// Включение вебхука сбрасывает отключение из-за ошибок доставки
+		columns = append(columns, "failure_count", "disabled_at")
+	}
+
+	// Обновление структурой, чтобы events сохранялся через serializer:json
+	result := r.db.Model(&domain.Webhook{}).Where("id = ?", id).Select(columns).Updates(&domain.Webhook{
+		URL:    webhook.URL,
+		Events: webhook.Events,
+		Secret: webhook.Secret,
+		Active: webhook.Active,
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrWebhookNotFound
+	}
+
+	return r.GetWebhookByID(id)
+}
+
+func (r *PostgresRepository) DeleteWebhook(id int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&domain.Webhook{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrWebhookNotFound
+		}
+		return tx.Where("webhook_id = ?", id).Delete(&domain.WebhookDelivery{}).Error
+	})
+}
+
+func (r *PostgresRepository) RecordWebhookFailure(id int64, disableAfter int) (*domain.Webhook, error) {
+	// Счетчик увеличивается и проверяется одним запросом, чтобы параллельные доставки не потеряли неудачу.
+	// В SET выражения видят значения столбцов до изменения.
+	disable := gorm.Expr("active AND ? > 0 AND failure_count + 1 >= ?", disableAfter, disableAfter)
+	result := r.db.Model(&domain.Webhook{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+		"failure_count": gorm.Expr("failure_count + 1"),
+		"active":        gorm.Expr("active AND NOT (?)", disable),
+		"disabled_at":   gorm.Expr("CASE WHEN ? THEN NOW() ELSE disabled_at END", disable),
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrWebhookNotFound
+	}
+	return r.GetWebhookByID(id)
+}
+
+func (r *PostgresRepository) ResetWebhookFailures(id int64) error {
+	return r.db.Model(&domain.Webhook{}).Where("id = ? AND failure_count <> 0", id).UpdateColumn("failure_count", 0).Error
+}
+
+func (r *PostgresRepository) SaveWebhookDelivery(delivery *domain.WebhookDelivery, keep int) error {
+	if delivery.ID != 0 {
+		// Не Save: запись, уже вытесненная из журнала, не должна появиться снова
+		return r.db.Model(delivery).Select("*").Omit("id", "created_at").Updates(delivery).Error
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := webhookExists(tx, delivery.WebhookID); err != nil {
+			return err
+		}
+		if err := tx.Create(delivery).Error; err != nil {
+			return err
+		}
+		if keep <= 0 {
+			return nil
+		}
+		// Журнал вебхука ограничен keep последними записями
+		return tx.Exec(`DELETE FROM webhook_deliveries WHERE webhook_id = ? AND id NOT IN (
+			SELECT id FROM webhook_deliveries WHERE webhook_id = ? ORDER BY id DESC LIMIT ?
+		)`, delivery.WebhookID, delivery.WebhookID, keep).Error
+	})
+}
+
+func (r *PostgresRepository) GetWebhookDeliveries(webhookID int64, limit, offset int) ([]*domain.WebhookDelivery, int, error) {
+	if err := webhookExists(r.db, webhookID); err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	if err := r.db.Model(&domain.WebhookDelivery{}).Where("webhook_id = ?", webhookID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var deliveries []*domain.WebhookDelivery
+	result := r.db.Where("webhook_id = ?", webhookID).Order("id DESC").Limit(limit).Offset(offset).Find(&deliveries)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return deliveries, int(total), nil
+}
+
+// webhookExists проверяет, что вебхук существует
+func webhookExists(tx *gorm.DB, id int64) error {
+	var count int64
+	if err := tx.Model(&domain.Webhook{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
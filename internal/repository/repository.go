@@ -1,14 +1,105 @@
 package repository
 
 import (
+	"iter"
+	"time"
+
 	"notes-api/internal/domain"
 )
 
 // NoteRepository определяет интерфейс для работы с заметками
 type NoteRepository interface {
 	Create(note *domain.Note) (*domain.Note, error)
-	GetAll(limit, offset int) ([]*domain.Note, int, error) // Изменено
+	GetAll(opts domain.NoteListOptions) ([]*domain.Note, int, error)
+	// All перебирает заметки, подходящие под фильтр, в порядке sort (пусто - DefaultNoteSort),
+	// не загружая их в память все сразу. Ошибка завершает перебор; прерванный перебор освобождает ресурсы.
+	All(filter domain.NoteFilter, sort []domain.SortField) iter.Seq2[*domain.Note, error]
 	GetByID(id int64) (*domain.Note, error)
-	Update(id int64, note *domain.Note) (*domain.Note, error)
-	Delete(id int64) error
+	// Update и Patch сохраняют прежнюю версию как ревизию. Если ожидаемая версия
+	// (note.Version, patch.Version) не 0 и не совпадает, возвращают ErrNoteVersionMismatch.
+	Update(id int64, note *domain.Note) (*domain.Note, error)      // Если note.Tags == nil, теги не меняются
+	Patch(id int64, patch *domain.NotePatch) (*domain.Note, error) // Меняет только переданные поля; без изменений ревизия не создается
+	Delete(id int64, version int64) error                          // Перемещает заметку в корзину; version 0 - любая версия
+	MoveNote(id int64, notebookID *int64) (*domain.Note, error)
+	Search(query string, limit, offset int) ([]*domain.SearchResult, int, error) // Самые релевантные первыми
+	// Bulk выполняет операции одной транзакцией и возвращает результат каждой операции в том же порядке.
+	// В атомарном режиме при ошибке любой операции не применяется ни одна, остальные получают ErrBulkNotApplied.
+	// Ошибка возвращается, только если не удалось сохранить изменения.
+	Bulk(ops []domain.BulkNoteOp, atomic bool) ([]domain.BulkResult, error)
+}
+
+// TagRepository определяет интерфейс для работы с тегами.
+// Как и в ListTags, тег существует, пока он есть хотя бы у одной заметки вне корзины:
+// RenameTag и MergeTags возвращают ErrTagNotFound для остальных тегов, а RenameTag - ErrTagExists,
+// если новое имя уже занято. Теги меняются и у заметок в корзине, без повторов у одной заметки.
+type TagRepository interface {
+	ListTags() ([]domain.TagUsage, error)
+	RenameTag(oldName, newName string) (*domain.TagUsage, error)
+	MergeTags(sources []string, target string) (*domain.TagUsage, error)
+}
+
+// NotebookRepository определяет интерфейс для работы с блокнотами
+type NotebookRepository interface {
+	CreateNotebook(notebook *domain.Notebook) (*domain.Notebook, error)
+	GetAllNotebooks() ([]*domain.Notebook, error)
+	GetNotebookByID(id int64) (*domain.Notebook, error)
+	UpdateNotebook(id int64, notebook *domain.Notebook) (*domain.Notebook, error)
+	MoveNotebook(id int64, parentID *int64) (*domain.Notebook, error)
+	DeleteNotebook(id int64) error
+	GetNotebookSubtree(id int64) ([]int64, error) // ID блокнота и всех его потомков
+}
+
+// RevisionRepository определяет интерфейс для чтения истории изменений заметок.
+// Ревизии создаются в NoteRepository.Update.
+type RevisionRepository interface {
+	GetRevisions(noteID int64) ([]*domain.Revision, error) // Новые первыми
+	GetRevision(noteID int64, number int) (*domain.Revision, error)
+}
+
+// TrashRepository определяет интерфейс для работы с удаленными заметками
+type TrashRepository interface {
+	GetTrash(limit, offset int) ([]*domain.Note, int, error) // Недавно удаленные первыми
+	RestoreNote(id int64) (*domain.Note, error)
+	PurgeNote(id int64) error                            // Удаляет заметку из корзины навсегда
+	PurgeTrash(deletedBefore time.Time) ([]int64, error) // Удаляет навсегда заметки, удаленные раньше deletedBefore; возвращает их ID
+}
+
+// IdempotencyRepository определяет интерфейс для хранения ответов на запросы с Idempotency-Key
+type IdempotencyRepository interface {
+	// ReserveIdempotencyKey сохраняет новый ключ, если его нет или срок его хранения истек к record.CreatedAt.
+	// Если ключ занят, возвращает существующую запись.
+	ReserveIdempotencyKey(record *domain.IdempotencyKey) (*domain.IdempotencyKey, error)
+	CompleteIdempotencyKey(record *domain.IdempotencyKey) error // Сохраняет ответ на запрос
+	DeleteIdempotencyKey(key string) error
+	PurgeIdempotencyKeys(now time.Time) (int, error) // Удаляет ключи с истекшим сроком хранения
+}
+
+// WebhookRepository определяет интерфейс для хранения вебхуков и журнала их доставок
+type WebhookRepository interface {
+	CreateWebhook(webhook *domain.Webhook) (*domain.Webhook, error)
+	GetAllWebhooks() ([]*domain.Webhook, error)
+	GetWebhookByID(id int64) (*domain.Webhook, error)
+	// UpdateWebhook меняет адрес, события, ключ и Active; включение обнуляет счетчик неудачных доставок
+	UpdateWebhook(id int64, webhook *domain.Webhook) (*domain.Webhook, error)
+	DeleteWebhook(id int64) error // Удаляет вебхук вместе с журналом доставок
+	// RecordWebhookFailure увеличивает счетчик неудачных доставок подряд и отключает вебхук,
+	// когда счетчик достигает disableAfter (0 - не отключать)
+	RecordWebhookFailure(id int64, disableAfter int) (*domain.Webhook, error)
+	ResetWebhookFailures(id int64) error // Обнуляет счетчик после успешной доставки
+
+	// SaveWebhookDelivery создает (ID == 0) или обновляет запись журнала доставки.
+	// Журнал вебхука хранит не больше keep последних записей.
+	SaveWebhookDelivery(delivery *domain.WebhookDelivery, keep int) error
+	GetWebhookDeliveries(webhookID int64, limit, offset int) ([]*domain.WebhookDelivery, int, error) // Новые первыми
+}
+
+// Repository объединяет все хранилища, которые предоставляет бэкенд
+type Repository interface {
+	NoteRepository
+	TagRepository
+	NotebookRepository
+	RevisionRepository
+	TrashRepository
+	IdempotencyRepository
+	WebhookRepository
 }
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"notes-api/internal/domain"
+)
+
+// tagTestRepository хранилище для проверки контракта тегов
+type tagTestRepository interface {
+	NoteRepository
+	TagRepository
+	TrashRepository
+}
+
+// tagTestRepositories возвращает пустые хранилища всех типов. Postgres проверяется,
+// если задана TEST_DATABASE_URL; его таблицы заметок и тегов очищаются перед каждым тестом.
+func tagTestRepositories(t *testing.T) map[string]func(t *testing.T) tagTestRepository {
+	repos := map[string]func(t *testing.T) tagTestRepository{
+		"json": func(t *testing.T) tagTestRepository {
+			repo, err := NewJSONRepository(filepath.Join(t.TempDir(), "notes.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return repo
+		},
+	}
+
+	if dsn := os.Getenv("TEST_DATABASE_URL"); dsn != "" {
+		repos["postgres"] = func(t *testing.T) tagTestRepository {
+			repo, err := NewPostgresRepository(dsn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := repo.db.Exec("TRUNCATE notes, tags, note_tags, revisions RESTART IDENTITY CASCADE").Error; err != nil {
+				t.Fatal(err)
+			}
+			return repo
+		}
+	}
+	return repos
+}
+
+func TestTagRenameAndMergeContract(t *testing.T) {
+	tests := []struct {
+		name      string
+		apply     func(repo TagRepository) (*domain.TagUsage, error)
+		wantErr   error
+		wantUsage domain.TagUsage
+		wantTrash []string // Теги заметки в корзине после изменения (исходно a, b)
+	}{
+		{
+			name:      "rename onto tag used only in trash",
+			apply:     func(repo TagRepository) (*domain.TagUsage, error) { return repo.RenameTag("a", "b") },
+			wantUsage: domain.TagUsage{Name: "b", Count: 1},
+			wantTrash: []string{"b"},
+		},
+		{
+			name:      "rename to itself",
+			apply:     func(repo TagRepository) (*domain.TagUsage, error) { return repo.RenameTag("a", "a") },
+			wantUsage: domain.TagUsage{Name: "a", Count: 1},
+			wantTrash: []string{"a", "b"},
+		},
+		{
+			name:    "rename tag used only in trash",
+			apply:   func(repo TagRepository) (*domain.TagUsage, error) { return repo.RenameTag("c", "x") },
+			wantErr: ErrTagNotFound,
+		},
+		{
+			name:    "rename unknown tag",
+			apply:   func(repo TagRepository) (*domain.TagUsage, error) { return repo.RenameTag("missing", "x") },
+			wantErr: ErrTagNotFound,
+		},
+		{
+			name:    "rename onto live tag",
+			apply:   func(repo TagRepository) (*domain.TagUsage, error) { return repo.RenameTag("a", "d") },
+			wantErr: ErrTagExists,
+		},
+		{
+			name:      "merge into tag used only in trash",
+			apply:     func(repo TagRepository) (*domain.TagUsage, error) { return repo.MergeTags([]string{"a"}, "b") },
+			wantUsage: domain.TagUsage{Name: "b", Count: 1},
+			wantTrash: []string{"b"},
+		},
+		{
+			name:      "merge into new tag",
+			apply:     func(repo TagRepository) (*domain.TagUsage, error) { return repo.MergeTags([]string{"a", "d"}, "e") },
+			wantUsage: domain.TagUsage{Name: "e", Count: 2},
+			wantTrash: []string{"b", "e"},
+		},
+		{
+			name:    "merge tag used only in trash",
+			apply:   func(repo TagRepository) (*domain.TagUsage, error) { return repo.MergeTags([]string{"a", "c"}, "e") },
+			wantErr: ErrTagNotFound,
+		},
+	}
+
+	for kind, newRepo := range tagTestRepositories(t) {
+		for _, tt := range tests {
+			t.Run(kind+"/"+tt.name, func(t *testing.T) {
+				repo := newRepo(t)
+
+				// a - у заметки и в корзине, b и c - только в корзине, d - только у заметки
+				for _, note := range []struct {
+					tags    []string
+					trashed bool
+				}{
+					{tags: []string{"a"}},
+					{tags: []string{"d"}},
+					{tags: []string{"a", "b"}, trashed: true},
+					{tags: []string{"c"}, trashed: true},
+				} {
+					tags, err := domain.NormalizeTags(note.tags)
+					if err != nil {
+						t.Fatal(err)
+					}
+					created, err := repo.Create(&domain.Note{Title: "note", Content: "text", Tags: tags})
+					if err != nil {
+						t.Fatal(err)
+					}
+					if note.trashed {
+						if err := repo.Delete(created.ID, 0); err != nil {
+							t.Fatal(err)
+						}
+					}
+				}
+
+				usage, err := tt.apply(repo)
+				if tt.wantErr != nil {
+					if !errors.Is(err, tt.wantErr) {
+						t.Fatalf("error = %v, want %v", err, tt.wantErr)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if *usage != tt.wantUsage {
+					t.Errorf("usage = %+v, want %+v", *usage, tt.wantUsage)
+				}
+
+				trash, _, err := repo.GetTrash(10, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, note := range trash {
+					names := domain.TagNames(note.Tags)
+					if slices.Contains(names, "c") {
+						continue
+					}
+					slices.Sort(names)
+					if !slices.Equal(names, tt.wantTrash) {
+						t.Errorf("trashed note tags = %v, want %v", names, tt.wantTrash)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package search
+
+import "unicode"
+
+// Языки текстового поиска, совпадают с конфигурациями PostgreSQL
+const (
+	LanguageRussian = "russian"
+	LanguageEnglish = "english"
+)
+
+// DetectLanguage определяет язык заметки: русский, если кириллических букв больше, чем латинских
+func DetectLanguage(title, content string) string {
+	cyrillic, latin := 0, 0
+	for _, text := range []string{title, content} {
+		for _, r := range text {
+			switch {
+			case unicode.Is(unicode.Cyrillic, r):
+				cyrillic++
+			case unicode.Is(unicode.Latin, r):
+				latin++
+			}
+		}
+	}
+
+	if cyrillic > latin {
+		return LanguageRussian
+	}
+	return LanguageEnglish
+}
+
+// Lexeme приводит слово к лексеме так же, как конфигурация PostgreSQL для языка:
+// латинские слова обрабатывает english_stem, остальные слова - стеммер языка,
+// слова с цифрами не меняются. Для стоп-слов возвращает пустую строку.
+func Lexeme(term, language string) string {
+	ascii, digits := true, false
+	for _, r := range term {
+		if r > unicode.MaxASCII {
+			ascii = false
+		}
+		if unicode.IsDigit(r) {
+			digits = true
+		}
+	}
+
+	switch {
+	case digits:
+		return term
+	case ascii:
+		if englishStopWords[term] {
+			return ""
+		}
+		return StemEnglish(term)
+	case language == LanguageRussian:
+		if russianStopWords[term] {
+			return ""
+		}
+		return StemRussian(term)
+	default:
+		return term
+	}
+}
@@ -0,0 +1,54 @@
+package search
+
+import "testing"
+
+func TestLexeme(t *testing.T) {
+	tests := []struct {
+		term     string
+		language string
+		want     string
+	}{
+		{"cats", LanguageEnglish, "cat"},
+		{"cats", LanguageRussian, "cat"}, // Латинские слова всегда обрабатывает english_stem
+		{"the", LanguageEnglish, ""},     // Стоп-слово
+		{"the", LanguageRussian, ""},
+		{"заметки", LanguageRussian, "заметк"},
+		{"заметки", LanguageEnglish, "заметки"}, // Кириллица в английской конфигурации не меняется
+		{"и", LanguageRussian, ""},
+		{"и", LanguageEnglish, "и"},
+		{"v2", LanguageEnglish, "v2"}, // Слова с цифрами не меняются
+		{"2024", LanguageRussian, "2024"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.term+"/"+tt.language, func(t *testing.T) {
+			if got := Lexeme(tt.term, tt.language); got != tt.want {
+				t.Errorf("Lexeme(%q, %q) = %q, want %q", tt.term, tt.language, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name    string
+		title   string
+		content string
+		want    string
+	}{
+		{name: "empty", want: LanguageEnglish},
+		{name: "english", title: "Shopping list", content: "milk, bread", want: LanguageEnglish},
+		{name: "russian", title: "Список покупок", content: "молоко, хлеб", want: LanguageRussian},
+		{name: "more cyrillic letters", title: "Go", content: "заметки о языке", want: LanguageRussian},
+		{name: "more latin letters", title: "Заметка", content: "kubernetes deployment notes", want: LanguageEnglish},
+		{name: "equal counts", title: "ab", content: "аб", want: LanguageEnglish},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectLanguage(tt.title, tt.content); got != tt.want {
+				t.Errorf("DetectLanguage(%q, %q) = %q, want %q", tt.title, tt.content, got, tt.want)
+			}
+		})
+	}
+}
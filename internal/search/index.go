@@ -0,0 +1,234 @@
+package search
+
+import (
+	"math"
+	"sort"
+)
+
+// Веса позиций при ранжировании, как веса A и B у ts_rank в PostgreSQL
+const (
+	titleWeight   float32 = 1.0
+	contentWeight float32 = 0.4
+)
+
+// Ограничения tsvector: максимальная позиция слова и число позиций у лексемы
+const (
+	maxPosition  = 16383
+	maxPositions = 256
+)
+
+// position вхождение лексемы в документ
+type position struct {
+	pos    int
+	weight float32
+}
+
+// Hit найденный документ с релевантностью
+type Hit struct {
+	ID    int64
+	Score float64
+}
+
+// Index инвертированный индекс по заголовку и содержимому документов.
+// Документ индексируется по правилам своего языка, ранжирование повторяет ts_rank.
+// Index не потокобезопасен, синхронизация остается на вызывающей стороне.
+type Index struct {
+	postings  map[string]map[int64][]position
+	docs      map[int64][]string // Лексемы документа, нужны для удаления
+	languages map[int64]string
+}
+
+// NewIndex создает пустой индекс
+func NewIndex() *Index {
+	return &Index{
+		postings:  make(map[string]map[int64][]position),
+		docs:      make(map[int64][]string),
+		languages: make(map[int64]string),
+	}
+}
+
+// Add индексирует документ на языке language, заменяя прежнюю версию с тем же ID
+func (idx *Index) Add(id int64, language, title, content string) {
+	idx.Remove(id)
+
+	lexemes := make(map[string][]position)
+	// Позиции содержимого продолжают позиции заголовка, как при склейке tsvector
+	last := analyze(lexemes, title, language, titleWeight, 0)
+	analyze(lexemes, content, language, contentWeight, last)
+
+	docLexemes := make([]string, 0, len(lexemes))
+	for lexeme, positions := range lexemes {
+		if idx.postings[lexeme] == nil {
+			idx.postings[lexeme] = make(map[int64][]position)
+		}
+		idx.postings[lexeme][id] = positions
+		docLexemes = append(docLexemes, lexeme)
+	}
+	idx.docs[id] = docLexemes
+	idx.languages[id] = language
+}
+
+// analyze добавляет в lexemes вхождения слов текста начиная с позиции offset
+// и возвращает позицию последней лексемы. Стоп-слова занимают позицию, но не индексируются.
+func analyze(lexemes map[string][]position, text, language string, weight float32, offset int) int {
+	last := offset
+	for i, token := range Tokenize(text) {
+		lexeme := Lexeme(token.Term, language)
+		if lexeme == "" || len(lexemes[lexeme]) >= maxPositions {
+			continue
+		}
+		last = min(offset+i+1, maxPosition)
+		lexemes[lexeme] = append(lexemes[lexeme], position{pos: last, weight: weight})
+	}
+	return last
+}
+
+// Remove удаляет документ из индекса
+func (idx *Index) Remove(id int64) {
+	for _, lexeme := range idx.docs[id] {
+		delete(idx.postings[lexeme], id)
+		if len(idx.postings[lexeme]) == 0 {
+			delete(idx.postings, lexeme)
+		}
+	}
+	delete(idx.docs, id)
+	delete(idx.languages, id)
+}
+
+// Search возвращает документы, подходящие под запрос, самые релевантные первыми.
+// Запрос разбирается отдельно для каждого языка и применяется к документам этого языка.
+func (idx *Index) Search(text string) []Hit {
+	var hits []Hit
+	for _, language := range []string{LanguageRussian, LanguageEnglish} {
+		hits = append(hits, idx.search(ParseQuery(text, language), language)...)
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID > hits[j].ID
+	})
+
+	return hits
+}
+
+// search ищет запрос q среди документов на языке language
+func (idx *Index) search(q Query, language string) []Hit {
+	if q.Empty() {
+		return nil
+	}
+
+	// Кандидаты - документы, содержащие самую редкую лексему
+	rarest := q.Include[0]
+	for _, lexeme := range q.Include[1:] {
+		if len(idx.postings[lexeme]) < len(idx.postings[rarest]) {
+			rarest = lexeme
+		}
+	}
+
+	var hits []Hit
+	for id := range idx.postings[rarest] {
+		if idx.languages[id] != language || !idx.matches(id, q) {
+			continue
+		}
+		hits = append(hits, Hit{ID: id, Score: float64(idx.rank(id, q))})
+	}
+
+	return hits
+}
+
+// matches проверяет, что документ содержит все искомые лексемы и ни одной исключенной
+func (idx *Index) matches(id int64, q Query) bool {
+	for _, lexeme := range q.Include {
+		if _, found := idx.postings[lexeme][id]; !found {
+			return false
+		}
+	}
+	for _, lexeme := range q.Exclude {
+		if _, found := idx.postings[lexeme][id]; found {
+			return false
+		}
+	}
+	return true
+}
+
+// rank считает релевантность документа так же, как ts_rank без нормализации
+func (idx *Index) rank(id int64, q Query) float32 {
+	lexemes := q.Lexemes()
+
+	var rank float32
+	if len(lexemes) < 2 {
+		rank = idx.rankOr(id, lexemes)
+	} else {
+		rank = idx.rankAnd(id, lexemes)
+	}
+
+	if rank < 0 {
+		rank = 1e-20
+	}
+	return rank
+}
+
+// rankOr оценивает частоту и вес вхождений каждой лексемы (calc_rank_or)
+func (idx *Index) rankOr(id int64, lexemes []string) float32 {
+	var rank float32
+	for _, lexeme := range lexemes {
+		positions, found := idx.postings[lexeme][id]
+		if !found {
+			continue
+		}
+
+		var sum float32
+		maxWeight, maxAt := float32(-1), 0
+		for j, p := range positions {
+			sum += p.weight / float32((j+1)*(j+1))
+			if p.weight > maxWeight {
+				maxWeight, maxAt = p.weight, j
+			}
+		}
+		// Сумма ряда 1/i^2 равна pi^2/6
+		rank += float32(float64(maxWeight+sum-maxWeight/float32((maxAt+1)*(maxAt+1))) / 1.64493406685)
+	}
+
+	if len(lexemes) > 0 {
+		rank /= float32(len(lexemes))
+	}
+	return rank
+}
+
+// rankAnd оценивает близость вхождений разных лексем друг к другу (calc_rank_and)
+func (idx *Index) rankAnd(id int64, lexemes []string) float32 {
+	rank := float32(-1)
+	for i, lexeme := range lexemes {
+		positions := idx.postings[lexeme][id]
+		for _, other := range lexemes[:i] {
+			for _, a := range positions {
+				for _, b := range idx.postings[other][id] {
+					distance := a.pos - b.pos
+					if distance < 0 {
+						distance = -distance
+					}
+					if distance == 0 {
+						continue
+					}
+					w := float32(math.Sqrt(float64(a.weight * b.weight * wordDistance(distance))))
+					if rank < 0 {
+						rank = w
+					} else {
+						rank = 1 - (1-rank)*(1-w)
+					}
+				}
+			}
+		}
+	}
+	return rank
+}
+
+// wordDistance вес расстояния между словами (word_distance в PostgreSQL)
+func wordDistance(distance int) float32 {
+	if distance > 100 {
+		return 1e-30
+	}
+	return float32(1.0 / (1.005 + 0.05*math.Exp(float64(float32(distance))/1.5-2)))
+}
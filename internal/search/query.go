@@ -0,0 +1,59 @@
+package search
+
+import (
+	"sort"
+	"strings"
+)
+
+// Query разобранный поисковый запрос: все Include лексемы должны встречаться
+// в документе, ни одна из Exclude - не должна
+type Query struct {
+	Include []string
+	Exclude []string
+}
+
+// ParseQuery разбирает запрос в стиле websearch для языка language: слова через пробел
+// объединяются по AND, слово с префиксом '-' исключает документы, кавычки игнорируются.
+// Слова приводятся к лексемам, стоп-слова отбрасываются.
+func ParseQuery(text, language string) Query {
+	var q Query
+
+	for _, field := range strings.Fields(text) {
+		exclude := strings.HasPrefix(field, "-") && len(field) > 1
+		if exclude {
+			field = field[1:]
+		}
+
+		for _, token := range Tokenize(field) {
+			lexeme := Lexeme(token.Term, language)
+			switch {
+			case lexeme == "":
+			case exclude:
+				q.Exclude = append(q.Exclude, lexeme)
+			default:
+				q.Include = append(q.Include, lexeme)
+			}
+		}
+	}
+
+	return q
+}
+
+// Empty проверяет, что в запросе нет искомых лексем
+func (q Query) Empty() bool {
+	return len(q.Include) == 0
+}
+
+// Lexemes возвращает все различные лексемы запроса, включая исключенные
+func (q Query) Lexemes() []string {
+	seen := make(map[string]bool)
+	var lexemes []string
+	for _, lexeme := range append(append([]string(nil), q.Include...), q.Exclude...) {
+		if !seen[lexeme] {
+			seen[lexeme] = true
+			lexemes = append(lexemes, lexeme)
+		}
+	}
+	sort.Strings(lexemes)
+	return lexemes
+}
@@ -0,0 +1,65 @@
+package search
+
+import "strings"
+
+// Маркеры подсветки совпадений в сниппетах (те же, что передаются в ts_headline)
+const (
+	HighlightStart = "<mark>"
+	HighlightStop  = "</mark>"
+)
+
+// snippetWords количество слов в сниппете
+const snippetWords = 20
+
+// Snippet возвращает фрагмент текста вокруг первого совпадения с лексемами,
+// слова, дающие эти лексемы в языке language, обрамляются маркерами подсветки
+func Snippet(text, language string, lexemes []string) string {
+	tokens := Tokenize(text)
+	if len(tokens) == 0 {
+		return ""
+	}
+
+	wanted := make(map[string]bool, len(lexemes))
+	for _, lexeme := range lexemes {
+		wanted[lexeme] = true
+	}
+	matched := make([]bool, len(tokens))
+	for i, token := range tokens {
+		matched[i] = wanted[Lexeme(token.Term, language)]
+	}
+
+	// Окно начинается чуть раньше первого совпадения
+	first := 0
+	for i := range tokens {
+		if matched[i] {
+			first = i
+			break
+		}
+	}
+	start := max(0, first-snippetWords/4)
+	end := min(len(tokens), start+snippetWords)
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("...")
+	}
+
+	pos := tokens[start].Start
+	for i, token := range tokens[start:end] {
+		sb.WriteString(text[pos:token.Start])
+		if matched[start+i] {
+			sb.WriteString(HighlightStart)
+			sb.WriteString(text[token.Start:token.End])
+			sb.WriteString(HighlightStop)
+		} else {
+			sb.WriteString(text[token.Start:token.End])
+		}
+		pos = token.End
+	}
+
+	if end < len(tokens) {
+		sb.WriteString("...")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,302 @@
+package search
+
+import "strings"
+
+// Стеммер английского языка по алгоритму Snowball (Porter2), как english_stem в PostgreSQL
+
+// englishExceptions слова, которые стеммер обрабатывает особым образом
+var englishExceptions = map[string]string{
+	"skis": "ski", "skies": "sky", "dying": "die", "lying": "lie", "tying": "tie",
+	"idly": "idl", "gently": "gentl", "ugly": "ugli", "early": "earli", "only": "onli",
+	"singly": "singl", "sky": "sky", "news": "news", "howe": "howe",
+	"atlas": "atlas", "cosmos": "cosmos", "bias": "bias", "andes": "andes",
+}
+
+// englishExceptions2 слова, которые не меняются после шага 1a
+var englishExceptions2 = map[string]bool{
+	"inning": true, "outing": true, "canning": true, "herring": true,
+	"earring": true, "proceed": true, "exceed": true, "succeed": true,
+}
+
+// englishStep2 замены шага 2 (применяются в R1)
+var englishStep2 = []struct{ suffix, replacement string }{
+	{"ization", "ize"}, {"ational", "ate"}, {"fulness", "ful"}, {"ousness", "ous"},
+	{"iveness", "ive"}, {"tional", "tion"}, {"biliti", "ble"}, {"lessli", "less"},
+	{"entli", "ent"}, {"ation", "ate"}, {"alism", "al"}, {"aliti", "al"},
+	{"ousli", "ous"}, {"iviti", "ive"}, {"fulli", "ful"}, {"enci", "ence"},
+	{"anci", "ance"}, {"abli", "able"}, {"izer", "ize"}, {"ator", "ate"},
+	{"alli", "al"}, {"bli", "ble"}, {"ogi", "og"}, {"li", ""},
+}
+
+// englishStep3 замены шага 3 (применяются в R1)
+var englishStep3 = []struct{ suffix, replacement string }{
+	{"ational", "ate"}, {"tional", "tion"}, {"alize", "al"}, {"icate", "ic"},
+	{"iciti", "ic"}, {"ative", ""}, {"ical", "ic"}, {"ness", ""}, {"ful", ""},
+}
+
+// englishStep4 суффиксы шага 4 (удаляются в R2)
+var englishStep4 = []string{
+	"ement", "ance", "ence", "able", "ible", "ment", "ant", "ent", "ism", "ate",
+	"iti", "ous", "ive", "ize", "ion", "al", "er", "ic",
+}
+
+// StemEnglish возвращает основу английского слова в нижнем регистре
+func StemEnglish(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+	if stem, ok := englishExceptions[word]; ok {
+		return stem
+	}
+
+	w := []byte(strings.TrimPrefix(word, "'"))
+
+	// Y в начале слова и после гласной считается согласной
+	for i := range w {
+		if w[i] == 'y' && (i == 0 || isEnglishVowel(w[i-1])) {
+			w[i] = 'Y'
+		}
+	}
+
+	r1, r2 := englishRegions(w)
+
+	w = englishStep0(w)
+	w = englishStep1a(w)
+	if englishExceptions2[string(w)] {
+		return strings.ReplaceAll(string(w), "Y", "y")
+	}
+	w = englishStep1b(w, r1)
+	w = englishStep1c(w)
+	w = englishReplace(w, englishStep2, r1)
+	w = englishStep3Apply(w, r1, r2)
+	w = englishStep4Apply(w, r2)
+	w = englishStep5(w, r1, r2)
+
+	return strings.ReplaceAll(string(w), "Y", "y")
+}
+
+// isEnglishVowel проверяет, является ли буква гласной
+func isEnglishVowel(c byte) bool {
+	switch c {
+	case 'a', 'e', 'i', 'o', 'u', 'y':
+		return true
+	}
+	return false
+}
+
+// englishRegions вычисляет начала областей R1 и R2
+func englishRegions(w []byte) (r1, r2 int) {
+	r1 = len(w)
+	s := string(w)
+	switch {
+	case strings.HasPrefix(s, "gener"), strings.HasPrefix(s, "arsen"):
+		r1 = 5
+	case strings.HasPrefix(s, "commun"):
+		r1 = 6
+	default:
+		r1 = regionAfter(w, 0)
+	}
+	r2 = regionAfter(w, r1)
+	return r1, r2
+}
+
+// regionAfter возвращает позицию после первой согласной, следующей за гласной, начиная с from
+func regionAfter(w []byte, from int) int {
+	for i := from + 1; i < len(w); i++ {
+		if !isEnglishVowel(w[i]) && isEnglishVowel(w[i-1]) {
+			return i + 1
+		}
+	}
+	return len(w)
+}
+
+// hasSuffix проверяет окончание слова
+func hasSuffix(w []byte, suffix string) bool {
+	return strings.HasSuffix(string(w), suffix)
+}
+
+// containsVowel проверяет наличие гласной в w
+func containsVowel(w []byte) bool {
+	for _, c := range w {
+		if isEnglishVowel(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// endsShortSyllable проверяет, заканчивается ли слово на короткий слог
+func endsShortSyllable(w []byte) bool {
+	n := len(w)
+	if n == 2 {
+		return isEnglishVowel(w[0]) && !isEnglishVowel(w[1])
+	}
+	if n >= 3 {
+		c := w[n-1]
+		return !isEnglishVowel(w[n-3]) && isEnglishVowel(w[n-2]) &&
+			!isEnglishVowel(c) && c != 'w' && c != 'x' && c != 'Y'
+	}
+	return false
+}
+
+// isShortWord проверяет, является ли слово коротким
+func isShortWord(w []byte, r1 int) bool {
+	return r1 >= len(w) && endsShortSyllable(w)
+}
+
+func englishStep0(w []byte) []byte {
+	for _, suffix := range []string{"'s'", "'s", "'"} {
+		if hasSuffix(w, suffix) {
+			return w[:len(w)-len(suffix)]
+		}
+	}
+	return w
+}
+
+func englishStep1a(w []byte) []byte {
+	switch {
+	case hasSuffix(w, "sses"):
+		return w[:len(w)-2]
+	case hasSuffix(w, "ied"), hasSuffix(w, "ies"):
+		if len(w) > 4 {
+			return append(w[:len(w)-3], 'i')
+		}
+		return append(w[:len(w)-3], 'i', 'e')
+	case hasSuffix(w, "us"), hasSuffix(w, "ss"):
+		return w
+	case hasSuffix(w, "s"):
+		// Удаляем s, если перед предпоследней буквой есть гласная
+		if len(w) >= 3 && containsVowel(w[:len(w)-2]) {
+			return w[:len(w)-1]
+		}
+	}
+	return w
+}
+
+func englishStep1b(w []byte, r1 int) []byte {
+	for _, suffix := range []string{"eedly", "eed"} {
+		if hasSuffix(w, suffix) {
+			if len(w)-len(suffix) >= r1 {
+				return w[:len(w)-len(suffix)+2]
+			}
+			return w
+		}
+	}
+
+	for _, suffix := range []string{"ingly", "edly", "ing", "ed"} {
+		if !hasSuffix(w, suffix) {
+			continue
+		}
+		stem := w[:len(w)-len(suffix)]
+		if !containsVowel(stem) {
+			return w
+		}
+		w = stem
+		switch {
+		case hasSuffix(w, "at"), hasSuffix(w, "bl"), hasSuffix(w, "iz"):
+			return append(w, 'e')
+		case endsDouble(w):
+			return w[:len(w)-1]
+		case isShortWord(w, r1):
+			return append(w, 'e')
+		}
+		return w
+	}
+
+	return w
+}
+
+// endsDouble проверяет окончание на удвоенную согласную
+func endsDouble(w []byte) bool {
+	n := len(w)
+	if n < 2 || w[n-1] != w[n-2] {
+		return false
+	}
+	switch w[n-1] {
+	case 'b', 'd', 'f', 'g', 'm', 'n', 'p', 'r', 't':
+		return true
+	}
+	return false
+}
+
+func englishStep1c(w []byte) []byte {
+	n := len(w)
+	if n > 2 && (w[n-1] == 'y' || w[n-1] == 'Y') && !isEnglishVowel(w[n-2]) {
+		w[n-1] = 'i'
+	}
+	return w
+}
+
+// englishReplace заменяет самый длинный подходящий суффикс, если он лежит в R1
+func englishReplace(w []byte, rules []struct{ suffix, replacement string }, r1 int) []byte {
+	for _, rule := range rules {
+		if !hasSuffix(w, rule.suffix) {
+			continue
+		}
+		start := len(w) - len(rule.suffix)
+		if start < r1 {
+			return w
+		}
+		switch rule.suffix {
+		case "ogi":
+			if start == 0 || w[start-1] != 'l' {
+				return w
+			}
+		case "li":
+			if start == 0 || !strings.ContainsRune("cdeghkmnrt", rune(w[start-1])) {
+				return w
+			}
+		}
+		return append(w[:start], rule.replacement...)
+	}
+	return w
+}
+
+func englishStep3Apply(w []byte, r1, r2 int) []byte {
+	for _, rule := range englishStep3 {
+		if !hasSuffix(w, rule.suffix) {
+			continue
+		}
+		start := len(w) - len(rule.suffix)
+		if start < r1 || (rule.suffix == "ative" && start < r2) {
+			return w
+		}
+		return append(w[:start], rule.replacement...)
+	}
+	return w
+}
+
+func englishStep4Apply(w []byte, r2 int) []byte {
+	for _, suffix := range englishStep4 {
+		if !hasSuffix(w, suffix) {
+			continue
+		}
+		start := len(w) - len(suffix)
+		if start < r2 {
+			return w
+		}
+		if suffix == "ion" && (start == 0 || (w[start-1] != 's' && w[start-1] != 't')) {
+			return w
+		}
+		return w[:start]
+	}
+	return w
+}
+
+func englishStep5(w []byte, r1, r2 int) []byte {
+	n := len(w)
+	if n == 0 {
+		return w
+	}
+	switch w[n-1] {
+	case 'e':
+		if n-1 >= r2 || (n-1 >= r1 && !endsShortSyllable(w[:n-1])) {
+			return w[:n-1]
+		}
+	case 'l':
+		if n-1 >= r2 && n >= 2 && w[n-2] == 'l' {
+			return w[:n-1]
+		}
+	}
+	return w
+}
@@ -0,0 +1,54 @@
+package search
+
+import "testing"
+
+func TestStemEnglish(t *testing.T) {
+	// Ожидаемые основы совпадают с english_stem (Snowball Porter2)
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"a", "a"},
+		{"is", "is"},
+		{"cats", "cat"},
+		{"caresses", "caress"},
+		{"ponies", "poni"},
+		{"ties", "tie"},
+		{"gas", "gas"},
+		{"gaps", "gap"},
+		{"cried", "cri"},
+		{"agreed", "agre"},
+		{"running", "run"},
+		{"hopping", "hop"},
+		{"yelling", "yell"},
+		{"controlling", "control"},
+		{"consigned", "consign"},
+		{"consignment", "consign"},
+		{"generously", "generous"},
+		{"knightly", "knight"},
+		{"fluently", "fluentli"}, // entli вне R1: более короткий суффикс li не рассматривается
+		{"relational", "relat"},
+		{"sensational", "sensat"},
+		{"generation", "generat"},
+		{"connections", "connect"},
+		{"university", "univers"},
+		{"abilities", "abil"},
+		{"happiness", "happi"},
+		{"hopeful", "hope"},
+		{"luxuriating", "luxuri"},
+		// Исключения
+		{"skies", "sky"},
+		{"sky", "sky"},
+		{"news", "news"},
+		{"succeeded", "succeed"},
+		{"proceed", "proceed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			if got := StemEnglish(tt.word); got != tt.want {
+				t.Errorf("StemEnglish(%q) = %q, want %q", tt.word, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package search
+
+// Стеммер русского языка по алгоритму Snowball, как russian_stem в PostgreSQL
+
+var (
+	// Окончания, которые удаляются только после 'а' или 'я'
+	russianPerfectiveGerund1 = []string{"вшись", "вши", "в"}
+	russianPerfectiveGerund2 = []string{"ившись", "ывшись", "ивши", "ывши", "ив", "ыв"}
+
+	russianAdjective = []string{
+		"ими", "ыми", "его", "ого", "ему", "ому",
+		"ее", "ие", "ые", "ое", "ей", "ий", "ый", "ой", "ем", "им", "ым", "ом",
+		"их", "ых", "ую", "юю", "ая", "яя", "ою", "ею",
+	}
+
+	russianParticiple1 = []string{"ем", "нн", "вш", "ющ", "щ"}
+	russianParticiple2 = []string{"ивш", "ывш", "ующ"}
+
+	russianReflexive = []string{"ся", "сь"}
+
+	russianVerb1 = []string{
+		"ете", "йте", "ешь", "нно",
+		"ла", "на", "ли", "ем", "ло", "но", "ет", "ют", "ны", "ть",
+		"й", "л", "н",
+	}
+	russianVerb2 = []string{
+		"ейте", "уйте",
+		"ила", "ыла", "ена", "ите", "или", "ыли", "ило", "ыло", "ено", "ует", "уют", "ены", "ить", "ыть", "ишь",
+		"ей", "уй", "ил", "ыл", "им", "ым", "ен", "ят", "ит", "ыт", "ую",
+		"ю",
+	}
+
+	russianNoun = []string{
+		"иями", "ями", "ами", "ией", "иям", "ием", "иях",
+		"ев", "ов", "ие", "ье", "еи", "ии", "ей", "ой", "ий", "ям", "ем", "ам", "ом", "ах", "ях", "ию", "ью", "ия", "ья",
+		"а", "е", "и", "й", "о", "у", "ы", "ь", "ю", "я",
+	}
+
+	russianSuperlative  = []string{"ейше", "ейш"}
+	russianDerivational = []string{"ость", "ост"}
+)
+
+// isRussianVowel проверяет, является ли буква гласной
+func isRussianVowel(r rune) bool {
+	switch r {
+	case 'а', 'е', 'и', 'о', 'у', 'ы', 'э', 'ю', 'я':
+		return true
+	}
+	return false
+}
+
+// StemRussian возвращает основу русского слова в нижнем регистре
+func StemRussian(word string) string {
+	w := []rune(word)
+	for i, r := range w {
+		if r == 'ё' {
+			w[i] = 'е'
+		}
+	}
+
+	rv, r2 := russianRegions(w)
+	if rv >= len(w) {
+		return string(w)
+	}
+
+	// Все окончания ищутся только в области RV
+	head, tail := w[:rv], w[rv:]
+
+	tail = russianStep1(tail)
+
+	// Шаг 2: удаляем 'и' на конце
+	if n := len(tail); n > 0 && tail[n-1] == 'и' {
+		tail = tail[:n-1]
+	}
+
+	// Шаг 3: словообразовательное окончание в R2
+	if suffix := longestSuffix(tail, russianDerivational); suffix != "" {
+		if rv+len(tail)-runeLen(suffix) >= r2 {
+			tail = tail[:len(tail)-runeLen(suffix)]
+		}
+	}
+
+	// Шаг 4: превосходная степень, удвоенная 'н', мягкий знак
+	if suffix := longestSuffix(tail, russianSuperlative); suffix != "" {
+		tail = undoubleN(tail[:len(tail)-runeLen(suffix)])
+	} else if n := len(tail); n > 0 && tail[n-1] == 'ь' {
+		tail = tail[:n-1]
+	} else {
+		tail = undoubleN(tail)
+	}
+
+	return string(head) + string(tail)
+}
+
+// undoubleN заменяет 'нн' на конце на 'н'
+func undoubleN(w []rune) []rune {
+	if n := len(w); n >= 2 && w[n-1] == 'н' && w[n-2] == 'н' {
+		return w[:n-1]
+	}
+	return w
+}
+
+// russianRegions вычисляет начала областей RV и R2
+func russianRegions(w []rune) (rv, r2 int) {
+	rv = len(w)
+	for i, r := range w {
+		if isRussianVowel(r) {
+			rv = i + 1
+			break
+		}
+	}
+
+	r1 := russianRegionAfter(w, 0)
+	r2 = russianRegionAfter(w, r1)
+	return rv, r2
+}
+
+// russianRegionAfter возвращает позицию после первой согласной, следующей за гласной, начиная с from
+func russianRegionAfter(w []rune, from int) int {
+	for i := from + 1; i < len(w); i++ {
+		if !isRussianVowel(w[i]) && isRussianVowel(w[i-1]) {
+			return i + 1
+		}
+	}
+	return len(w)
+}
+
+// russianStep1 удаляет деепричастные, возвратные, прилагательные, глагольные и именные окончания
+func russianStep1(w []rune) []rune {
+	if stem, ok := removeEnding(w, russianPerfectiveGerund1, russianPerfectiveGerund2); ok {
+		return stem
+	}
+
+	if suffix := longestSuffix(w, russianReflexive); suffix != "" {
+		w = w[:len(w)-runeLen(suffix)]
+	}
+
+	if stem, ok := removeAdjectival(w); ok {
+		return stem
+	}
+	if stem, ok := removeEnding(w, russianVerb1, russianVerb2); ok {
+		return stem
+	}
+	if suffix := longestSuffix(w, russianNoun); suffix != "" {
+		return w[:len(w)-runeLen(suffix)]
+	}
+
+	return w
+}
+
+// removeAdjectival удаляет окончание прилагательного и, возможно, суффикс причастия перед ним
+func removeAdjectival(w []rune) ([]rune, bool) {
+	suffix := longestSuffix(w, russianAdjective)
+	if suffix == "" {
+		return w, false
+	}
+	w = w[:len(w)-runeLen(suffix)]
+
+	if stem, ok := removeEnding(w, russianParticiple1, russianParticiple2); ok {
+		return stem, true
+	}
+	return w, true
+}
+
+// removeEnding удаляет самое длинное окончание из двух групп.
+// Окончания первой группы удаляются, только если перед ними стоит 'а' или 'я'.
+func removeEnding(w []rune, group1, group2 []string) ([]rune, bool) {
+	s1 := longestSuffix(w, group1)
+	s2 := longestSuffix(w, group2)
+
+	// Как и among в Snowball, проверяется только самое длинное окончание
+	if s1 != "" && runeLen(s1) > runeLen(s2) {
+		start := len(w) - runeLen(s1)
+		if start > 0 && (w[start-1] == 'а' || w[start-1] == 'я') {
+			return w[:start], true
+		}
+		return w, false
+	}
+	if s2 != "" {
+		return w[:len(w)-runeLen(s2)], true
+	}
+	return w, false
+}
+
+// longestSuffix возвращает самый длинный суффикс из списка, которым заканчивается w
+func longestSuffix(w []rune, suffixes []string) string {
+	best := ""
+	for _, suffix := range suffixes {
+		n := runeLen(suffix)
+		if n > runeLen(best) && n <= len(w) && string(w[len(w)-n:]) == suffix {
+			best = suffix
+		}
+	}
+	return best
+}
+
+// runeLen возвращает длину строки в символах
+func runeLen(s string) int {
+	return len([]rune(s))
+}
@@ -0,0 +1,40 @@
+package search
+
+import "testing"
+
+func TestStemRussian(t *testing.T) {
+	// Ожидаемые основы совпадают с russian_stem (Snowball)
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"и", "и"},
+		{"он", "он"},
+		{"стола", "стол"},
+		{"столы", "стол"},
+		{"книги", "книг"},
+		{"книгами", "книг"},
+		{"машины", "машин"},
+		{"заметки", "заметк"},
+		{"заметок", "заметок"},
+		{"красивая", "красив"},
+		{"маленький", "маленьк"},
+		{"длинный", "длин"},       // Удвоенная н
+		{"важнейший", "важн"},     // Превосходная степень
+		{"красивейшая", "красив"}, // Превосходная степень после окончания прилагательного
+		{"бегать", "бега"},        // Глагол
+		{"говорил", "говор"},      // Глагол прошедшего времени
+		{"бегающий", "бега"},      // Причастие
+		{"программирование", "программирован"},
+		{"достопримечательность", "достопримечательн"}, // Словообразовательный суффикс в R2
+		{"ёлки", "елк"}, // ё приводится к е
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			if got := StemRussian(tt.word); got != tt.want {
+				t.Errorf("StemRussian(%q) = %q, want %q", tt.word, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Token слово текста с позицией в исходной строке
+type Token struct {
+	Term  string // Нормализованное слово
+	Start int    // Смещение начала слова в байтах
+	End   int    // Смещение конца слова в байтах
+}
+
+// Tokenize разбивает текст на слова (последовательности букв и цифр) в нижнем регистре
+func Tokenize(text string) []Token {
+	var tokens []Token
+
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, newToken(text, start, i))
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, newToken(text, start, len(text)))
+	}
+
+	return tokens
+}
+
+// newToken создает токен для подстроки text[start:end]
+func newToken(text string, start, end int) Token {
+	return Token{
+		Term:  strings.ToLower(text[start:end]),
+		Start: start,
+		End:   end,
+	}
+}
@@ -0,0 +1,39 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []Token
+	}{
+		{name: "empty", text: "", want: nil},
+		{name: "only separators", text: " ,.-!? ", want: nil},
+		{name: "lower case", text: "Hello World", want: []Token{{"hello", 0, 5}, {"world", 6, 11}}},
+		{name: "punctuation", text: "one,two...three", want: []Token{{"one", 0, 3}, {"two", 4, 7}, {"three", 10, 15}}},
+		{name: "digits are word characters", text: "v2.0 2024", want: []Token{{"v2", 0, 2}, {"0", 3, 4}, {"2024", 5, 9}}},
+		{name: "apostrophe splits words", text: "don't", want: []Token{{"don", 0, 3}, {"t", 4, 5}}},
+		// Смещения в байтах: кириллическая буква занимает два байта
+		{name: "cyrillic offsets", text: "Привет, мир!", want: []Token{{"привет", 0, 12}, {"мир", 14, 20}}},
+		{name: "mixed scripts", text: "Go и Rust", want: []Token{{"go", 0, 2}, {"и", 3, 5}, {"rust", 6, 10}}},
+		{name: "word at end", text: "  заметка", want: []Token{{"заметка", 2, 16}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Tokenize(tt.text)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Tokenize(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+			for _, token := range got {
+				if original := tt.text[token.Start:token.End]; len(original) != len(token.Term) {
+					t.Errorf("token %q points to %q", token.Term, original)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"notes-api/internal/domain"
+)
+
+// subscriberBuffer сколько событий может ждать отправки подписчику.
+// Подписчик, который не успевает их забирать, отключается и может продолжить с Last-Event-ID.
+const subscriberBuffer = 256
+
+// EventPublisher получает события изменения заметок от NoteService
+type EventPublisher interface {
+	Publish(eventType string, noteID int64, note *domain.Note)
+}
+
+// EventBroker рассылает события изменения заметок подписчикам и хранит последние события,
+// чтобы переподключившийся клиент получил пропущенные
+type EventBroker struct {
+	mu          sync.Mutex
+	log         []domain.NoteEvent // Кольцевой буфер последних событий
+	start       int                // Индекс самого старого события в log
+	size        int                // Сколько событий в log
+	lastID      int64
+	subscribers map[*EventSubscription]struct{}
+	closed      bool
+}
+
+// NewEventBroker создает брокер, который хранит до logSize последних событий
+func NewEventBroker(logSize int) *EventBroker {
+	return &EventBroker{
+		log:         make([]domain.NoteEvent, max(logSize, 1)),
+		subscribers: make(map[*EventSubscription]struct{}),
+	}
+}
+
+// EventSubscription подписка на события
+type EventSubscription struct {
+	// Backlog события из журнала после запрошенного номера, которые нужно отправить первыми
+	Backlog []domain.NoteEvent
+	// Gap - часть событий после запрошенного номера уже вытеснена из журнала;
+	// клиенту нужно заново загрузить заметки
+	Gap bool
+	// ResumeID при Gap - номер, после которого продолжается поток (перед самым старым событием журнала)
+	ResumeID int64
+	// Events новые события; канал закрывается при Unsubscribe, остановке брокера
+	// или если подписчик не успевает забирать события
+	Events <-chan domain.NoteEvent
+
+	broker *EventBroker
+	events chan domain.NoteEvent
+	filter func(domain.NoteEvent) bool
+}
+
+// Publish добавляет событие в журнал и рассылает подписчикам. note читается без блокировки
+// репозитория, поэтому передается заметка, которую вернул репозиторий: это всегда копия.
+func (b *EventBroker) Publish(eventType string, noteID int64, note *domain.Note) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event := domain.NoteEvent{ID: b.lastID, Type: eventType, NoteID: noteID, Time: time.Now().UTC()}
+	if note != nil {
+		// Копия: вызывающий код может продолжать работать со своей заметкой
+		snapshot := *note
+		snapshot.Tags = slices.Clone(note.Tags)
+		event.Note = &snapshot
+	}
+
+	if b.size < len(b.log) {
+		b.log[(b.start+b.size)%len(b.log)] = event
+		b.size++
+	} else {
+		b.log[b.start] = event
+		b.start = (b.start + 1) % len(b.log)
+	}
+
+	for sub := range b.subscribers {
+		if sub.filter != nil && !sub.filter(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			// Медленный подписчик не задерживает остальных
+			b.remove(sub)
+		}
+	}
+}
+
+// Subscribe подписывает на события после события с номером lastID (0 - только новые события).
+// filter отбирает события (nil - все). Пропущенные события из журнала возвращаются в Backlog.
+func (b *EventBroker) Subscribe(lastID int64, filter func(domain.NoteEvent) bool) *EventSubscription {
+	events := make(chan domain.NoteEvent, subscriberBuffer)
+	sub := &EventSubscription{Events: events, broker: b, events: events, filter: filter}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		close(events)
+		return sub
+	}
+
+	if lastID > b.lastID {
+		// Номер из прошлого запуска сервера: номера событий начинаются заново,
+		// поэтому неизвестно, что пропущено
+		sub.Gap = true
+		lastID = 0
+	}
+	if lastID > 0 || sub.Gap {
+		oldest := b.lastID - int64(b.size) + 1
+		sub.Gap = sub.Gap || lastID < oldest-1
+		sub.ResumeID = oldest - 1
+		for i := range b.size {
+			event := b.log[(b.start+i)%len(b.log)]
+			if event.ID > lastID && (filter == nil || filter(event)) {
+				sub.Backlog = append(sub.Backlog, event)
+			}
+		}
+	}
+
+	b.subscribers[sub] = struct{}{}
+	return sub
+}
+
+// Unsubscribe отменяет подписку
+func (s *EventSubscription) Unsubscribe() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.broker.remove(s)
+}
+
+// Close закрывает все подписки; потоки событий завершаются, чтобы не задерживать остановку сервера
+func (b *EventBroker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subscribers {
+		b.remove(sub)
+	}
+}
+
+// Closed проверяет, остановлен ли брокер. Подписчик, чей канал закрылся до остановки брокера,
+// не успевал забирать события и может подписаться снова с номером последнего полученного события.
+func (b *EventBroker) Closed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.closed
+}
+
+// remove удаляет подписчика и закрывает его канал; вызывается под b.mu
+func (b *EventBroker) remove(sub *EventSubscription) {
+	if _, ok := b.subscribers[sub]; ok {
+		delete(b.subscribers, sub)
+		close(sub.events)
+	}
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"io"
+
+	"notes-api/internal/domain"
+	"notes-api/internal/format"
+	"notes-api/internal/repository"
+)
+
+// oldestFirst порядок выгрузки: от старых заметок к новым
+var oldestFirst = []domain.SortField{{Field: domain.SortByCreatedAt}}
+
+// ExportService выгружает все заметки
+type ExportService struct {
+	repo repository.NoteRepository
+}
+
+// NewExportService создает новый сервис
+func NewExportService(repo repository.NoteRepository) *ExportService {
+	return &ExportService{repo: repo}
+}
+
+// Export пишет все заметки в w в формате f от старых к новым.
+// Заметки читаются из репозитория по одной, поэтому выгрузка не держит в памяти все заметки.
+func (s *ExportService) Export(w io.Writer, f format.ExportFormat) error {
+	exporter := format.NewExporter(f, w)
+	for note, err := range s.repo.All(domain.NoteFilter{}, oldestFirst) {
+		if err != nil {
+			return err
+		}
+		if err := exporter.Write(note); err != nil {
+			return err
+		}
+	}
+	return exporter.Close()
+}
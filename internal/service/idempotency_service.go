@@ -0,0 +1,113 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"notes-api/internal/domain"
+	"notes-api/internal/repository"
+)
+
+const (
+	// idempotencyLockTimeout - сколько ключ остается занятым выполняющимся запросом.
+	// Если сервер упал, не сохранив ответ, по истечении этого времени запрос можно повторить.
+	idempotencyLockTimeout = time.Minute
+
+	// idempotencyCleanupInterval - как часто удаляются ключи с истекшим сроком хранения
+	idempotencyCleanupInterval = time.Hour
+)
+
+var (
+	// ErrIdempotencyKeyReused ключ уже использован с другим запросом (422 Unprocessable Entity)
+	ErrIdempotencyKeyReused     = errors.New("idempotency key has already been used with a different request")
+	ErrIdempotencyKeyInProgress = domain.NewConflictError("a request with this idempotency key is still being processed")
+)
+
+// IdempotencyService хранит ответы на запросы с заголовком Idempotency-Key,
+// чтобы повтор запроса вернул исходный ответ, а не выполнил запрос еще раз
+type IdempotencyService struct {
+	repo repository.IdempotencyRepository
+	ttl  time.Duration
+}
+
+// NewIdempotencyService создает сервис; ответы хранятся ttl. При ttl <= 0 ключи не используются.
+func NewIdempotencyService(repo repository.IdempotencyRepository, ttl time.Duration) *IdempotencyService {
+	return &IdempotencyService{repo: repo, ttl: ttl}
+}
+
+// Enabled проверяет, включена ли поддержка Idempotency-Key
+func (s *IdempotencyService) Enabled() bool {
+	return s.ttl > 0
+}
+
+// Begin занимает ключ для запроса с хэшем requestHash. Если ответ на запрос с этим ключом
+// уже сохранен, возвращает его; nil означает, что запрос нужно выполнить и вызвать Complete или Release.
+func (s *IdempotencyService) Begin(key, requestHash string) (*domain.IdempotencyKey, error) {
+	now := time.Now()
+	existing, err := s.repo.ReserveIdempotencyKey(&domain.IdempotencyKey{
+		Key:         key,
+		RequestHash: requestHash,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(idempotencyLockTimeout),
+	})
+	if err != nil || existing == nil {
+		return nil, err
+	}
+
+	if existing.RequestHash != requestHash {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if !existing.Completed() {
+		return nil, ErrIdempotencyKeyInProgress
+	}
+	return existing, nil
+}
+
+// Complete сохраняет ответ на запрос с ключом на время хранения
+func (s *IdempotencyService) Complete(key, requestHash string, statusCode int, contentType, etag string, body []byte) error {
+	now := time.Now()
+	return s.repo.CompleteIdempotencyKey(&domain.IdempotencyKey{
+		Key:         key,
+		RequestHash: requestHash,
+		StatusCode:  statusCode,
+		ContentType: contentType,
+		ETag:        etag,
+		Body:        body,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.ttl),
+	})
+}
+
+// Release освобождает ключ, если запрос не удалось выполнить, чтобы его можно было повторить
+func (s *IdempotencyService) Release(key string) error {
+	return s.repo.DeleteIdempotencyKey(key)
+}
+
+// StartCleanup запускает периодическое удаление ключей с истекшим сроком хранения.
+// Возвращает функцию остановки.
+func (s *IdempotencyService) StartCleanup() (stop func()) {
+	if !s.Enabled() {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(idempotencyCleanupInterval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := s.repo.PurgeIdempotencyKeys(time.Now()); err != nil {
+				log.Printf("Failed to purge idempotency keys: %v", err)
+			}
+
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
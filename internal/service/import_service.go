@@ -0,0 +1,133 @@
+package service
+
+import (
+	"crypto/sha256"
+	"slices"
+	"strings"
+
+	"notes-api/internal/domain"
+	"notes-api/internal/format"
+	"notes-api/internal/repository"
+)
+
+// ImportService импортирует заметки из выгрузок других приложений
+type ImportService struct {
+	repo   repository.NoteRepository
+	events EventPublisher // Получает события о созданных заметках
+}
+
+// NewImportService создает новый сервис
+func NewImportService(repo repository.NoteRepository, events EventPublisher) *ImportService {
+	return &ImportService{repo: repo, events: events}
+}
+
+// importOrigin заметка, с которой совпадает импортируемая
+type importOrigin struct {
+	noteID int64  // Существующая заметка
+	file   string // Файл этого же импорта
+}
+
+// Import создает заметки из файла data формата f (пустой формат определяется по содержимому)
+// и возвращает результат по каждому файлу. Заметки с тем же заголовком и текстом, что у существующей
+// или у файла выше в том же импорте, считаются дубликатами и не создаются.
+// При dryRun отчет строится так же, но ничего не сохраняется.
+func (s *ImportService) Import(data []byte, f format.ImportFormat, dryRun bool) (*domain.ImportReport, error) {
+	if f == "" {
+		detected, err := format.DetectImportFormat(data)
+		if err != nil {
+			return nil, err
+		}
+		f = detected
+	}
+
+	imported, err := format.ParseImport(f, data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Отпечатки существующих заметок для поиска дубликатов
+	seen := make(map[[sha256.Size]byte]importOrigin)
+	for note, err := range s.repo.All(domain.NoteFilter{}, oldestFirst) {
+		if err != nil {
+			return nil, err
+		}
+		seen[noteFingerprint(note.Title, note.Content)] = importOrigin{noteID: note.ID}
+	}
+
+	report := &domain.ImportReport{Format: string(f), DryRun: dryRun, Files: []domain.ImportFileResult{}}
+	var ops []domain.BulkNoteOp
+	for _, note := range imported {
+		result := domain.ImportFileResult{File: note.Source, Title: note.Title}
+
+		op, opErr := importNoteOp(len(report.Files), note)
+		switch {
+		case note.Err != nil:
+			result.Status, result.Error = domain.ImportFailed, note.Err.Error()
+		case note.Skipped != "":
+			result.Status, result.Reason = domain.ImportSkipped, note.Skipped
+		case opErr != nil:
+			result.Status, result.Error = domain.ImportFailed, opErr.Error()
+		default:
+			fingerprint := noteFingerprint(note.Title, note.Content)
+			if origin, exists := seen[fingerprint]; exists {
+				result.Status, result.DuplicateOf, result.DuplicateFile = domain.ImportDuplicate, origin.noteID, origin.file
+				break
+			}
+			seen[fingerprint] = importOrigin{file: note.Source}
+			result.Status = domain.ImportCreated
+			ops = append(ops, op)
+		}
+		report.Add(result)
+	}
+
+	if dryRun {
+		return report, nil
+	}
+
+	// Заметки создаются пакетами; ошибка одной заметки (например, несуществующий блокнот) не мешает остальным
+	for batch := range slices.Chunk(ops, domain.MaxBulkOperations) {
+		results, err := s.repo.Bulk(batch, false)
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range results {
+			if result.Err != nil {
+				report.SetStatus(result.Index, domain.ImportFailed, result.Err.Error())
+				continue
+			}
+			report.Files[result.Index].NoteID = result.ID
+			s.events.Publish(domain.EventNoteCreated, result.ID, result.Note)
+		}
+	}
+
+	return report, nil
+}
+
+// importNoteOp проверяет импортированную заметку так же, как создание в пакетном запросе
+func importNoteOp(index int, note format.ImportedNote) (domain.BulkNoteOp, error) {
+	op, err := bulkNoteOp(index, domain.BulkOperation{
+		Op:         domain.BulkCreate,
+		Title:      note.Title,
+		Content:    note.Content,
+		Tags:       note.Tags,
+		NotebookID: note.NotebookID,
+	})
+	if err != nil {
+		return op, err
+	}
+
+	// Исходные даты заметки сохраняются; без даты изменения (или с датой раньше создания)
+	// заметка считается не изменявшейся
+	op.Note.CreatedAt = note.CreatedAt
+	op.Note.UpdatedAt = note.UpdatedAt
+	if op.Note.UpdatedAt.Before(note.CreatedAt) {
+		op.Note.UpdatedAt = note.CreatedAt
+	}
+	return op, nil
+}
+
+// noteFingerprint отпечаток заголовка и текста заметки без учета пробелов по краям и переводов строк Windows
+func noteFingerprint(title, content string) [sha256.Size]byte {
+	content = strings.TrimSpace(strings.ReplaceAll(content, "\r\n", "\n"))
+	return sha256.Sum256([]byte(strings.TrimSpace(title) + "\x00" + content))
+}
@@ -1,26 +1,69 @@
 package service
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"strings"
+
 	"notes-api/internal/domain"
 	"notes-api/internal/repository"
+	"notes-api/pkg/utils"
+)
+
+// PatchFormat формат тела PATCH запроса
+type PatchFormat string
+
+const (
+	MergePatch PatchFormat = "merge-patch" // application/merge-patch+json
+	JSONPatch  PatchFormat = "json-patch"  // application/json-patch+json
 )
 
+// patchRetries сколько раз PatchNote без версии пробует применить патч при параллельных изменениях
+const patchRetries = 3
+
+// ErrVersionRequired изменение заметки без ожидаемой версии, когда версия обязательна (REQUIRE_IF_MATCH)
+var ErrVersionRequired = domain.NewPreconditionRequiredError("version", "version is required, send the current note version")
+
+// ErrInvalidBulkRequest пакетный запрос пуст или содержит слишком много операций
+var ErrInvalidBulkRequest = domain.NewValidationError("invalid bulk request")
+
+// patchableNote документ заметки, к которому применяется патч
+type patchableNote struct {
+	Title   string   `json:"title"`
+	Content string   `json:"content"`
+	Tags    []string `json:"tags"`
+}
+
 // NoteService реализует бизнес-логику для работы с заметками
 type NoteService struct {
-	repo repository.NoteRepository // Изменено на интерфейс!
+	repo           repository.NoteRepository // Изменено на интерфейс!
+	events         EventPublisher            // Получает события о созданных, измененных и удаленных заметках
+	requireVersion bool                      // Пакетные update и delete без версии отклоняются
 }
 
-// NewNoteService создает новый сервис
-func NewNoteService(repo repository.NoteRepository) *NoteService { // Изменено на интерфейс!
-	return &NoteService{repo: repo}
+// NewNoteService создает новый сервис; events получает события изменения заметок.
+// При requireVersion пакетные update и delete должны передавать ожидаемую версию заметки.
+func NewNoteService(repo repository.NoteRepository, events EventPublisher, requireVersion bool) *NoteService { // Изменено на интерфейс!
+	return &NoteService{repo: repo, events: events, requireVersion: requireVersion}
 }
 
 // CreateNote создает новую заметку
 func (s *NoteService) CreateNote(req domain.CreateNoteRequest) (*domain.Note, error) {
+	// Нормализуем теги
+	tags, err := domain.NormalizeTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+
 	// Создаем новую заметку
 	note := &domain.Note{
-		Title:   req.Title,
-		Content: req.Content,
+		Title:      req.Title,
+		Content:    req.Content,
+		Tags:       tags,
+		NotebookID: req.NotebookID,
 	}
 
 	// Валидируем
@@ -29,12 +72,22 @@ func (s *NoteService) CreateNote(req domain.CreateNoteRequest) (*domain.Note, er
 	}
 
 	// Сохраняем через репозиторий
-	return s.repo.Create(note)
+	created, err := s.repo.Create(note)
+	if err != nil {
+		return nil, err
+	}
+	s.events.Publish(domain.EventNoteCreated, created.ID, created)
+	return created, nil
 }
 
-// GetAllNotes возвращает заметки с пагинацией
-func (s *NoteService) GetAllNotes(limit, offset int) ([]*domain.Note, int, error) {
-	return s.repo.GetAll(limit, offset)
+// GetAllNotes возвращает заметки с фильтрацией, сортировкой и пагинацией
+func (s *NoteService) GetAllNotes(opts domain.NoteListOptions) ([]*domain.Note, int, error) {
+	return s.repo.GetAll(opts)
+}
+
+// StreamNotes перебирает все заметки, подходящие под фильтр, без ограничения количества
+func (s *NoteService) StreamNotes(filter domain.NoteFilter, sort []domain.SortField) iter.Seq2[*domain.Note, error] {
+	return s.repo.All(filter, sort)
 }
 
 // GetNoteByID возвращает заметку по ID
@@ -42,17 +95,29 @@ func (s *NoteService) GetNoteByID(id int64) (*domain.Note, error) {
 	return s.repo.GetByID(id)
 }
 
-// UpdateNote обновляет заметку
-func (s *NoteService) UpdateNote(id int64, req domain.UpdateNoteRequest) (*domain.Note, error) {
+// UpdateNote обновляет заметку. Если version не 0, заметка должна иметь эту версию.
+func (s *NoteService) UpdateNote(id int64, req domain.UpdateNoteRequest, version int64) (*domain.Note, error) {
 	// Проверяем существование заметки
-	if _, err := s.repo.GetByID(id); err != nil {
+	current, err := s.repo.GetByID(id)
+	if err != nil {
 		return nil, err
 	}
+	previousVersion := current.Version
 
 	// Создаем обновленную заметку
 	note := &domain.Note{
 		Title:   req.Title,
 		Content: req.Content,
+		Version: version,
+	}
+
+	// Теги заменяем только если они переданы в запросе
+	if req.Tags != nil {
+		tags, err := domain.NormalizeTags(req.Tags)
+		if err != nil {
+			return nil, err
+		}
+		note.Tags = tags
 	}
 
 	// Валидируем
@@ -61,10 +126,248 @@ func (s *NoteService) UpdateNote(id int64, req domain.UpdateNoteRequest) (*domai
 	}
 
 	// Обновляем через репозиторий
-	return s.repo.Update(id, note)
+	updated, err := s.repo.Update(id, note)
+	if err != nil {
+		return nil, err
+	}
+	s.publishUpdate(previousVersion, updated)
+	return updated, nil
+}
+
+// PatchNote применяет к заметке JSON Merge Patch (RFC 7396) или JSON Patch (RFC 6902).
+// Патч применяется к документу {"title", "content", "tags"}, результат валидируется,
+// в хранилище передаются только изменившиеся поля. Если version не 0, заметка должна иметь эту версию.
+// Без version патч применяется заново, если заметку изменили между чтением и записью.
+func (s *NoteService) PatchNote(id int64, format PatchFormat, patch []byte, version int64) (*domain.Note, error) {
+	for attempt := 1; ; attempt++ {
+		updated, err := s.patchNote(id, format, patch, version)
+		if version != 0 || !errors.Is(err, repository.ErrNoteVersionMismatch) {
+			return updated, err
+		}
+		if attempt == patchRetries {
+			return nil, domain.NewConflictError("note is being modified concurrently, retry the request")
+		}
+	}
+}
+
+// patchNote применяет патч к прочитанной заметке и записывает результат при условии,
+// что версия заметки с момента чтения не изменилась
+func (s *NoteService) patchNote(id int64, format PatchFormat, patch []byte, version int64) (*domain.Note, error) {
+	current, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if version != 0 && version != current.Version {
+		return nil, repository.ErrNoteVersionMismatch
+	}
+
+	doc, err := json.Marshal(patchableNote{
+		Title:   current.Title,
+		Content: current.Content,
+		Tags:    domain.TagNames(current.Tags),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var patched []byte
+	switch format {
+	case MergePatch:
+		patched, err = utils.MergePatch(doc, patch)
+	case JSONPatch:
+		patched, err = utils.ApplyJSONPatch(doc, patch)
+	default:
+		err = fmt.Errorf("unsupported patch format %q", format)
+	}
+	if errors.Is(err, utils.ErrPatchTestFailed) {
+		return nil, domain.NewConflictError(err.Error())
+	}
+	if err != nil {
+		return nil, domain.NewValidationError(err.Error())
+	}
+
+	// Результат должен остаться документом заметки без посторонних полей
+	var result patchableNote
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&result); err != nil {
+		return nil, domain.NewValidationError("invalid patch result: " + err.Error())
+	}
+
+	tags, err := domain.NormalizeTags(result.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if tags == nil {
+		tags = []domain.Tag{} // Удаление tags из документа очищает теги
+	}
+
+	note := &domain.Note{Title: result.Title, Content: result.Content, Tags: tags}
+	if err := note.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Передаем только изменившиеся поля; запись условна по прочитанной версии,
+	// иначе параллельное изменение было бы потеряно, а проверка test ничего бы не гарантировала
+	changes := &domain.NotePatch{Version: current.Version}
+	if note.Title != current.Title {
+		changes.Title = &note.Title
+	}
+	if note.Content != current.Content {
+		changes.Content = &note.Content
+	}
+	if !domain.SameTags(current.Tags, tags) {
+		changes.Tags = tags
+	}
+
+	previousVersion := current.Version
+	updated, err := s.repo.Patch(id, changes)
+	if err != nil {
+		return nil, err
+	}
+	s.publishUpdate(previousVersion, updated)
+	return updated, nil
 }
 
-// DeleteNote удаляет заметку
-func (s *NoteService) DeleteNote(id int64) error {
-	return s.repo.Delete(id)
+// publishUpdate сообщает об изменении заметки, если запрос действительно ее изменил
+func (s *NoteService) publishUpdate(previousVersion int64, note *domain.Note) {
+	if note.Version != previousVersion {
+		s.events.Publish(domain.EventNoteUpdated, note.ID, note)
+	}
+}
+
+// DeleteNote удаляет заметку. Если version не 0, заметка должна иметь эту версию.
+func (s *NoteService) DeleteNote(id int64, version int64) error {
+	if err := s.repo.Delete(id, version); err != nil {
+		return err
+	}
+	s.events.Publish(domain.EventNoteDeleted, id, nil)
+	return nil
+}
+
+// BulkNotes выполняет пакет операций create/update/delete. Операции проверяются до обращения
+// к хранилищу: в атомарном режиме ошибка проверки отменяет весь пакет, иначе - только эту операцию.
+// Возвращает результат каждой операции в исходном порядке.
+func (s *NoteService) BulkNotes(req domain.BulkRequest) ([]domain.BulkResult, error) {
+	if len(req.Operations) == 0 {
+		return nil, fmt.Errorf("%w: operations cannot be empty", ErrInvalidBulkRequest)
+	}
+	if len(req.Operations) > domain.MaxBulkOperations {
+		return nil, fmt.Errorf("%w: too many operations (maximum %d)", ErrInvalidBulkRequest, domain.MaxBulkOperations)
+	}
+
+	results := make([]domain.BulkResult, len(req.Operations))
+	ops := make([]domain.BulkNoteOp, 0, len(req.Operations))
+	invalid := false
+	for i, operation := range req.Operations {
+		op, err := bulkNoteOp(i, operation)
+		if err == nil && s.requireVersion && operation.Op != domain.BulkCreate && operation.Version <= 0 {
+			// Как PUT и DELETE /notes/:id без If-Match при REQUIRE_IF_MATCH
+			err = ErrVersionRequired
+		}
+		if err != nil {
+			results[i] = domain.BulkResult{Index: i, Op: operation.Op, ID: operation.ID, Err: err}
+			invalid = true
+			continue
+		}
+		ops = append(ops, op)
+	}
+
+	// В атомарном режиме некорректная операция отменяет весь пакет
+	if req.Atomic && invalid {
+		for _, op := range ops {
+			results[op.Index] = domain.BulkResult{Index: op.Index, Op: op.Op, ID: op.ID, Err: repository.ErrBulkNotApplied}
+		}
+		return results, nil
+	}
+	if len(ops) == 0 {
+		return results, nil
+	}
+
+	applied, err := s.repo.Bulk(ops, req.Atomic)
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range applied {
+		results[result.Index] = result
+		if result.Err == nil {
+			s.publishBulkResult(result)
+		}
+	}
+
+	return results, nil
+}
+
+// publishBulkResult сообщает о примененной операции пакетного запроса
+func (s *NoteService) publishBulkResult(result domain.BulkResult) {
+	switch result.Op {
+	case domain.BulkCreate:
+		s.events.Publish(domain.EventNoteCreated, result.ID, result.Note)
+	case domain.BulkUpdate:
+		s.events.Publish(domain.EventNoteUpdated, result.ID, result.Note)
+	case domain.BulkDelete:
+		s.events.Publish(domain.EventNoteDeleted, result.ID, nil)
+	}
+}
+
+// bulkNoteOp проверяет операцию пакетного запроса и готовит ее для хранилища
+func bulkNoteOp(index int, operation domain.BulkOperation) (domain.BulkNoteOp, error) {
+	op := domain.BulkNoteOp{Index: index, Op: operation.Op, ID: operation.ID, Version: operation.Version}
+
+	switch operation.Op {
+	case domain.BulkCreate:
+		tags, err := domain.NormalizeTags(operation.Tags)
+		if err != nil {
+			return op, err
+		}
+		op.Note = &domain.Note{
+			Title:      operation.Title,
+			Content:    operation.Content,
+			Tags:       tags,
+			NotebookID: operation.NotebookID,
+		}
+		return op, op.Note.Validate()
+	case domain.BulkUpdate:
+		if operation.ID <= 0 {
+			return op, domain.NewFieldError("id", "invalid note ID")
+		}
+		note := &domain.Note{Title: operation.Title, Content: operation.Content, Version: operation.Version}
+		if operation.Tags != nil {
+			tags, err := domain.NormalizeTags(operation.Tags)
+			if err != nil {
+				return op, err
+			}
+			note.Tags = tags
+		}
+		if err := note.Validate(); err != nil {
+			return op, err
+		}
+		op.Patch = domain.NotePatchFromNote(note)
+		return op, nil
+	case domain.BulkDelete:
+		if operation.ID <= 0 {
+			return op, domain.NewFieldError("id", "invalid note ID")
+		}
+		return op, nil
+	default:
+		return op, domain.NewFieldError("op", fmt.Sprintf("unknown operation %q (allowed: create, update, delete)", operation.Op))
+	}
+}
+
+// MoveNote перемещает заметку в блокнот (или в корень, если notebook_id = null)
+func (s *NoteService) MoveNote(id int64, req domain.MoveNoteRequest) (*domain.Note, error) {
+	note, err := s.repo.MoveNote(id, req.NotebookID)
+	if err != nil {
+		return nil, err
+	}
+	s.events.Publish(domain.EventNoteUpdated, note.ID, note)
+	return note, nil
+}
+
+// SearchNotes выполняет полнотекстовый поиск по заголовку и содержимому
+func (s *NoteService) SearchNotes(query string, limit, offset int) ([]*domain.SearchResult, int, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, 0, domain.NewFieldError("q", "search query cannot be empty")
+	}
+	return s.repo.Search(query, limit, offset)
 }
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"notes-api/internal/domain"
+	"notes-api/internal/repository"
+)
+
+// racingRepository изменяет заметку сразу после первых чтений, как параллельный запрос
+type racingRepository struct {
+	repository.NoteRepository
+	races int // Сколько чтений еще сопровождается параллельным изменением
+}
+
+func (r *racingRepository) GetByID(id int64) (*domain.Note, error) {
+	note, err := r.NoteRepository.GetByID(id)
+	if err != nil || r.races == 0 {
+		return note, err
+	}
+	r.races--
+	copied := *note
+	if _, err := r.NoteRepository.Update(id, &domain.Note{Title: note.Title, Content: note.Content + " edited"}); err != nil {
+		return nil, err
+	}
+	return &copied, nil
+}
+
+func TestPatchNoteConcurrentUpdate(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      PatchFormat
+		patch       string
+		version     int64
+		races       int
+		wantTitle   string
+		wantContent string
+		wantErr     any // Указатель на ожидаемый тип ошибки; nil - патч применяется
+	}{
+		{
+			name: "merge patch reapplied to fresh note", format: MergePatch, patch: `{"title":"patched"}`, races: 1,
+			wantTitle: "patched", wantContent: "text edited",
+		},
+		{
+			name: "json patch test sees concurrent change", format: JSONPatch, races: 1,
+			patch:   `[{"op":"test","path":"/content","value":"text"},{"op":"replace","path":"/title","value":"patched"}]`,
+			wantErr: new(*domain.ConflictError),
+		},
+		{
+			name: "explicit version is not retried", format: MergePatch, patch: `{"title":"patched"}`, version: 1, races: 1,
+			wantErr: new(*domain.PreconditionError),
+		},
+		{
+			name: "retries exhausted", format: MergePatch, patch: `{"title":"patched"}`, races: patchRetries,
+			wantErr: new(*domain.ConflictError),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := repository.NewJSONRepository(filepath.Join(t.TempDir(), "notes.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			note, err := repo.Create(&domain.Note{Title: "title", Content: "text"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			notes := NewNoteService(&racingRepository{NoteRepository: repo, races: tt.races}, NewEventBroker(10), false)
+			patched, err := notes.PatchNote(note.ID, tt.format, []byte(tt.patch), tt.version)
+			if tt.wantErr != nil {
+				if !errors.As(err, tt.wantErr) {
+					t.Fatalf("PatchNote() error = %v, want %T", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PatchNote() error = %v", err)
+			}
+			if patched.Title != tt.wantTitle || patched.Content != tt.wantContent {
+				t.Errorf("PatchNote() = %q / %q, want %q / %q", patched.Title, patched.Content, tt.wantTitle, tt.wantContent)
+			}
+		})
+	}
+}
+
+func TestBulkNotesRequireVersion(t *testing.T) {
+	tests := []struct {
+		name           string
+		requireVersion bool
+		operation      domain.BulkOperation
+		wantErr        bool
+	}{
+		{name: "update without version", requireVersion: true, operation: domain.BulkOperation{Op: domain.BulkUpdate, ID: 1, Title: "t", Content: "c"}, wantErr: true},
+		{name: "delete without version", requireVersion: true, operation: domain.BulkOperation{Op: domain.BulkDelete, ID: 1}, wantErr: true},
+		{name: "update with version", requireVersion: true, operation: domain.BulkOperation{Op: domain.BulkUpdate, ID: 1, Version: 1, Title: "t", Content: "c"}},
+		{name: "delete with version", requireVersion: true, operation: domain.BulkOperation{Op: domain.BulkDelete, ID: 1, Version: 1}},
+		{name: "create needs no version", requireVersion: true, operation: domain.BulkOperation{Op: domain.BulkCreate, Title: "t", Content: "c"}},
+		{name: "version optional", operation: domain.BulkOperation{Op: domain.BulkDelete, ID: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := repository.NewJSONRepository(filepath.Join(t.TempDir(), "notes.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := repo.Create(&domain.Note{Title: "title", Content: "text"}); err != nil {
+				t.Fatal(err)
+			}
+
+			notes := NewNoteService(repo, NewEventBroker(10), tt.requireVersion)
+			results, err := notes.BulkNotes(domain.BulkRequest{Operations: []domain.BulkOperation{tt.operation}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := results[0].Err; tt.wantErr != errors.Is(got, ErrVersionRequired) {
+				t.Errorf("BulkNotes() result error = %v, want ErrVersionRequired: %v", got, tt.wantErr)
+			}
+			if !tt.wantErr && results[0].Err != nil {
+				t.Errorf("BulkNotes() result error = %v", results[0].Err)
+			}
+		})
+	}
+}
+
+// TestNoteEventsDoNotShareNotes проверяет, что события получают копию заметки: параллельные
+// изменения той же заметки не должны менять ее, пока брокер и подписчики ее читают (go test -race)
+func TestNoteEventsDoNotShareNotes(t *testing.T) {
+	repo, err := repository.NewJSONRepository(filepath.Join(t.TempDir(), "notes.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := NewEventBroker(100)
+	notes := NewNoteService(repo, events, false)
+	note, err := notes.CreateNote(domain.CreateNoteRequest{Title: "title", Content: "text"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sub := events.Subscribe(0, nil)
+	defer sub.Unsubscribe()
+	read := make(chan struct{})
+	go func() {
+		defer close(read)
+		for event := range sub.Events {
+			if event.Note != nil {
+				_ = event.Note.Title + event.Note.Content
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Go(func() {
+			for j := range 20 {
+				content := fmt.Sprintf("text %d-%d", i, j)
+				if _, err := notes.UpdateNote(note.ID, domain.UpdateNoteRequest{Title: "title", Content: content}, 0); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+	sub.Unsubscribe()
+	<-read
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"notes-api/internal/domain"
+	"notes-api/internal/repository"
+)
+
+// NotebookService реализует бизнес-логику для работы с блокнотами
+type NotebookService struct {
+	repo  repository.NotebookRepository
+	notes repository.NoteRepository
+}
+
+// NewNotebookService создает новый сервис блокнотов
+func NewNotebookService(repo repository.NotebookRepository, notes repository.NoteRepository) *NotebookService {
+	return &NotebookService{repo: repo, notes: notes}
+}
+
+// CreateNotebook создает новый блокнот
+func (s *NotebookService) CreateNotebook(req domain.CreateNotebookRequest) (*domain.Notebook, error) {
+	notebook := &domain.Notebook{
+		Name:     req.Name,
+		ParentID: req.ParentID,
+	}
+
+	if err := notebook.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.repo.CreateNotebook(notebook)
+}
+
+// GetAllNotebooks возвращает все блокноты
+func (s *NotebookService) GetAllNotebooks() ([]*domain.Notebook, error) {
+	return s.repo.GetAllNotebooks()
+}
+
+// GetNotebookByID возвращает блокнот по ID
+func (s *NotebookService) GetNotebookByID(id int64) (*domain.Notebook, error) {
+	return s.repo.GetNotebookByID(id)
+}
+
+// UpdateNotebook переименовывает блокнот
+func (s *NotebookService) UpdateNotebook(id int64, req domain.UpdateNotebookRequest) (*domain.Notebook, error) {
+	notebook := &domain.Notebook{Name: req.Name}
+
+	if err := notebook.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.repo.UpdateNotebook(id, notebook)
+}
+
+// MoveNotebook перемещает блокнот к другому родителю
+func (s *NotebookService) MoveNotebook(id int64, req domain.MoveNotebookRequest) (*domain.Notebook, error) {
+	if req.ParentID != nil && *req.ParentID == id {
+		return nil, repository.ErrNotebookCycle
+	}
+
+	return s.repo.MoveNotebook(id, req.ParentID)
+}
+
+// DeleteNotebook удаляет пустой блокнот
+func (s *NotebookService) DeleteNotebook(id int64) error {
+	return s.repo.DeleteNotebook(id)
+}
+
+// GetNotebookNotes возвращает заметки блокнота и всех вложенных блокнотов
+func (s *NotebookService) GetNotebookNotes(id int64, opts domain.NoteListOptions) ([]*domain.Note, int, error) {
+	subtree, err := s.repo.GetNotebookSubtree(id)
+	if err != nil {
+		return nil, 0, err
+	}
+	opts.Filter.NotebookIDs = subtree
+
+	return s.notes.GetAll(opts)
+}
@@ -0,0 +1,350 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"notes-api/internal/domain"
+)
+
+// QueryError ошибка разбора структурированного запроса с указанием на ошибочный токен
+type QueryError struct {
+	Position int    // Позиция токена в запросе (в символах, с нуля)
+	Token    string // Текст ошибочного токена
+	Message  string
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Message, e.Position)
+}
+
+// queryTokenKind тип токена запроса
+type queryTokenKind int
+
+const (
+	queryTokenEOF queryTokenKind = iota
+	queryTokenTerm
+	queryTokenNot
+	queryTokenOr
+	queryTokenLParen
+	queryTokenRParen
+)
+
+// queryToken токен запроса
+type queryToken struct {
+	kind   queryTokenKind
+	pos    int
+	text   string // Исходный текст токена
+	field  string // Имя поля для field:value, пусто для свободного текста
+	value  string
+	quoted bool
+}
+
+// queryFields поля, доступные в запросе в виде field:value
+var queryFields = map[string]bool{
+	"tag": true, "title": true, "content": true, "notebook": true, "created": true, "updated": true,
+}
+
+// ParseNoteQuery разбирает структурированный запрос к заметкам, например
+// `tag:work created:>2026-01-01 -title:draft "exact phrase"`.
+// Условия через пробел объединяются по AND, OR объединяет по ИЛИ, '-' отрицает условие,
+// скобки группируют условия. Для пустого запроса возвращает nil.
+func ParseNoteQuery(text string) (domain.QueryNode, error) {
+	tokens, err := lexQuery([]rune(text))
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 1 {
+		return nil, nil
+	}
+
+	p := &queryParser{tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != queryTokenEOF {
+		return nil, tokenError(tok, "unexpected "+describeToken(tok))
+	}
+
+	return node, nil
+}
+
+// lexQuery разбивает запрос на токены; последний токен всегда queryTokenEOF
+func lexQuery(input []rune) ([]queryToken, error) {
+	var tokens []queryToken
+
+	i := 0
+	for {
+		for i < len(input) && unicode.IsSpace(input[i]) {
+			i++
+		}
+		if i == len(input) {
+			return append(tokens, queryToken{kind: queryTokenEOF, pos: i}), nil
+		}
+
+		start := i
+		switch input[i] {
+		case '(':
+			tokens = append(tokens, queryToken{kind: queryTokenLParen, pos: start, text: "("})
+			i++
+		case ')':
+			tokens = append(tokens, queryToken{kind: queryTokenRParen, pos: start, text: ")"})
+			i++
+		case '-':
+			// Минус отрицает следующее за ним условие, одиночный минус - обычное слово
+			if i+1 < len(input) && !unicode.IsSpace(input[i+1]) && input[i+1] != ')' {
+				tokens = append(tokens, queryToken{kind: queryTokenNot, pos: start, text: "-"})
+				i++
+				continue
+			}
+			i++
+			tokens = append(tokens, queryToken{kind: queryTokenTerm, pos: start, text: "-", value: "-"})
+		case '"':
+			value, end, err := lexQuoted(input, i)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			tokens = append(tokens, queryToken{
+				kind: queryTokenTerm, pos: start, text: string(input[start:i]), value: value, quoted: true,
+			})
+		default:
+			for i < len(input) && !unicode.IsSpace(input[i]) && !strings.ContainsRune(`()"`, input[i]) {
+				i++
+			}
+			word := string(input[start:i])
+
+			if word == "OR" {
+				tokens = append(tokens, queryToken{kind: queryTokenOr, pos: start, text: word})
+				continue
+			}
+
+			tok := queryToken{kind: queryTokenTerm, pos: start, text: word, value: word}
+			if field, value, found := strings.Cut(word, ":"); found && field != "" {
+				tok.field = strings.ToLower(field)
+				tok.value = value
+				if !queryFields[tok.field] {
+					return nil, tokenError(tok, fmt.Sprintf("unknown field %q (quote the word to search for it as text)", field))
+				}
+				// Значение поля может быть в кавычках: title:"my draft"
+				if value == "" && i < len(input) && input[i] == '"' {
+					quoted, end, err := lexQuoted(input, i)
+					if err != nil {
+						return nil, err
+					}
+					i = end
+					tok.text = string(input[start:i])
+					tok.value = quoted
+					tok.quoted = true
+				}
+				if tok.value == "" {
+					return nil, tokenError(tok, fmt.Sprintf("missing value for field %q", field))
+				}
+			}
+			tokens = append(tokens, tok)
+		}
+	}
+}
+
+// lexQuoted читает строку в кавычках, начинающуюся в позиции start,
+// и возвращает ее содержимое и позицию после закрывающей кавычки
+func lexQuoted(input []rune, start int) (string, int, error) {
+	for i := start + 1; i < len(input); i++ {
+		if input[i] == '"' {
+			return string(input[start+1 : i]), i + 1, nil
+		}
+	}
+	return "", 0, &QueryError{Position: start, Token: string(input[start:]), Message: "unterminated quoted string"}
+}
+
+// queryParser разбирает токены рекурсивным спуском:
+//
+//	or      = and { "OR" and }
+//	and     = unary { unary }
+//	unary   = "-" unary | primary
+//	primary = "(" or ")" | term
+type queryParser struct {
+	tokens []queryToken
+	pos    int
+}
+
+func (p *queryParser) peek() queryToken {
+	return p.tokens[p.pos]
+}
+
+func (p *queryParser) next() queryToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != queryTokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *queryParser) parseOr() (domain.QueryNode, error) {
+	node, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []domain.QueryNode{node}
+	for p.peek().kind == queryTokenOr {
+		p.next()
+		node, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return domain.QueryOr{Nodes: nodes}, nil
+}
+
+func (p *queryParser) parseAnd() (domain.QueryNode, error) {
+	var nodes []domain.QueryNode
+	for {
+		switch p.peek().kind {
+		case queryTokenEOF, queryTokenOr, queryTokenRParen:
+			if len(nodes) == 0 {
+				tok := p.peek()
+				return nil, tokenError(tok, "expected search term, got "+describeToken(tok))
+			}
+			if len(nodes) == 1 {
+				return nodes[0], nil
+			}
+			return domain.QueryAnd{Nodes: nodes}, nil
+		}
+
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+}
+
+func (p *queryParser) parseUnary() (domain.QueryNode, error) {
+	if p.peek().kind != queryTokenNot {
+		return p.parsePrimary()
+	}
+
+	p.next()
+	node, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return domain.QueryNot{Node: node}, nil
+}
+
+func (p *queryParser) parsePrimary() (domain.QueryNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case queryTokenLParen:
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.peek(); closing.kind != queryTokenRParen {
+			return nil, tokenError(tok, "missing closing parenthesis")
+		}
+		p.next()
+		return node, nil
+	case queryTokenTerm:
+		return parseTerm(tok)
+	default:
+		return nil, tokenError(tok, "expected search term, got "+describeToken(tok))
+	}
+}
+
+// parseTerm строит условие для слова, фразы или пары field:value
+func parseTerm(tok queryToken) (domain.QueryNode, error) {
+	switch tok.field {
+	case "":
+		return domain.QueryText{Field: domain.QueryFieldText, Value: tok.value}, nil
+	case "title":
+		return domain.QueryText{Field: domain.QueryFieldTitle, Value: tok.value}, nil
+	case "content":
+		return domain.QueryText{Field: domain.QueryFieldContent, Value: tok.value}, nil
+	case "tag":
+		name, err := domain.NormalizeTagName(tok.value)
+		if err != nil {
+			return nil, tokenError(tok, err.Error())
+		}
+		return domain.QueryTag{Name: name}, nil
+	case "notebook":
+		id, err := strconv.ParseInt(tok.value, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, tokenError(tok, fmt.Sprintf("invalid notebook ID %q", tok.value))
+		}
+		return domain.QueryNotebook{ID: id}, nil
+	case "created":
+		return parseTimeTerm(tok, domain.QueryFieldCreated)
+	default: // "updated"
+		return parseTimeTerm(tok, domain.QueryFieldUpdated)
+	}
+}
+
+// parseTimeTerm разбирает сравнение даты: created:>2026-01-01, updated:<=2026-03-01T12:00:00Z.
+// Дата без времени означает весь день, поэтому created:2026-01-01 отбирает заметки за этот день.
+func parseTimeTerm(tok queryToken, field string) (domain.QueryNode, error) {
+	value := tok.value
+	op := "="
+	for _, candidate := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(value, candidate) {
+			op, value = candidate, value[len(candidate):]
+			break
+		}
+	}
+
+	if instant, err := time.Parse(time.RFC3339, value); err == nil {
+		if op == "=" {
+			return nil, tokenError(tok, "exact time comparison is not supported, use a date or <, <=, >, >=")
+		}
+		return domain.QueryTime{Field: field, Op: op, Value: instant}, nil
+	}
+
+	day, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err != nil {
+		return nil, tokenError(tok, fmt.Sprintf("invalid date %q, expected YYYY-MM-DD or RFC 3339", value))
+	}
+	nextDay := day.AddDate(0, 0, 1)
+
+	switch op {
+	case ">":
+		return domain.QueryTime{Field: field, Op: domain.QueryOpGreaterEqual, Value: nextDay}, nil
+	case ">=":
+		return domain.QueryTime{Field: field, Op: domain.QueryOpGreaterEqual, Value: day}, nil
+	case "<":
+		return domain.QueryTime{Field: field, Op: domain.QueryOpLess, Value: day}, nil
+	case "<=":
+		return domain.QueryTime{Field: field, Op: domain.QueryOpLess, Value: nextDay}, nil
+	default:
+		return domain.QueryAnd{Nodes: []domain.QueryNode{
+			domain.QueryTime{Field: field, Op: domain.QueryOpGreaterEqual, Value: day},
+			domain.QueryTime{Field: field, Op: domain.QueryOpLess, Value: nextDay},
+		}}, nil
+	}
+}
+
+// tokenError создает ошибку, указывающую на токен
+func tokenError(tok queryToken, message string) *QueryError {
+	return &QueryError{Position: tok.pos, Token: tok.text, Message: message}
+}
+
+// describeToken описывает токен для сообщения об ошибке
+func describeToken(tok queryToken) string {
+	switch tok.kind {
+	case queryTokenEOF:
+		return "end of query"
+	case queryTokenOr:
+		return "OR"
+	default:
+		return fmt.Sprintf("%q", tok.text)
+	}
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"notes-api/internal/domain"
+)
+
+func TestParseNoteQuery(t *testing.T) {
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local)
+	nextDay := day.AddDate(0, 0, 1)
+	text := func(value string) domain.QueryNode {
+		return domain.QueryText{Field: domain.QueryFieldText, Value: value}
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  domain.QueryNode
+	}{
+		{name: "empty", query: "", want: nil},
+		{name: "only spaces", query: "   ", want: nil},
+		{name: "word", query: "meeting", want: text("meeting")},
+		{name: "words joined by AND", query: "a b", want: domain.QueryAnd{Nodes: []domain.QueryNode{text("a"), text("b")}}},
+		{name: "phrase", query: `"exact phrase"`, want: text("exact phrase")},
+		{name: "OR", query: "a OR b", want: domain.QueryOr{Nodes: []domain.QueryNode{text("a"), text("b")}}},
+		{name: "lower case or is a word", query: "a or b", want: domain.QueryAnd{Nodes: []domain.QueryNode{text("a"), text("or"), text("b")}}},
+		{
+			name: "AND binds tighter than OR", query: "a b OR c",
+			want: domain.QueryOr{Nodes: []domain.QueryNode{
+				domain.QueryAnd{Nodes: []domain.QueryNode{text("a"), text("b")}},
+				text("c"),
+			}},
+		},
+		{
+			name: "parentheses", query: "a (b OR c)",
+			want: domain.QueryAnd{Nodes: []domain.QueryNode{
+				text("a"),
+				domain.QueryOr{Nodes: []domain.QueryNode{text("b"), text("c")}},
+			}},
+		},
+		{name: "negation", query: "-draft", want: domain.QueryNot{Node: text("draft")}},
+		{name: "double negation", query: "--draft", want: domain.QueryNot{Node: domain.QueryNot{Node: text("draft")}}},
+		{name: "lone minus is a word", query: "a - b", want: domain.QueryAnd{Nodes: []domain.QueryNode{text("a"), text("-"), text("b")}}},
+		{
+			name: "negated group", query: "-(a OR b)",
+			want: domain.QueryNot{Node: domain.QueryOr{Nodes: []domain.QueryNode{text("a"), text("b")}}},
+		},
+		{name: "tag normalized", query: "tag:Work", want: domain.QueryTag{Name: "work"}},
+		{name: "field name case-insensitive", query: "TAG:work", want: domain.QueryTag{Name: "work"}},
+		{name: "title", query: "title:plan", want: domain.QueryText{Field: domain.QueryFieldTitle, Value: "plan"}},
+		{name: "quoted field value", query: `title:"my draft"`, want: domain.QueryText{Field: domain.QueryFieldTitle, Value: "my draft"}},
+		{name: "content", query: "content:todo", want: domain.QueryText{Field: domain.QueryFieldContent, Value: "todo"}},
+		{name: "notebook", query: "notebook:42", want: domain.QueryNotebook{ID: 42}},
+		{name: "quoted colon is text", query: `"foo:bar"`, want: text("foo:bar")},
+		{
+			name: "date means whole day", query: "created:2026-01-01",
+			want: domain.QueryAnd{Nodes: []domain.QueryNode{
+				domain.QueryTime{Field: domain.QueryFieldCreated, Op: domain.QueryOpGreaterEqual, Value: day},
+				domain.QueryTime{Field: domain.QueryFieldCreated, Op: domain.QueryOpLess, Value: nextDay},
+			}},
+		},
+		{name: "after date", query: "created:>2026-01-01", want: domain.QueryTime{Field: domain.QueryFieldCreated, Op: domain.QueryOpGreaterEqual, Value: nextDay}},
+		{name: "from date", query: "created:>=2026-01-01", want: domain.QueryTime{Field: domain.QueryFieldCreated, Op: domain.QueryOpGreaterEqual, Value: day}},
+		{name: "before date", query: "updated:<2026-01-01", want: domain.QueryTime{Field: domain.QueryFieldUpdated, Op: domain.QueryOpLess, Value: day}},
+		{name: "until date", query: "updated:<=2026-01-01", want: domain.QueryTime{Field: domain.QueryFieldUpdated, Op: domain.QueryOpLess, Value: nextDay}},
+		{
+			name: "exact time", query: "updated:>2026-03-01T12:00:00Z",
+			want: domain.QueryTime{Field: domain.QueryFieldUpdated, Op: domain.QueryOpGreater, Value: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseNoteQuery(tt.query)
+			if err != nil {
+				t.Fatalf("ParseNoteQuery(%q) error = %v", tt.query, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseNoteQuery(%q) = %#v, want %#v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNoteQueryErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		position int
+		token    string
+		message  string // Начало сообщения об ошибке
+	}{
+		{name: "unknown field", query: "foo:bar", position: 0, token: "foo:bar", message: `unknown field "foo"`},
+		{name: "missing field value", query: "a title:", position: 2, token: "title:", message: `missing value for field "title"`},
+		{name: "unterminated quote", query: `a "open`, position: 2, token: `"open`, message: "unterminated quoted string"},
+		{name: "unterminated quoted field value", query: `title:"open`, position: 6, token: `"open`, message: "unterminated quoted string"},
+		{name: "trailing OR", query: "a OR", position: 4, message: "expected search term, got end of query"},
+		{name: "leading OR", query: "OR a", position: 0, token: "OR", message: "expected search term, got OR"},
+		{name: "missing closing parenthesis", query: "(a b", position: 0, token: "(", message: "missing closing parenthesis"},
+		{name: "unexpected closing parenthesis", query: "a )", position: 2, token: ")", message: `unexpected ")"`},
+		{name: "empty group", query: "()", position: 1, token: ")", message: `expected search term, got ")"`},
+		{name: "negation without term", query: "a -)", position: 3, token: ")", message: "unexpected"},
+		{name: "invalid notebook", query: "notebook:abc", position: 0, token: "notebook:abc", message: `invalid notebook ID "abc"`},
+		{name: "invalid date", query: "created:2026-13-01", position: 0, token: "created:2026-13-01", message: `invalid date "2026-13-01"`},
+		{name: "exact time equality", query: "created:2026-01-01T00:00:00Z", position: 0, message: "exact time comparison is not supported"},
+		{name: "invalid tag", query: "tag:a/b", position: 0, token: "tag:a/b", message: "tag cannot contain"},
+		{name: "position in characters", query: "заметка foo:x", position: 8, token: "foo:x", message: "unknown field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := ParseNoteQuery(tt.query)
+			if err == nil {
+				t.Fatalf("ParseNoteQuery(%q) = %#v, want error", tt.query, node)
+			}
+			var queryErr *QueryError
+			if !errors.As(err, &queryErr) {
+				t.Fatalf("ParseNoteQuery(%q) error = %T, want *QueryError", tt.query, err)
+			}
+			if queryErr.Position != tt.position {
+				t.Errorf("position = %d, want %d", queryErr.Position, tt.position)
+			}
+			if tt.token != "" && queryErr.Token != tt.token {
+				t.Errorf("token = %q, want %q", queryErr.Token, tt.token)
+			}
+			if !strings.HasPrefix(queryErr.Message, tt.message) {
+				t.Errorf("message = %q, want prefix %q", queryErr.Message, tt.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"fmt"
+
+	"notes-api/internal/domain"
+	"notes-api/internal/repository"
+	"notes-api/pkg/utils"
+)
+
+// diffContext количество строк контекста вокруг изменений в diff
+const diffContext = 3
+
+// RevisionService реализует бизнес-логику истории изменений заметок
+type RevisionService struct {
+	repo   repository.RevisionRepository
+	notes  repository.NoteRepository
+	events EventPublisher // Получает события о восстановленных заметках
+}
+
+// NewRevisionService создает новый сервис ревизий
+func NewRevisionService(repo repository.RevisionRepository, notes repository.NoteRepository, events EventPublisher) *RevisionService {
+	return &RevisionService{repo: repo, notes: notes, events: events}
+}
+
+// GetRevisions возвращает все ревизии заметки
+func (s *RevisionService) GetRevisions(noteID int64) ([]*domain.Revision, error) {
+	return s.repo.GetRevisions(noteID)
+}
+
+// GetRevision возвращает ревизию заметки по номеру
+func (s *RevisionService) GetRevision(noteID int64, number int) (*domain.Revision, error) {
+	return s.repo.GetRevision(noteID, number)
+}
+
+// DiffRevisions возвращает unified diff между ревизией from и ревизией to.
+// Если to == nil, сравнение идет с текущей версией заметки.
+func (s *RevisionService) DiffRevisions(noteID int64, from int, to *int) (*domain.RevisionDiff, error) {
+	fromRevision, err := s.repo.GetRevision(noteID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	var toText, toName string
+	if to != nil {
+		toRevision, err := s.repo.GetRevision(noteID, *to)
+		if err != nil {
+			return nil, err
+		}
+		toText = toRevision.Text()
+		toName = fmt.Sprintf("note-%d@%d", noteID, *to)
+	} else {
+		note, err := s.notes.GetByID(noteID)
+		if err != nil {
+			return nil, err
+		}
+		toText = domain.NoteText(note.Title, note.Content)
+		toName = fmt.Sprintf("note-%d@current", noteID)
+	}
+
+	fromName := fmt.Sprintf("note-%d@%d", noteID, from)
+
+	return &domain.RevisionDiff{
+		NoteID: noteID,
+		From:   from,
+		To:     to,
+		Diff:   utils.UnifiedDiff(fromName, toName, fromRevision.Text(), toText, diffContext),
+	}, nil
+}
+
+// RestoreRevision возвращает заметку к состоянию ревизии.
+// Текущая версия при этом сохраняется как новая ревизия, поэтому восстановление можно отменить.
+// Если version не 0, заметка должна иметь эту версию.
+func (s *RevisionService) RestoreRevision(noteID int64, number int, version int64) (*domain.Note, error) {
+	revision, err := s.repo.GetRevision(noteID, number)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.notes.GetByID(noteID)
+	if err != nil {
+		return nil, err
+	}
+	previousVersion := current.Version
+
+	tags, err := domain.NormalizeTags(revision.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	note := &domain.Note{
+		Title:   revision.Title,
+		Content: revision.Content,
+		Tags:    tags,
+		Version: version,
+	}
+
+	restored, err := s.notes.Update(noteID, note)
+	if err != nil {
+		return nil, err
+	}
+	// Ревизия, совпадающая с текущим состоянием, заметку не меняет
+	if restored.Version != previousVersion {
+		s.events.Publish(domain.EventNoteUpdated, restored.ID, restored)
+	}
+	return restored, nil
+}
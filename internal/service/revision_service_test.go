@@ -0,0 +1,74 @@
+package service
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"notes-api/internal/domain"
+	"notes-api/internal/repository"
+)
+
+// recordedEvents запоминает опубликованные события
+type recordedEvents []string
+
+func (r *recordedEvents) Publish(eventType string, noteID int64, note *domain.Note) {
+	*r = append(*r, eventType)
+}
+
+func TestRestoreRevision(t *testing.T) {
+	tests := []struct {
+		name        string
+		revision    int
+		version     int64
+		wantVersion int64 // Версия заметки после восстановления
+		wantEvents  int
+		wantErr     error
+	}{
+		{name: "restore older revision", revision: 2, wantVersion: 4, wantEvents: 1},
+		{name: "expected version matches", revision: 2, version: 3, wantVersion: 4, wantEvents: 1},
+		{name: "expected version is stale", revision: 2, version: 2, wantErr: repository.ErrNoteVersionMismatch},
+		{name: "revision equal to current note", revision: 1, wantVersion: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := repository.NewJSONRepository(filepath.Join(t.TempDir(), "notes.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			note, err := repo.Create(&domain.Note{Title: "first", Content: "text"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Ревизия 1 совпадает с текущим состоянием заметки (версия 3), ревизия 2 - нет
+			for _, title := range []string{"second", "first"} {
+				if _, err := repo.Update(note.ID, &domain.Note{Title: title, Content: "text"}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			events := &recordedEvents{}
+			revisions := NewRevisionService(repo, repo, events)
+			restored, err := revisions.RestoreRevision(note.ID, tt.revision, tt.version)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("RestoreRevision() error = %v, want %v", err, tt.wantErr)
+				}
+				if len(*events) != 0 {
+					t.Errorf("published %v after failed restore", *events)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RestoreRevision() error = %v", err)
+			}
+			if restored.Version != tt.wantVersion {
+				t.Errorf("version = %d, want %d", restored.Version, tt.wantVersion)
+			}
+			if len(*events) != tt.wantEvents {
+				t.Errorf("published %v, want %d note.updated events", *events, tt.wantEvents)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"slices"
+
+	"notes-api/internal/domain"
+	"notes-api/internal/repository"
+)
+
+// TagService реализует бизнес-логику для работы с тегами
+type TagService struct {
+	repo   repository.TagRepository
+	notes  repository.NoteRepository
+	events EventPublisher // Получает события о заметках, у которых изменились теги
+}
+
+// NewTagService создает новый сервис тегов
+func NewTagService(repo repository.TagRepository, notes repository.NoteRepository, events EventPublisher) *TagService {
+	return &TagService{repo: repo, notes: notes, events: events}
+}
+
+// ListTags возвращает теги с количеством заметок
+func (s *TagService) ListTags() ([]domain.TagUsage, error) {
+	return s.repo.ListTags()
+}
+
+// RenameTag переименовывает тег
+func (s *TagService) RenameTag(oldName string, req domain.RenameTagRequest) (*domain.TagUsage, error) {
+	oldName, err := domain.NormalizeTagName(oldName)
+	if err != nil {
+		return nil, err
+	}
+	newName, err := domain.NormalizeTagName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err := s.taggedNoteIDs([]string{oldName})
+	if err != nil {
+		return nil, err
+	}
+	usage, err := s.repo.RenameTag(oldName, newName)
+	if err != nil {
+		return nil, err
+	}
+	s.publishUpdated(affected)
+	return usage, nil
+}
+
+// MergeTags объединяет несколько тегов в один. Повторы и сам целевой тег в sources не учитываются.
+func (s *TagService) MergeTags(req domain.MergeTagsRequest) (*domain.TagUsage, error) {
+	if len(req.Sources) == 0 {
+		return nil, domain.NewFieldError("sources", "sources cannot be empty")
+	}
+
+	sources, err := domain.NormalizeTags(req.Sources)
+	if err != nil {
+		return nil, err
+	}
+	target, err := domain.NormalizeTagName(req.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	// Повторы уже отброшены; целевой тег среди исходных ничего не меняет,
+	// поэтому хранилища получают только теги, которые действительно сливаются
+	names := slices.DeleteFunc(domain.TagNames(sources), func(name string) bool { return name == target })
+	if len(names) == 0 {
+		return nil, domain.NewFieldError("sources", "sources must contain a tag other than target")
+	}
+
+	affected, err := s.taggedNoteIDs(names)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := s.repo.MergeTags(names, target)
+	if err != nil {
+		return nil, err
+	}
+	s.publishUpdated(affected)
+	return usage, nil
+}
+
+// taggedNoteIDs возвращает ID заметок хотя бы с одним из тегов
+func (s *TagService) taggedNoteIDs(tags []string) ([]int64, error) {
+	ids := make([]int64, 0)
+	for note, err := range s.notes.All(domain.NoteFilter{Tags: tags}, oldestFirst) {
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, note.ID)
+	}
+	return ids, nil
+}
+
+// publishUpdated сообщает об изменении заметок после переименования или объединения тегов.
+// Ошибка чтения не отменяет уже выполненное изменение: событие просто не отправляется.
+func (s *TagService) publishUpdated(ids []int64) {
+	if len(ids) == 0 {
+		return
+	}
+	for note, err := range s.notes.All(domain.NoteFilter{IDs: ids}, oldestFirst) {
+		if err != nil {
+			return
+		}
+		s.events.Publish(domain.EventNoteUpdated, note.ID, note)
+	}
+}
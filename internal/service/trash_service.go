@@ -0,0 +1,91 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"notes-api/internal/domain"
+	"notes-api/internal/repository"
+)
+
+// trashCheckInterval - как часто проверяется корзина при автоочистке
+const trashCheckInterval = time.Hour
+
+// TrashService реализует бизнес-логику корзины удаленных заметок
+type TrashService struct {
+	repo   repository.TrashRepository
+	events EventPublisher // Получает события о восстановленных и удаленных навсегда заметках
+}
+
+// NewTrashService создает новый сервис корзины
+func NewTrashService(repo repository.TrashRepository, events EventPublisher) *TrashService {
+	return &TrashService{repo: repo, events: events}
+}
+
+// GetTrash возвращает заметки из корзины с пагинацией
+func (s *TrashService) GetTrash(limit, offset int) ([]*domain.Note, int, error) {
+	return s.repo.GetTrash(limit, offset)
+}
+
+// RestoreNote возвращает заметку из корзины. Для подписчиков заметка появляется снова, как созданная.
+func (s *TrashService) RestoreNote(id int64) (*domain.Note, error) {
+	note, err := s.repo.RestoreNote(id)
+	if err != nil {
+		return nil, err
+	}
+	s.events.Publish(domain.EventNoteCreated, note.ID, note)
+	return note, nil
+}
+
+// PurgeNote удаляет заметку из корзины навсегда
+func (s *TrashService) PurgeNote(id int64) error {
+	if err := s.repo.PurgeNote(id); err != nil {
+		return err
+	}
+	s.events.Publish(domain.EventNoteDeleted, id, nil)
+	return nil
+}
+
+// EmptyTrash удаляет навсегда заметки, пролежавшие в корзине дольше retention.
+// Возвращает число удаленных заметок.
+func (s *TrashService) EmptyTrash(retention time.Duration) (int, error) {
+	ids, err := s.repo.PurgeTrash(time.Now().Add(-retention))
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range ids {
+		s.events.Publish(domain.EventNoteDeleted, id, nil)
+	}
+	return len(ids), nil
+}
+
+// StartAutoEmpty запускает периодическую очистку корзины в фоне.
+// Возвращает функцию остановки. При retention <= 0 автоочистка отключена.
+func (s *TrashService) StartAutoEmpty(retention time.Duration) (stop func()) {
+	if retention <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(trashCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			purged, err := s.EmptyTrash(retention)
+			if err != nil {
+				log.Printf("Failed to empty trash: %v", err)
+			} else if purged > 0 {
+				log.Printf("Purged %d notes from trash", purged)
+			}
+
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
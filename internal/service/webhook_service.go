@@ -0,0 +1,594 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"notes-api/internal/domain"
+	"notes-api/internal/repository"
+)
+
+// Заголовки запроса с событием, который получает вебхук
+const (
+	WebhookEventHeader     = "X-Webhook-Event"     // Тип события
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"  // ID записи журнала доставки; одинаков у повторов
+	WebhookTimestampHeader = "X-Webhook-Timestamp" // Время отправки, секунды Unix
+	WebhookSignatureHeader = "X-Webhook-Signature" // sha256=<HMAC-SHA256 "<timestamp>.<тело>" с ключом вебхука>
+)
+
+const (
+	// WebhookDeliveryLogSize сколько последних доставок хранится в журнале вебхука
+	WebhookDeliveryLogSize = 100
+
+	// webhookConcurrency сколько запросов к получателям выполняется одновременно
+	webhookConcurrency = 16
+
+	// webhookMaxPending сколько доставок может одновременно выполняться или ждать повтора.
+	// Когда их больше, новые события ждут в журнале брокера.
+	webhookMaxPending = 1024
+
+	// webhookMinRetryDelay наименьшая пауза перед повтором: без паузы повторы ушли бы получателю подряд
+	webhookMinRetryDelay = 10 * time.Millisecond
+
+	// webhookRetryDelayLimit наибольшая пауза перед повтором, если MaxRetryDelay не задан
+	webhookRetryDelayLimit = 24 * time.Hour
+
+	// webhookResponseLimit сколько байт ответа получателя читается, чтобы соединение можно было переиспользовать
+	webhookResponseLimit = 64 << 10
+
+	// webhookResolveTimeout сколько ждать DNS при проверке адреса вебхука
+	webhookResolveTimeout = 5 * time.Second
+
+	// webhookCacheTTL сколько рассылка использует загруженный список вебхуков. Изменения через сервис
+	// сбрасывают список сразу; срок ограничивает задержку для изменений, сделанных другим экземпляром
+	// сервера с той же базой.
+	webhookCacheTTL = 30 * time.Second
+)
+
+// nonPublicPrefixes служебные диапазоны, которые netip не относит к loopback, частным и link-local адресам
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "Эта" сеть
+	netip.MustParsePrefix("100.64.0.0/10"), // Shared address space (CGNAT)
+	netip.MustParsePrefix("192.0.0.0/24"),  // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"), // Тестирование производительности
+	netip.MustParsePrefix("240.0.0.0/4"),   // Зарезервировано
+}
+
+// WebhookConfig настройки доставки событий вебхукам
+type WebhookConfig struct {
+	MaxAttempts   int           // Сколько раз пытаться доставить событие
+	RetryDelay    time.Duration // Пауза перед первым повтором; каждая следующая вдвое больше
+	MaxRetryDelay time.Duration // Наибольшая пауза между попытками; 0 - сутки
+	Timeout       time.Duration // Сколько ждать ответа получателя
+	DisableAfter  int           // После скольких неудачных доставок подряд вебхук отключается; 0 - не отключать
+	// AllowPrivate разрешает адреса loopback, частных и link-local сетей; по умолчанию вебхук
+	// может указывать только на публичный адрес, чтобы через него нельзя было обращаться к внутренним сервисам
+	AllowPrivate bool
+	Client       *http.Client // nil - клиент с Timeout, который не следует перенаправлениям и соединяется только с разрешенными адресами
+}
+
+// WebhookService управляет вебхуками и доставляет им события изменения заметок
+type WebhookService struct {
+	repo     repository.WebhookRepository
+	cfg      WebhookConfig
+	client   *http.Client
+	requests chan struct{} // Семафор одновременных запросов к получателям
+	pending  chan struct{} // Семафор доставок, которые выполняются или ждут повтора
+
+	cacheMu    sync.Mutex
+	active     []*domain.Webhook // Активные вебхуки для рассылки; nil - список нужно загрузить
+	loadedAt   time.Time
+	generation int64 // Растет при каждом сбросе списка, чтобы не сохранить список, загруженный до изменения
+}
+
+// NewWebhookService создает сервис вебхуков
+func NewWebhookService(repo repository.WebhookRepository, cfg WebhookConfig) *WebhookService {
+	cfg.MaxAttempts = max(cfg.MaxAttempts, 1)
+	cfg.RetryDelay = max(cfg.RetryDelay, webhookMinRetryDelay)
+	if cfg.MaxRetryDelay <= 0 || cfg.MaxRetryDelay > webhookRetryDelayLimit {
+		cfg.MaxRetryDelay = webhookRetryDelayLimit
+	}
+	cfg.MaxRetryDelay = max(cfg.MaxRetryDelay, cfg.RetryDelay)
+	client := cfg.Client
+	if client == nil {
+		client = newWebhookClient(cfg)
+	}
+	return &WebhookService{
+		repo:     repo,
+		cfg:      cfg,
+		client:   client,
+		requests: make(chan struct{}, webhookConcurrency),
+		pending:  make(chan struct{}, webhookMaxPending),
+	}
+}
+
+// newWebhookClient создает клиент доставки. Адрес получателя проверяется при соединении, уже после
+// разрешения имени: DNS может вернуть другой адрес, чем при регистрации вебхука. Прокси из окружения
+// не используется, иначе проверялся бы адрес прокси, а не получателя.
+func newWebhookClient(cfg WebhookConfig) *http.Client {
+	dialer := &net.Dialer{Timeout: cfg.Timeout}
+	if !cfg.AllowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			addr, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !publicAddr(addr.Addr()) {
+				return fmt.Errorf("webhook address %s is not public", addr.Addr())
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   cfg.Timeout,
+		Transport: transport,
+		// Перенаправление считается неудачной доставкой: событие не должно уйти на другой адрес
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+}
+
+// publicAddr проверяет, что адрес публичный: не loopback, не частный, не link-local
+// (в том числе 169.254.169.254 метаданных облака), не multicast и не служебный
+func publicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkAddress разрешает имя хоста вебхука и отклоняет адрес, если хотя бы один из его IP не публичный.
+// Проверка при соединении все равно нужна: запись DNS может измениться после регистрации.
+func (s *WebhookService) checkAddress(rawURL string) error {
+	if s.cfg.AllowPrivate {
+		return nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return domain.NewFieldError("url", "url must be an absolute http or https URL")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookResolveTimeout)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return domain.NewFieldError("url", fmt.Sprintf("cannot resolve host %q", u.Hostname()))
+	}
+	for _, addr := range addrs {
+		if !publicAddr(addr) {
+			return domain.NewFieldError("url", "url must point to a public address")
+		}
+	}
+	return nil
+}
+
+// CreateWebhook создает вебхук. Если ключ не задан, создается случайный.
+func (s *WebhookService) CreateWebhook(req domain.WebhookRequest) (*domain.Webhook, error) {
+	webhook := &domain.Webhook{
+		URL:    req.URL,
+		Events: normalizeEventTypes(req.Events),
+		Secret: req.Secret,
+		Active: req.Active == nil || *req.Active,
+	}
+	if err := webhook.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkAddress(webhook.URL); err != nil {
+		return nil, err
+	}
+
+	if webhook.Secret == "" {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+		webhook.Secret = secret
+	}
+
+	defer s.invalidate()
+	return s.repo.CreateWebhook(webhook)
+}
+
+// GetAllWebhooks возвращает все вебхуки
+func (s *WebhookService) GetAllWebhooks() ([]*domain.Webhook, error) {
+	return s.repo.GetAllWebhooks()
+}
+
+// GetWebhookByID возвращает вебхук по ID
+func (s *WebhookService) GetWebhookByID(id int64) (*domain.Webhook, error) {
+	return s.repo.GetWebhookByID(id)
+}
+
+// UpdateWebhook меняет вебхук. Пустой ключ и отсутствующий active оставляют прежние значения;
+// active = true включает вебхук, отключенный из-за ошибок доставки.
+func (s *WebhookService) UpdateWebhook(id int64, req domain.WebhookRequest) (*domain.Webhook, error) {
+	current, err := s.repo.GetWebhookByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook := &domain.Webhook{
+		URL:    req.URL,
+		Events: normalizeEventTypes(req.Events),
+		Secret: req.Secret,
+		Active: current.Active,
+	}
+	if webhook.Secret == "" {
+		webhook.Secret = current.Secret
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+	if err := webhook.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkAddress(webhook.URL); err != nil {
+		return nil, err
+	}
+
+	defer s.invalidate()
+	return s.repo.UpdateWebhook(id, webhook)
+}
+
+// DeleteWebhook удаляет вебхук и его журнал доставок
+func (s *WebhookService) DeleteWebhook(id int64) error {
+	defer s.invalidate()
+	return s.repo.DeleteWebhook(id)
+}
+
+// GetDeliveries возвращает страницу журнала доставок вебхука, новые первыми
+func (s *WebhookService) GetDeliveries(id int64, limit, offset int) ([]*domain.WebhookDelivery, int, error) {
+	return s.repo.GetWebhookDeliveries(id, limit, offset)
+}
+
+// normalizeEventTypes убирает повторы типов событий; пустой список означает все события
+func normalizeEventTypes(events []string) []string {
+	normalized := []string{}
+	for _, event := range events {
+		if !slices.Contains(normalized, event) {
+			normalized = append(normalized, event)
+		}
+	}
+	return normalized
+}
+
+// newWebhookSecret создает случайный ключ подписи
+func newWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// SignWebhook возвращает значение заголовка X-Webhook-Signature для тела body, отправленного в момент timestamp.
+// Получатель проверяет подпись, вычислив ее так же по заголовку X-Webhook-Timestamp и телу запроса.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// StartDelivery запускает доставку событий broker вебхукам в фоне. Каждое событие отправляется
+// отдельным запросом, поэтому порядок получения событий не гарантирован - его задает id события.
+// Возвращает функцию остановки; она прерывает запросы и ожидание повторов.
+func (s *WebhookService) StartDelivery(broker *EventBroker) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var deliveries sync.WaitGroup
+
+	// Первая подписка до возврата: события, опубликованные сразу после запуска, не теряются
+	sub := broker.Subscribe(0, nil)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		var lastID int64
+		for {
+			if sub.Gap {
+				log.Printf("Webhooks: events after #%d left the event log before delivery and are lost", lastID)
+			}
+			for _, event := range sub.Backlog {
+				s.dispatch(ctx, &deliveries, event)
+				lastID = event.ID
+			}
+
+		receive:
+			for {
+				select {
+				case event, ok := <-sub.Events:
+					if !ok {
+						break receive
+					}
+					s.dispatch(ctx, &deliveries, event)
+					lastID = event.ID
+				case <-ctx.Done():
+					sub.Unsubscribe()
+					return
+				}
+			}
+
+			// Брокер отключает подписчика, который не успевает забирать события;
+			// пропущенные события придут из журнала при повторной подписке
+			if broker.Closed() {
+				return
+			}
+			sub = broker.Subscribe(lastID, nil)
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+		deliveries.Wait()
+	}
+}
+
+// activeWebhooks возвращает активные вебхуки. Список загружается из хранилища один раз
+// и используется, пока его не сбросит изменение вебхуков или не истечет webhookCacheTTL.
+// Возвращенный список и вебхуки в нем менять нельзя.
+func (s *WebhookService) activeWebhooks() ([]*domain.Webhook, error) {
+	s.cacheMu.Lock()
+	if s.active != nil && time.Since(s.loadedAt) < webhookCacheTTL {
+		defer s.cacheMu.Unlock()
+		return s.active, nil
+	}
+	generation := s.generation
+	s.cacheMu.Unlock()
+
+	webhooks, err := s.repo.GetAllWebhooks()
+	if err != nil {
+		return nil, err
+	}
+	active := make([]*domain.Webhook, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		if webhook.Active {
+			active = append(active, webhook)
+		}
+	}
+
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	if s.generation == generation {
+		s.active = active
+		s.loadedAt = time.Now()
+	}
+	return active, nil
+}
+
+// invalidate сбрасывает список активных вебхуков после их изменения
+func (s *WebhookService) invalidate() {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	s.active = nil
+	s.generation++
+}
+
+// dispatch начинает доставку события всем активным вебхукам, подписанным на его тип.
+// Если доставок уже webhookMaxPending, ждет, пока одна из них закончится.
+func (s *WebhookService) dispatch(ctx context.Context, deliveries *sync.WaitGroup, event domain.NoteEvent) {
+	webhooks, err := s.activeWebhooks()
+	if err != nil {
+		log.Printf("Webhooks: failed to load webhooks for event #%d: %v", event.ID, err)
+		return
+	}
+
+	var body []byte
+	for _, webhook := range webhooks {
+		if !webhook.Subscribed(event.Type) {
+			continue
+		}
+		if body == nil {
+			if body, err = json.Marshal(event); err != nil {
+				log.Printf("Webhooks: failed to encode event #%d: %v", event.ID, err)
+				return
+			}
+		}
+
+		select {
+		case s.pending <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+		deliveries.Add(1)
+		go func() {
+			defer func() {
+				<-s.pending
+				deliveries.Done()
+			}()
+			s.deliver(ctx, webhook, event, body)
+		}()
+	}
+}
+
+// deliver отправляет событие вебхуку, повторяя неудачные попытки с растущей паузой,
+// и записывает результат в журнал доставок
+func (s *WebhookService) deliver(ctx context.Context, webhook *domain.Webhook, event domain.NoteEvent, body []byte) {
+	now := time.Now()
+	delivery := &domain.WebhookDelivery{
+		WebhookID: webhook.ID,
+		EventID:   event.ID,
+		EventType: event.Type,
+		NoteID:    event.NoteID,
+		Status:    domain.DeliveryPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if !s.saveDelivery(delivery) {
+		return
+	}
+
+	for attempt := 1; ; attempt++ {
+		status, err := s.send(ctx, webhook, delivery.ID, event.Type, body)
+
+		delivery.Attempts = attempt
+		delivery.ResponseStatus = status
+		delivery.NextAttemptAt = nil
+		delivery.UpdatedAt = time.Now()
+		if err == nil {
+			delivery.Status = domain.DeliverySucceeded
+			delivery.Error = ""
+			s.saveDelivery(delivery)
+			if err := s.repo.ResetWebhookFailures(webhook.ID); err != nil && !errors.Is(err, repository.ErrWebhookNotFound) {
+				log.Printf("Webhooks: failed to reset failures of webhook %d: %v", webhook.ID, err)
+			}
+			return
+		}
+		delivery.Error = err.Error()
+
+		if ctx.Err() != nil {
+			s.cancelDelivery(delivery)
+			return
+		}
+		if attempt >= s.cfg.MaxAttempts {
+			delivery.Status = domain.DeliveryFailed
+			s.saveDelivery(delivery)
+			s.recordFailure(webhook.ID)
+			return
+		}
+
+		delay := s.retryDelay(attempt)
+		next := delivery.UpdatedAt.Add(delay)
+		delivery.NextAttemptAt = &next
+		if !s.saveDelivery(delivery) {
+			return
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			delivery.NextAttemptAt = nil
+			s.cancelDelivery(delivery)
+			return
+		}
+
+		// Пока доставка ждала повтора, вебхук могли изменить, отключить или удалить
+		current, err := s.repo.GetWebhookByID(webhook.ID)
+		if err != nil {
+			if !errors.Is(err, repository.ErrWebhookNotFound) {
+				log.Printf("Webhooks: failed to load webhook %d: %v", webhook.ID, err)
+			}
+			return
+		}
+		if !current.Active {
+			delivery.Status = domain.DeliveryFailed
+			delivery.Error = "webhook is disabled"
+			delivery.NextAttemptAt = nil
+			delivery.UpdatedAt = time.Now()
+			s.saveDelivery(delivery)
+			return
+		}
+		webhook = current
+	}
+}
+
+// send выполняет одну попытку доставки. Возвращает код ответа (0, если ответа нет)
+// и ошибку, если получатель не ответил 2xx.
+func (s *WebhookService) send(ctx context.Context, webhook *domain.Webhook, deliveryID int64, eventType string, body []byte) (int, error) {
+	select {
+	case s.requests <- struct{}{}:
+		defer func() { <-s.requests }()
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "notes-api-webhooks")
+	req.Header.Set(WebhookEventHeader, eventType)
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(deliveryID, 10))
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(webhook.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, webhookResponseLimit))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("receiver responded with %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// retryDelay возвращает паузу перед повтором после неудачной попытки attempt.
+// Удвоение останавливается на MaxRetryDelay, поэтому пауза не переполняется при любом attempt.
+func (s *WebhookService) retryDelay(attempt int) time.Duration {
+	delay := s.cfg.RetryDelay
+	for i := 1; i < attempt && delay < s.cfg.MaxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, s.cfg.MaxRetryDelay)
+}
+
+// cancelDelivery записывает доставку, прерванную остановкой сервера
+func (s *WebhookService) cancelDelivery(delivery *domain.WebhookDelivery) {
+	delivery.Status = domain.DeliveryFailed
+	delivery.Error = "delivery cancelled: server is shutting down"
+	delivery.UpdatedAt = time.Now()
+	s.saveDelivery(delivery)
+}
+
+// recordFailure учитывает неудачную доставку и сообщает в лог об отключении вебхука
+func (s *WebhookService) recordFailure(id int64) {
+	webhook, err := s.repo.RecordWebhookFailure(id, s.cfg.DisableAfter)
+	if err != nil {
+		if !errors.Is(err, repository.ErrWebhookNotFound) {
+			log.Printf("Webhooks: failed to record failure of webhook %d: %v", id, err)
+		}
+		return
+	}
+	if !webhook.Active && webhook.DisabledAt != nil && webhook.FailureCount == s.cfg.DisableAfter {
+		s.invalidate()
+		log.Printf("Webhooks: webhook %d disabled after %d failed deliveries", id, webhook.FailureCount)
+	}
+}
+
+// saveDelivery сохраняет запись журнала доставки. false - вебхук удален или журнал недоступен,
+// доставку нужно прекратить.
+func (s *WebhookService) saveDelivery(delivery *domain.WebhookDelivery) bool {
+	if err := s.repo.SaveWebhookDelivery(delivery, WebhookDeliveryLogSize); err != nil {
+		if !errors.Is(err, repository.ErrWebhookNotFound) {
+			log.Printf("Webhooks: failed to save delivery of event #%d to webhook %d: %v", delivery.EventID, delivery.WebhookID, err)
+		}
+		return false
+	}
+	return true
+}
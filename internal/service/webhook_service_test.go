@@ -0,0 +1,376 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"notes-api/internal/domain"
+	"notes-api/internal/repository"
+)
+
+// webhookRequest запрос, полученный тестовым получателем
+type webhookRequest struct {
+	at     time.Time
+	header http.Header
+	body   []byte
+}
+
+// webhookReceiver тестовый получатель: первые fail запросов получают 500, остальные 204
+type webhookReceiver struct {
+	mu       sync.Mutex
+	fail     int
+	requests []webhookRequest
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	r.mu.Lock()
+	r.requests = append(r.requests, webhookRequest{at: time.Now(), header: req.Header.Clone(), body: body})
+	failed := len(r.requests) <= r.fail
+	r.mu.Unlock()
+
+	if failed {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (r *webhookReceiver) received() []webhookRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]webhookRequest(nil), r.requests...)
+}
+
+// waitFor ждет выполнения условия не дольше пары секунд
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	const (
+		secret     = "test-secret"
+		retryDelay = 20 * time.Millisecond
+	)
+
+	tests := []struct {
+		name         string
+		fail         int    // Сколько первых запросов получатель отвечает 500
+		wantAttempts int    // Сколько запросов получит получатель
+		wantStatus   string // Состояние доставки в журнале
+		wantResponse int    // Код ответа последней попытки
+		wantFailures int    // Счетчик неудачных доставок вебхука
+	}{
+		{name: "first attempt succeeds", fail: 0, wantAttempts: 1, wantStatus: domain.DeliverySucceeded, wantResponse: http.StatusNoContent},
+		{name: "retry succeeds", fail: 2, wantAttempts: 3, wantStatus: domain.DeliverySucceeded, wantResponse: http.StatusNoContent},
+		{name: "all attempts fail", fail: 100, wantAttempts: 3, wantStatus: domain.DeliveryFailed, wantResponse: http.StatusInternalServerError, wantFailures: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver := &webhookReceiver{fail: tt.fail}
+			server := httptest.NewServer(receiver)
+			defer server.Close()
+
+			repo, err := repository.NewJSONRepository(filepath.Join(t.TempDir(), "notes.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			webhooks := NewWebhookService(repo, WebhookConfig{
+				MaxAttempts:   3,
+				RetryDelay:    retryDelay,
+				MaxRetryDelay: time.Second,
+				DisableAfter:  2,
+				AllowPrivate:  true,
+				Client:        server.Client(),
+			})
+			webhook, err := webhooks.CreateWebhook(domain.WebhookRequest{URL: server.URL, Secret: secret})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			broker := NewEventBroker(10)
+			stop := webhooks.StartDelivery(broker)
+			defer stop()
+
+			broker.Publish(domain.EventNoteCreated, 7, &domain.Note{ID: 7, Title: "note"})
+
+			waitFor(t, "delivery result", func() bool {
+				deliveries, _, err := webhooks.GetDeliveries(webhook.ID, 10, 0)
+				return err == nil && len(deliveries) == 1 && deliveries[0].Status != domain.DeliveryPending
+			})
+
+			requests := receiver.received()
+			if len(requests) != tt.wantAttempts {
+				t.Fatalf("receiver got %d requests, want %d", len(requests), tt.wantAttempts)
+			}
+
+			for i, req := range requests {
+				timestamp, err := strconv.ParseInt(req.header.Get(WebhookTimestampHeader), 10, 64)
+				if err != nil {
+					t.Fatalf("request %d: invalid timestamp header: %v", i, err)
+				}
+				if got, want := req.header.Get(WebhookSignatureHeader), SignWebhook(secret, timestamp, req.body); got != want {
+					t.Errorf("request %d: signature %q, want %q", i, got, want)
+				}
+				if got := req.header.Get(WebhookEventHeader); got != domain.EventNoteCreated {
+					t.Errorf("request %d: event header %q, want %q", i, got, domain.EventNoteCreated)
+				}
+
+				var event domain.NoteEvent
+				if err := json.Unmarshal(req.body, &event); err != nil {
+					t.Fatalf("request %d: invalid body: %v", i, err)
+				}
+				if event.Type != domain.EventNoteCreated || event.NoteID != 7 {
+					t.Errorf("request %d: got event %s for note %d", i, event.Type, event.NoteID)
+				}
+
+				// Пауза перед повтором удваивается после каждой неудачи
+				if i > 0 {
+					wantDelay := retryDelay << (i - 1)
+					if gap := req.at.Sub(requests[i-1].at); gap < wantDelay {
+						t.Errorf("retry %d came after %v, want at least %v", i, gap, wantDelay)
+					}
+				}
+			}
+
+			deliveries, total, err := webhooks.GetDeliveries(webhook.ID, 10, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			delivery := deliveries[0]
+			if total != 1 || delivery.Status != tt.wantStatus || delivery.Attempts != tt.wantAttempts || delivery.ResponseStatus != tt.wantResponse {
+				t.Errorf("delivery log: total %d, status %s, attempts %d, response %d; want 1, %s, %d, %d",
+					total, delivery.Status, delivery.Attempts, delivery.ResponseStatus, tt.wantStatus, tt.wantAttempts, tt.wantResponse)
+			}
+			if delivery.EventType != domain.EventNoteCreated || delivery.NoteID != 7 || delivery.NextAttemptAt != nil {
+				t.Errorf("delivery log: event %s for note %d, next attempt %v", delivery.EventType, delivery.NoteID, delivery.NextAttemptAt)
+			}
+			if got := requests[0].header.Get(WebhookDeliveryHeader); got != strconv.FormatInt(delivery.ID, 10) {
+				t.Errorf("delivery header %q, want %d", got, delivery.ID)
+			}
+
+			waitFor(t, "failure count", func() bool {
+				current, err := webhooks.GetWebhookByID(webhook.ID)
+				return err == nil && current.FailureCount == tt.wantFailures && current.Active
+			})
+		})
+	}
+}
+
+func TestWebhookDisabledAfterFailures(t *testing.T) {
+	receiver := &webhookReceiver{fail: 100}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	repo, err := repository.NewJSONRepository(filepath.Join(t.TempDir(), "notes.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	webhooks := NewWebhookService(repo, WebhookConfig{
+		MaxAttempts:  2,
+		RetryDelay:   10 * time.Millisecond,
+		DisableAfter: 2,
+		AllowPrivate: true,
+		Client:       server.Client(),
+	})
+	webhook, err := webhooks.CreateWebhook(domain.WebhookRequest{URL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	broker := NewEventBroker(10)
+	stop := webhooks.StartDelivery(broker)
+	defer stop()
+
+	// Каждая доставка ждет результата предыдущей, чтобы счетчик неудач рос предсказуемо
+	for i := 1; i <= 2; i++ {
+		broker.Publish(domain.EventNoteUpdated, int64(i), &domain.Note{ID: int64(i)})
+		waitFor(t, "failed delivery", func() bool {
+			current, err := webhooks.GetWebhookByID(webhook.ID)
+			return err == nil && current.FailureCount == i
+		})
+	}
+
+	current, err := webhooks.GetWebhookByID(webhook.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.Active || current.DisabledAt == nil {
+		t.Fatalf("webhook after %d failed deliveries: active %v, disabled_at %v; want disabled", current.FailureCount, current.Active, current.DisabledAt)
+	}
+	if got := len(receiver.received()); got != 4 {
+		t.Errorf("receiver got %d requests, want 4", got)
+	}
+
+	// Отключенный вебхук событий не получает
+	broker.Publish(domain.EventNoteDeleted, 3, nil)
+	time.Sleep(50 * time.Millisecond)
+	if got := len(receiver.received()); got != 4 {
+		t.Errorf("disabled webhook got %d requests, want 4", got)
+	}
+	_, total, err := webhooks.GetDeliveries(webhook.ID, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 {
+		t.Errorf("delivery log has %d entries, want 2", total)
+	}
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	tests := []struct {
+		name          string
+		retryDelay    time.Duration
+		maxRetryDelay time.Duration
+		attempt       int
+		want          time.Duration
+	}{
+		{name: "first retry", retryDelay: time.Second, maxRetryDelay: time.Minute, attempt: 1, want: time.Second},
+		{name: "doubles", retryDelay: time.Second, maxRetryDelay: time.Minute, attempt: 4, want: 8 * time.Second},
+		{name: "capped", retryDelay: time.Second, maxRetryDelay: time.Minute, attempt: 10, want: time.Minute},
+		{name: "zero delay uses minimum", retryDelay: 0, maxRetryDelay: time.Minute, attempt: 1, want: webhookMinRetryDelay},
+		{name: "no limit does not overflow", retryDelay: time.Second, maxRetryDelay: 0, attempt: 1000, want: webhookRetryDelayLimit},
+		{name: "limit below delay", retryDelay: time.Minute, maxRetryDelay: time.Second, attempt: 3, want: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewWebhookService(nil, WebhookConfig{RetryDelay: tt.retryDelay, MaxRetryDelay: tt.maxRetryDelay})
+			if got := s.retryDelay(tt.attempt); got != tt.want {
+				t.Errorf("retryDelay(%d) = %v, want %v", tt.attempt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookPrivateAddresses(t *testing.T) {
+	repo, err := repository.NewJSONRepository(filepath.Join(t.TempDir(), "notes.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	webhooks := NewWebhookService(repo, WebhookConfig{Timeout: time.Second})
+
+	urls := []string{
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://[::1]/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://10.0.0.5/hook",
+		"https://192.168.1.1/hook",
+		"http://[::ffff:172.16.0.1]/hook",
+		"http://100.64.0.1/hook",
+		"http://0.0.0.0/hook",
+	}
+	for _, url := range urls {
+		_, err := webhooks.CreateWebhook(domain.WebhookRequest{URL: url})
+		var validation *domain.ValidationError
+		if !errors.As(err, &validation) {
+			t.Errorf("CreateWebhook(%q) error = %v, want validation error", url, err)
+		}
+	}
+
+	// Адрес проверяется и при соединении: запись DNS могла измениться после регистрации
+	server := httptest.NewServer(&webhookReceiver{})
+	defer server.Close()
+	resp, err := webhooks.client.Post(server.URL, "application/json", nil)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatal("request to loopback address succeeded, want error")
+	}
+	if !strings.Contains(err.Error(), "is not public") {
+		t.Errorf("request error = %v, want non-public address error", err)
+	}
+}
+
+func TestPublicAddr(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"8.8.8.8", true},
+		{"2001:4860:4860::8888", true},
+		{"127.0.0.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"224.0.0.1", false},
+		{"198.18.0.1", false},
+		{"::ffff:10.0.0.1", false},
+	}
+	for _, tt := range tests {
+		if got := publicAddr(netip.MustParseAddr(tt.addr)); got != tt.want {
+			t.Errorf("publicAddr(%s) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
+
+// countingWebhookRepository считает загрузки списка вебхуков
+type countingWebhookRepository struct {
+	repository.WebhookRepository
+	loads int
+}
+
+func (r *countingWebhookRepository) GetAllWebhooks() ([]*domain.Webhook, error) {
+	r.loads++
+	return r.WebhookRepository.GetAllWebhooks()
+}
+
+func TestWebhookActiveCache(t *testing.T) {
+	json, err := repository.NewJSONRepository(filepath.Join(t.TempDir(), "notes.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &countingWebhookRepository{WebhookRepository: json}
+	webhooks := NewWebhookService(repo, WebhookConfig{AllowPrivate: true})
+
+	webhook, err := webhooks.CreateWebhook(domain.WebhookRequest{URL: "http://127.0.0.1/hook"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 3 {
+		active, err := webhooks.activeWebhooks()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(active) != 1 {
+			t.Fatalf("got %d active webhooks, want 1", len(active))
+		}
+	}
+	if repo.loads != 1 {
+		t.Errorf("webhooks loaded %d times, want 1", repo.loads)
+	}
+
+	inactive := false
+	if _, err := webhooks.UpdateWebhook(webhook.ID, domain.WebhookRequest{URL: webhook.URL, Active: &inactive}); err != nil {
+		t.Fatal(err)
+	}
+	active, err := webhooks.activeWebhooks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(active) != 0 {
+		t.Errorf("got %d active webhooks after disabling, want 0", len(active))
+	}
+	if repo.loads != 2 {
+		t.Errorf("webhooks loaded %d times, want 2", repo.loads)
+	}
+}
@@ -0,0 +1,1642 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: notes/v1/notes.proto
+
+// gRPC API заметок. Методы повторяют NoteService; ошибки возвращаются статусами gRPC:
+// NOT_FOUND - заметка или блокнот не существует, INVALID_ARGUMENT - данные не прошли проверку
+// (детали google.rpc.BadRequest с ошибками полей), ABORTED - версия заметки не совпала,
+// FAILED_PRECONDITION - запрос противоречит состоянию данных, UNAVAILABLE - хранилище недоступно.
+
+package notesv1
+
+import (
+	status "google.golang.org/genproto/googleapis/rpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PatchFormat int32
+
+const (
+	PatchFormat_PATCH_FORMAT_UNSPECIFIED PatchFormat = 0
+	// JSON Merge Patch (RFC 7396)
+	PatchFormat_PATCH_FORMAT_MERGE_PATCH PatchFormat = 1
+	// JSON Patch (RFC 6902)
+	PatchFormat_PATCH_FORMAT_JSON_PATCH PatchFormat = 2
+)
+
+// Enum value maps for PatchFormat.
+var (
+	PatchFormat_name = map[int32]string{
+		0: "PATCH_FORMAT_UNSPECIFIED",
+		1: "PATCH_FORMAT_MERGE_PATCH",
+		2: "PATCH_FORMAT_JSON_PATCH",
+	}
+	PatchFormat_value = map[string]int32{
+		"PATCH_FORMAT_UNSPECIFIED": 0,
+		"PATCH_FORMAT_MERGE_PATCH": 1,
+		"PATCH_FORMAT_JSON_PATCH":  2,
+	}
+)
+
+func (x PatchFormat) Enum() *PatchFormat {
+	p := new(PatchFormat)
+	*p = x
+	return p
+}
+
+func (x PatchFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PatchFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_notes_v1_notes_proto_enumTypes[0].Descriptor()
+}
+
+func (PatchFormat) Type() protoreflect.EnumType {
+	return &file_notes_v1_notes_proto_enumTypes[0]
+}
+
+func (x PatchFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PatchFormat.Descriptor instead.
+func (PatchFormat) EnumDescriptor() ([]byte, []int) {
+	return file_notes_v1_notes_proto_rawDescGZIP(), []int{0}
+}
+
+type BulkOp int32
+
+const (
+	BulkOp_BULK_OP_UNSPECIFIED BulkOp = 0
+	BulkOp_BULK_OP_CREATE      BulkOp = 1
+	BulkOp_BULK_OP_UPDATE      BulkOp = 2
+	BulkOp_BULK_OP_DELETE      BulkOp = 3
+)
+
+// Enum value maps for BulkOp.
+var (
+	BulkOp_name = map[int32]string{
+		0: "BULK_OP_UNSPECIFIED",
+		1: "BULK_OP_CREATE",
+		2: "BULK_OP_UPDATE",
+		3: "BULK_OP_DELETE",
+	}
+	BulkOp_value = map[string]int32{
+		"BULK_OP_UNSPECIFIED": 0,
+		"BULK_OP_CREATE":      1,
+		"BULK_OP_UPDATE":      2,
+		"BULK_OP_DELETE":      3,
+	}
+)
+
+func (x BulkOp) Enum() *BulkOp {
+	p := new(BulkOp)
+	*p = x
+	return p
+}
+
+func (x BulkOp) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BulkOp) Descriptor() protoreflect.EnumDescriptor {
+	return file_notes_v1_notes_proto_enumTypes[1].Descriptor()
+}
+
+func (BulkOp) Type() protoreflect.EnumType {
+	return &file_notes_v1_notes_proto_enumTypes[1]
+}
+
+func (x BulkOp) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BulkOp.Descriptor instead.
+func (BulkOp) EnumDescriptor() ([]byte, []int) {
+	return file_notes_v1_notes_proto_rawDescGZIP(), []int{1}
+}
+
+type Note struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	Tags          []string               `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty"`
+	NotebookId    *int64                 `protobuf:"varint,5,opt,name=notebook_id,json=notebookId,proto3,oneof" json:"notebook_id,omitempty"`
+	Language      string                 `protobuf:"bytes,6,opt,name=language,proto3" json:"language,omitempty"`
+	Version       int64                  `protobuf:"varint,7,opt,name=version,proto3" json:"version,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Note) Reset() {
+	*x = Note{}
+	mi := &file_notes_v1_notes_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Note) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Note) ProtoMessage() {}
+
+func (x *Note) ProtoReflect() protoreflect.Message {
+	mi := &file_notes_v1_notes_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Note.ProtoReflect.Descriptor instead.
+func (*Note) Descriptor() ([]byte, []int) {
+	return file_notes_v1_notes_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Note) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Note) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Note) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *Note) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Note) GetNotebookId() int64 {
+	if x != nil && x.NotebookId != nil {
+		return *x.NotebookId
+	}
+	return 0
+}
+
+func (x *Note) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *Note) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Note) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Note) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// Список тегов; отдельное сообщение позволяет отличить "не менять теги" от "убрать все теги"
+type TagList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Names         []string               `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TagList) Reset() {
+	*x = TagList{}
+	mi := &file_notes_v1_notes_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TagList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TagList) ProtoMessage() {}
+
+func (x *TagList) ProtoReflect() protoreflect.Message {
+	mi := &file_notes_v1_notes_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TagList.ProtoReflect.Descriptor instead.
+func (*TagList) Descriptor() ([]byte, []int) {
+	return file_notes_v1_notes_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TagList) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+type NoteFilter struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Только заметки с этими ID (не больше 100)
+	Ids  []int64  `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	Tags []string `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
+	// false - заметка должна иметь все теги, true - хотя бы один
+	MatchAnyTag bool `protobuf:"varint,3,opt,name=match_any_tag,json=matchAnyTag,proto3" json:"match_any_tag,omitempty"`
+	// Структурированный запрос: tag:work AND created:>2024-01-01
+	Query         string                 `protobuf:"bytes,4,opt,name=query,proto3" json:"query,omitempty"`
+	CreatedAfter  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	CreatedBefore *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
+	UpdatedSince  *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_since,json=updatedSince,proto3" json:"updated_since,omitempty"`
+	TitlePrefix   string                 `protobuf:"bytes,8,opt,name=title_prefix,json=titlePrefix,proto3" json:"title_prefix,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NoteFilter) Reset() {
+	*x = NoteFilter{}
+	mi := &file_notes_v1_notes_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NoteFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NoteFilter) ProtoMessage() {}
+
+func (x *NoteFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_notes_v1_notes_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NoteFilter.ProtoReflect.Descriptor instead.
+func (*NoteFilter) Descriptor() ([]byte, []int) {
+	return file_notes_v1_notes_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *NoteFilter) GetIds() []int64 {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *NoteFilter) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *NoteFilter) GetMatchAnyTag() bool {
+	if x != nil {
+		return x.MatchAnyTag
+	}
+	return false
+}
+
+func (x *NoteFilter) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *NoteFilter) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+func (x *NoteFilter) GetCreatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return nil
+}
+
+func (x *NoteFilter) GetUpdatedSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedSince
+	}
+	return nil
+}
+
+func (x *NoteFilter) GetTitlePrefix() string {
+	if x != nil {
+		return x.TitlePrefix
+	}
+	return ""
+}
+
+type CreateNoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Content       string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	Tags          []string               `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty"`
+	NotebookId    *int64                 `protobuf:"varint,4,opt,name=notebook_id,json=notebookId,proto3,oneof" json:"notebook_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateNoteRequest) Reset() {
+	*x = CreateNoteRequest{}
+	mi := &file_notes_v1_notes_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateNoteRequest) ProtoMessage() {}
+
+func (x *CreateNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notes_v1_notes_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateNoteRequest.ProtoReflect.Descriptor instead.
+func (*CreateNoteRequest) Descriptor() ([]byte, []int) {
+	return file_notes_v1_notes_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateNoteRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateNoteRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *CreateNoteRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *CreateNoteRequest) GetNotebookId() int64 {
+	if x != nil && x.NotebookId != nil {
+		return *x.NotebookId
+	}
+	return 0
+}
+
+type GetNoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNoteRequest) Reset() {
+	*x = GetNoteRequest{}
+	mi := &file_notes_v1_notes_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNoteRequest) ProtoMessage() {}
+
+func (x *GetNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notes_v1_notes_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNoteRequest.ProtoReflect.Descriptor instead.
+func (*GetNoteRequest) Descriptor() ([]byte, []int) {
+	return file_notes_v1_notes_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetNoteRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ListNotesRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Filter *NoteFilter            `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// Поля сортировки через запятую, '-' - по убыванию: "-updated_at,title"
+	Sort string `protobuf:"bytes,2,opt,name=sort,proto3" json:"sort,omitempty"`
+	// Заметок в ответе: по умолчанию 10, не больше 100
+	Limit         int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32 `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNotesRequest) Reset() {
+	*x = ListNotesRequest{}
+	mi := &file_notes_v1_notes_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNotesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNotesRequest) ProtoMessage() {}
+
+func (x *ListNotesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notes_v1_notes_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNotesRequest.ProtoReflect.Descriptor instead.
+func (*ListNotesRequest) Descriptor() ([]byte, []int) {
+	return file_notes_v1_notes_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListNotesRequest) GetFilter() *NoteFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ListNotesRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListNotesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListNotesRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type ListNotesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Notes []*Note                `protobuf:"bytes,1,rep,name=notes,proto3" json:"notes,omitempty"`
+	// Сколько всего заметок подходит под фильтр
+	Total         int32 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNotesResponse) Reset() {
+	*x = ListNotesResponse{}
+	mi := &file_notes_v1_notes_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNotesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNotesResponse) ProtoMessage() {}
+
+func (x *ListNotesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_notes_v1_notes_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNotesResponse.ProtoReflect.Descriptor instead.
+func (*ListNotesResponse) Descriptor() ([]byte, []int) {
+	return file_notes_v1_notes_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListNotesResponse) GetNotes() []*Note {
+	if x != nil {
+		return x.Notes
+	}
+	return nil
+}
+
+func (x *ListNotesResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type StreamNotesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *NoteFilter            `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	Sort          string                 `protobuf:"bytes,2,opt,name=sort,proto3" json:"sort,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamNotesRequest) Reset() {
+	*x = StreamNotesRequest{}
+	mi := &file_notes_v1_notes_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamNotesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamNotesRequest) ProtoMessage() {}
+
+func (x *StreamNotesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notes_v1_notes_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamNotesRequest.ProtoReflect.Descriptor instead.
+func (*StreamNotesRequest) Descriptor() ([]byte, []int) {
+	return file_notes_v1_notes_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *StreamNotesRequest) GetFilter() *NoteFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *StreamNotesRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+type UpdateNoteRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Id      int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title   string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Content string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	// Не задано - теги не меняются
+	Tags *TagList `protobuf:"bytes,4,opt,name=tags,proto3" json:"tags,omitempty"`
+	// Ожидаемая версия заметки; 0 - без проверки
+	Version       int64 `protobuf:"varint,5,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateNoteRequest) Reset() {
+	*x = UpdateNoteRequest{}
+	mi := &file_notes_v1_notes_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateNoteRequest) ProtoMessage() {}
+
+func (x *UpdateNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notes_v1_notes_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateNoteRequest.ProtoReflect.Descriptor instead.
+func (*UpdateNoteRequest) Descriptor() ([]byte, []int) {
+	return file_notes_v1_notes_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UpdateNoteRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateNoteRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *UpdateNoteRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *UpdateNoteRequest) GetTags() *TagList {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *UpdateNoteRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type PatchNoteRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Format PatchFormat            `protobuf:"varint,2,opt,name=format,proto3,enum=notes.v1.PatchFormat" json:"format,omitempty"`
+	// Патч к документу {"title", "content", "tags"}
+	Patch         []byte `protobuf:"bytes,3,opt,name=patch,proto3" json:"patch,omitempty"`
+	Version       int64  `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PatchNoteRequest) Reset() {
+	*x = PatchNoteRequest{}
+	mi := &file_notes_v1_notes_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PatchNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatchNoteRequest) ProtoMessage() {}
+
+func (x *PatchNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notes_v1_notes_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatchNoteRequest.ProtoReflect.Descriptor instead.
+func (*PatchNoteRequest) Descriptor() ([]byte, []int) {
+	return file_notes_v1_notes_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PatchNoteRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *PatchNoteRequest) GetFormat() PatchFormat {
+	if x != nil {
+		return x.Format
+	}
+	return PatchFormat_PATCH_FORMAT_UNSPECIFIED
+}
+
+func (x *PatchNoteRequest) GetPatch() []byte {
+	if x != nil {
+		return x.Patch
+	}
+	return nil
+}
+
+func (x *PatchNoteRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type DeleteNoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Version       int64                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteNoteRequest) Reset() {
+	*x = DeleteNoteRequest{}
+	mi := &file_notes_v1_notes_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteNoteRequest) ProtoMessage() {}
+
+func (x *DeleteNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notes_v1_notes_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteNoteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteNoteRequest) Descriptor() ([]byte, []int) {
+	return file_notes_v1_notes_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeleteNoteRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DeleteNoteRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+// Операция пакетного запроса: create использует title, content, tags и notebook_id;
+// update - id, title, content, tags (не заданы - не меняются); delete - id
+type BulkOperation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Op            BulkOp                 `protobuf:"varint,1,opt,name=op,proto3,enum=notes.v1.BulkOp" json:"op,omitempty"`
+	Id            int64                  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	Version       int64                  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	Title         string                 `protobuf:"bytes,4,opt,name=title,proto3" json:"title,omitempty"`
+	Content       string                 `protobuf:"bytes,5,opt,name=content,proto3" json:"content,omitempty"`
+	Tags          *TagList               `protobuf:"bytes,6,opt,name=tags,proto3" json:"tags,omitempty"`
+	NotebookId    *int64                 `protobuf:"varint,7,opt,name=notebook_id,json=notebookId,proto3,oneof" json:"notebook_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkOperation) Reset() {
+	*x = BulkOperation{}
+	mi := &file_notes_v1_notes_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkOperation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkOperation) ProtoMessage() {}
+
+func (x *BulkOperation) ProtoReflect() protoreflect.Message {
+	mi := &file_notes_v1_notes_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkOperation.ProtoReflect.Descriptor instead.
+func (*BulkOperation) Descriptor() ([]byte, []int) {
+	return file_notes_v1_notes_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BulkOperation) GetOp() BulkOp {
+	if x != nil {
+		return x.Op
+	}
+	return BulkOp_BULK_OP_UNSPECIFIED
+}
+
+func (x *BulkOperation) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *BulkOperation) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *BulkOperation) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *BulkOperation) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *BulkOperation) GetTags() *TagList {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *BulkOperation) GetNotebookId() int64 {
+	if x != nil && x.NotebookId != nil {
+		return *x.NotebookId
+	}
+	return 0
+}
+
+type BulkNotesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// true - все операции применяются вместе или не применяется ни одна
+	Atomic        bool             `protobuf:"varint,1,opt,name=atomic,proto3" json:"atomic,omitempty"`
+	Operations    []*BulkOperation `protobuf:"bytes,2,rep,name=operations,proto3" json:"operations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkNotesRequest) Reset() {
+	*x = BulkNotesRequest{}
+	mi := &file_notes_v1_notes_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkNotesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkNotesRequest) ProtoMessage() {}
+
+func (x *BulkNotesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notes_v1_notes_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkNotesRequest.ProtoReflect.Descriptor instead.
+func (*BulkNotesRequest) Descriptor() ([]byte, []int) {
+	return file_notes_v1_notes_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *BulkNotesRequest) GetAtomic() bool {
+	if x != nil {
+		return x.Atomic
+	}
+	return false
+}
+
+func (x *BulkNotesRequest) GetOperations() []*BulkOperation {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+type BulkResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Index int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Op    BulkOp                 `protobuf:"varint,2,opt,name=op,proto3,enum=notes.v1.BulkOp" json:"op,omitempty"`
+	Id    int64                  `protobuf:"varint,3,opt,name=id,proto3" json:"id,omitempty"`
+	// Заметка после create и update
+	Note *Note `protobuf:"bytes,4,opt,name=note,proto3" json:"note,omitempty"`
+	// Не задано - операция применена
+	Error         *status.Status `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkResult) Reset() {
+	*x = BulkResult{}
+	mi := &file_notes_v1_notes_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkResult) ProtoMessage() {}
+
+func (x *BulkResult) ProtoReflect() protoreflect.Message {
+	mi := &file_notes_v1_notes_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkResult.ProtoReflect.Descriptor instead.
+func (*BulkResult) Descriptor() ([]byte, []int) {
+	return file_notes_v1_notes_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *BulkResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BulkResult) GetOp() BulkOp {
+	if x != nil {
+		return x.Op
+	}
+	return BulkOp_BULK_OP_UNSPECIFIED
+}
+
+func (x *BulkResult) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *BulkResult) GetNote() *Note {
+	if x != nil {
+		return x.Note
+	}
+	return nil
+}
+
+func (x *BulkResult) GetError() *status.Status {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type BulkNotesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Atomic        bool                   `protobuf:"varint,1,opt,name=atomic,proto3" json:"atomic,omitempty"`
+	Succeeded     int32                  `protobuf:"varint,2,opt,name=succeeded,proto3" json:"succeeded,omitempty"`
+	Failed        int32                  `protobuf:"varint,3,opt,name=failed,proto3" json:"failed,omitempty"`
+	Results       []*BulkResult          `protobuf:"bytes,4,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkNotesResponse) Reset() {
+	*x = BulkNotesResponse{}
+	mi := &file_notes_v1_notes_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkNotesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkNotesResponse) ProtoMessage() {}
+
+func (x *BulkNotesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_notes_v1_notes_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkNotesResponse.ProtoReflect.Descriptor instead.
+func (*BulkNotesResponse) Descriptor() ([]byte, []int) {
+	return file_notes_v1_notes_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *BulkNotesResponse) GetAtomic() bool {
+	if x != nil {
+		return x.Atomic
+	}
+	return false
+}
+
+func (x *BulkNotesResponse) GetSucceeded() int32 {
+	if x != nil {
+		return x.Succeeded
+	}
+	return 0
+}
+
+func (x *BulkNotesResponse) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *BulkNotesResponse) GetResults() []*BulkResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type MoveNoteRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Не задано - заметка убирается из блокнотов
+	NotebookId    *int64 `protobuf:"varint,2,opt,name=notebook_id,json=notebookId,proto3,oneof" json:"notebook_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveNoteRequest) Reset() {
+	*x = MoveNoteRequest{}
+	mi := &file_notes_v1_notes_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveNoteRequest) ProtoMessage() {}
+
+func (x *MoveNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notes_v1_notes_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveNoteRequest.ProtoReflect.Descriptor instead.
+func (*MoveNoteRequest) Descriptor() ([]byte, []int) {
+	return file_notes_v1_notes_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *MoveNoteRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *MoveNoteRequest) GetNotebookId() int64 {
+	if x != nil && x.NotebookId != nil {
+		return *x.NotebookId
+	}
+	return 0
+}
+
+type SearchNotesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchNotesRequest) Reset() {
+	*x = SearchNotesRequest{}
+	mi := &file_notes_v1_notes_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchNotesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchNotesRequest) ProtoMessage() {}
+
+func (x *SearchNotesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notes_v1_notes_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchNotesRequest.ProtoReflect.Descriptor instead.
+func (*SearchNotesRequest) Descriptor() ([]byte, []int) {
+	return file_notes_v1_notes_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SearchNotesRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchNotesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchNotesRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type SearchResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Note  *Note                  `protobuf:"bytes,1,opt,name=note,proto3" json:"note,omitempty"`
+	Rank  float64                `protobuf:"fixed64,2,opt,name=rank,proto3" json:"rank,omitempty"`
+	// Совпадения обрамлены <mark></mark>
+	Snippet       string `protobuf:"bytes,3,opt,name=snippet,proto3" json:"snippet,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchResult) Reset() {
+	*x = SearchResult{}
+	mi := &file_notes_v1_notes_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResult) ProtoMessage() {}
+
+func (x *SearchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_notes_v1_notes_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResult.ProtoReflect.Descriptor instead.
+func (*SearchResult) Descriptor() ([]byte, []int) {
+	return file_notes_v1_notes_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SearchResult) GetNote() *Note {
+	if x != nil {
+		return x.Note
+	}
+	return nil
+}
+
+func (x *SearchResult) GetRank() float64 {
+	if x != nil {
+		return x.Rank
+	}
+	return 0
+}
+
+func (x *SearchResult) GetSnippet() string {
+	if x != nil {
+		return x.Snippet
+	}
+	return ""
+}
+
+type SearchNotesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*SearchResult        `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchNotesResponse) Reset() {
+	*x = SearchNotesResponse{}
+	mi := &file_notes_v1_notes_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchNotesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchNotesResponse) ProtoMessage() {}
+
+func (x *SearchNotesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_notes_v1_notes_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchNotesResponse.ProtoReflect.Descriptor instead.
+func (*SearchNotesResponse) Descriptor() ([]byte, []int) {
+	return file_notes_v1_notes_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SearchNotesResponse) GetResults() []*SearchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *SearchNotesResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+var File_notes_v1_notes_proto protoreflect.FileDescriptor
+
+const file_notes_v1_notes_proto_rawDesc = "" +
+	"\n" +
+	"\x14notes/v1/notes.proto\x12\bnotes.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x17google/rpc/status.proto\"\xbc\x02\n" +
+	"\x04Note\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x12\x12\n" +
+	"\x04tags\x18\x04 \x03(\tR\x04tags\x12$\n" +
+	"\vnotebook_id\x18\x05 \x01(\x03H\x00R\n" +
+	"notebookId\x88\x01\x01\x12\x1a\n" +
+	"\blanguage\x18\x06 \x01(\tR\blanguage\x12\x18\n" +
+	"\aversion\x18\a \x01(\x03R\aversion\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAtB\x0e\n" +
+	"\f_notebook_id\"\x1f\n" +
+	"\aTagList\x12\x14\n" +
+	"\x05names\x18\x01 \x03(\tR\x05names\"\xd4\x02\n" +
+	"\n" +
+	"NoteFilter\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\x03R\x03ids\x12\x12\n" +
+	"\x04tags\x18\x02 \x03(\tR\x04tags\x12\"\n" +
+	"\rmatch_any_tag\x18\x03 \x01(\bR\vmatchAnyTag\x12\x14\n" +
+	"\x05query\x18\x04 \x01(\tR\x05query\x12?\n" +
+	"\rcreated_after\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfter\x12A\n" +
+	"\x0ecreated_before\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\rcreatedBefore\x12?\n" +
+	"\rupdated_since\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\fupdatedSince\x12!\n" +
+	"\ftitle_prefix\x18\b \x01(\tR\vtitlePrefix\"\x8d\x01\n" +
+	"\x11CreateNoteRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\x12\x12\n" +
+	"\x04tags\x18\x03 \x03(\tR\x04tags\x12$\n" +
+	"\vnotebook_id\x18\x04 \x01(\x03H\x00R\n" +
+	"notebookId\x88\x01\x01B\x0e\n" +
+	"\f_notebook_id\" \n" +
+	"\x0eGetNoteRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x82\x01\n" +
+	"\x10ListNotesRequest\x12,\n" +
+	"\x06filter\x18\x01 \x01(\v2\x14.notes.v1.NoteFilterR\x06filter\x12\x12\n" +
+	"\x04sort\x18\x02 \x01(\tR\x04sort\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x04 \x01(\x05R\x06offset\"O\n" +
+	"\x11ListNotesResponse\x12$\n" +
+	"\x05notes\x18\x01 \x03(\v2\x0e.notes.v1.NoteR\x05notes\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"V\n" +
+	"\x12StreamNotesRequest\x12,\n" +
+	"\x06filter\x18\x01 \x01(\v2\x14.notes.v1.NoteFilterR\x06filter\x12\x12\n" +
+	"\x04sort\x18\x02 \x01(\tR\x04sort\"\x94\x01\n" +
+	"\x11UpdateNoteRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x12%\n" +
+	"\x04tags\x18\x04 \x01(\v2\x11.notes.v1.TagListR\x04tags\x12\x18\n" +
+	"\aversion\x18\x05 \x01(\x03R\aversion\"\x81\x01\n" +
+	"\x10PatchNoteRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12-\n" +
+	"\x06format\x18\x02 \x01(\x0e2\x15.notes.v1.PatchFormatR\x06format\x12\x14\n" +
+	"\x05patch\x18\x03 \x01(\fR\x05patch\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\x03R\aversion\"=\n" +
+	"\x11DeleteNoteRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x03R\aversion\"\xe8\x01\n" +
+	"\rBulkOperation\x12 \n" +
+	"\x02op\x18\x01 \x01(\x0e2\x10.notes.v1.BulkOpR\x02op\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x03R\x02id\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x03R\aversion\x12\x14\n" +
+	"\x05title\x18\x04 \x01(\tR\x05title\x12\x18\n" +
+	"\acontent\x18\x05 \x01(\tR\acontent\x12%\n" +
+	"\x04tags\x18\x06 \x01(\v2\x11.notes.v1.TagListR\x04tags\x12$\n" +
+	"\vnotebook_id\x18\a \x01(\x03H\x00R\n" +
+	"notebookId\x88\x01\x01B\x0e\n" +
+	"\f_notebook_id\"c\n" +
+	"\x10BulkNotesRequest\x12\x16\n" +
+	"\x06atomic\x18\x01 \x01(\bR\x06atomic\x127\n" +
+	"\n" +
+	"operations\x18\x02 \x03(\v2\x17.notes.v1.BulkOperationR\n" +
+	"operations\"\xa2\x01\n" +
+	"\n" +
+	"BulkResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12 \n" +
+	"\x02op\x18\x02 \x01(\x0e2\x10.notes.v1.BulkOpR\x02op\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\x03R\x02id\x12\"\n" +
+	"\x04note\x18\x04 \x01(\v2\x0e.notes.v1.NoteR\x04note\x12(\n" +
+	"\x05error\x18\x05 \x01(\v2\x12.google.rpc.StatusR\x05error\"\x91\x01\n" +
+	"\x11BulkNotesResponse\x12\x16\n" +
+	"\x06atomic\x18\x01 \x01(\bR\x06atomic\x12\x1c\n" +
+	"\tsucceeded\x18\x02 \x01(\x05R\tsucceeded\x12\x16\n" +
+	"\x06failed\x18\x03 \x01(\x05R\x06failed\x12.\n" +
+	"\aresults\x18\x04 \x03(\v2\x14.notes.v1.BulkResultR\aresults\"W\n" +
+	"\x0fMoveNoteRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12$\n" +
+	"\vnotebook_id\x18\x02 \x01(\x03H\x00R\n" +
+	"notebookId\x88\x01\x01B\x0e\n" +
+	"\f_notebook_id\"X\n" +
+	"\x12SearchNotesRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\"`\n" +
+	"\fSearchResult\x12\"\n" +
+	"\x04note\x18\x01 \x01(\v2\x0e.notes.v1.NoteR\x04note\x12\x12\n" +
+	"\x04rank\x18\x02 \x01(\x01R\x04rank\x12\x18\n" +
+	"\asnippet\x18\x03 \x01(\tR\asnippet\"]\n" +
+	"\x13SearchNotesResponse\x120\n" +
+	"\aresults\x18\x01 \x03(\v2\x16.notes.v1.SearchResultR\aresults\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total*f\n" +
+	"\vPatchFormat\x12\x1c\n" +
+	"\x18PATCH_FORMAT_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18PATCH_FORMAT_MERGE_PATCH\x10\x01\x12\x1b\n" +
+	"\x17PATCH_FORMAT_JSON_PATCH\x10\x02*]\n" +
+	"\x06BulkOp\x12\x17\n" +
+	"\x13BULK_OP_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0eBULK_OP_CREATE\x10\x01\x12\x12\n" +
+	"\x0eBULK_OP_UPDATE\x10\x02\x12\x12\n" +
+	"\x0eBULK_OP_DELETE\x10\x032\x82\x05\n" +
+	"\vNoteService\x129\n" +
+	"\n" +
+	"CreateNote\x12\x1b.notes.v1.CreateNoteRequest\x1a\x0e.notes.v1.Note\x123\n" +
+	"\aGetNote\x12\x18.notes.v1.GetNoteRequest\x1a\x0e.notes.v1.Note\x12D\n" +
+	"\tListNotes\x12\x1a.notes.v1.ListNotesRequest\x1a\x1b.notes.v1.ListNotesResponse\x12=\n" +
+	"\vStreamNotes\x12\x1c.notes.v1.StreamNotesRequest\x1a\x0e.notes.v1.Note0\x01\x129\n" +
+	"\n" +
+	"UpdateNote\x12\x1b.notes.v1.UpdateNoteRequest\x1a\x0e.notes.v1.Note\x127\n" +
+	"\tPatchNote\x12\x1a.notes.v1.PatchNoteRequest\x1a\x0e.notes.v1.Note\x12A\n" +
+	"\n" +
+	"DeleteNote\x12\x1b.notes.v1.DeleteNoteRequest\x1a\x16.google.protobuf.Empty\x12D\n" +
+	"\tBulkNotes\x12\x1a.notes.v1.BulkNotesRequest\x1a\x1b.notes.v1.BulkNotesResponse\x125\n" +
+	"\bMoveNote\x12\x19.notes.v1.MoveNoteRequest\x1a\x0e.notes.v1.Note\x12J\n" +
+	"\vSearchNotes\x12\x1c.notes.v1.SearchNotesRequest\x1a\x1d.notes.v1.SearchNotesResponseB$Z\"notes-api/pkg/api/notes/v1;notesv1b\x06proto3"
+
+var (
+	file_notes_v1_notes_proto_rawDescOnce sync.Once
+	file_notes_v1_notes_proto_rawDescData []byte
+)
+
+func file_notes_v1_notes_proto_rawDescGZIP() []byte {
+	file_notes_v1_notes_proto_rawDescOnce.Do(func() {
+		file_notes_v1_notes_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_notes_v1_notes_proto_rawDesc), len(file_notes_v1_notes_proto_rawDesc)))
+	})
+	return file_notes_v1_notes_proto_rawDescData
+}
+
+var file_notes_v1_notes_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_notes_v1_notes_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_notes_v1_notes_proto_goTypes = []any{
+	(PatchFormat)(0),              // 0: notes.v1.PatchFormat
+	(BulkOp)(0),                   // 1: notes.v1.BulkOp
+	(*Note)(nil),                  // 2: notes.v1.Note
+	(*TagList)(nil),               // 3: notes.v1.TagList
+	(*NoteFilter)(nil),            // 4: notes.v1.NoteFilter
+	(*CreateNoteRequest)(nil),     // 5: notes.v1.CreateNoteRequest
+	(*GetNoteRequest)(nil),        // 6: notes.v1.GetNoteRequest
+	(*ListNotesRequest)(nil),      // 7: notes.v1.ListNotesRequest
+	(*ListNotesResponse)(nil),     // 8: notes.v1.ListNotesResponse
+	(*StreamNotesRequest)(nil),    // 9: notes.v1.StreamNotesRequest
+	(*UpdateNoteRequest)(nil),     // 10: notes.v1.UpdateNoteRequest
+	(*PatchNoteRequest)(nil),      // 11: notes.v1.PatchNoteRequest
+	(*DeleteNoteRequest)(nil),     // 12: notes.v1.DeleteNoteRequest
+	(*BulkOperation)(nil),         // 13: notes.v1.BulkOperation
+	(*BulkNotesRequest)(nil),      // 14: notes.v1.BulkNotesRequest
+	(*BulkResult)(nil),            // 15: notes.v1.BulkResult
+	(*BulkNotesResponse)(nil),     // 16: notes.v1.BulkNotesResponse
+	(*MoveNoteRequest)(nil),       // 17: notes.v1.MoveNoteRequest
+	(*SearchNotesRequest)(nil),    // 18: notes.v1.SearchNotesRequest
+	(*SearchResult)(nil),          // 19: notes.v1.SearchResult
+	(*SearchNotesResponse)(nil),   // 20: notes.v1.SearchNotesResponse
+	(*timestamppb.Timestamp)(nil), // 21: google.protobuf.Timestamp
+	(*status.Status)(nil),         // 22: google.rpc.Status
+	(*emptypb.Empty)(nil),         // 23: google.protobuf.Empty
+}
+var file_notes_v1_notes_proto_depIdxs = []int32{
+	21, // 0: notes.v1.Note.created_at:type_name -> google.protobuf.Timestamp
+	21, // 1: notes.v1.Note.updated_at:type_name -> google.protobuf.Timestamp
+	21, // 2: notes.v1.NoteFilter.created_after:type_name -> google.protobuf.Timestamp
+	21, // 3: notes.v1.NoteFilter.created_before:type_name -> google.protobuf.Timestamp
+	21, // 4: notes.v1.NoteFilter.updated_since:type_name -> google.protobuf.Timestamp
+	4,  // 5: notes.v1.ListNotesRequest.filter:type_name -> notes.v1.NoteFilter
+	2,  // 6: notes.v1.ListNotesResponse.notes:type_name -> notes.v1.Note
+	4,  // 7: notes.v1.StreamNotesRequest.filter:type_name -> notes.v1.NoteFilter
+	3,  // 8: notes.v1.UpdateNoteRequest.tags:type_name -> notes.v1.TagList
+	0,  // 9: notes.v1.PatchNoteRequest.format:type_name -> notes.v1.PatchFormat
+	1,  // 10: notes.v1.BulkOperation.op:type_name -> notes.v1.BulkOp
+	3,  // 11: notes.v1.BulkOperation.tags:type_name -> notes.v1.TagList
+	13, // 12: notes.v1.BulkNotesRequest.operations:type_name -> notes.v1.BulkOperation
+	1,  // 13: notes.v1.BulkResult.op:type_name -> notes.v1.BulkOp
+	2,  // 14: notes.v1.BulkResult.note:type_name -> notes.v1.Note
+	22, // 15: notes.v1.BulkResult.error:type_name -> google.rpc.Status
+	15, // 16: notes.v1.BulkNotesResponse.results:type_name -> notes.v1.BulkResult
+	2,  // 17: notes.v1.SearchResult.note:type_name -> notes.v1.Note
+	19, // 18: notes.v1.SearchNotesResponse.results:type_name -> notes.v1.SearchResult
+	5,  // 19: notes.v1.NoteService.CreateNote:input_type -> notes.v1.CreateNoteRequest
+	6,  // 20: notes.v1.NoteService.GetNote:input_type -> notes.v1.GetNoteRequest
+	7,  // 21: notes.v1.NoteService.ListNotes:input_type -> notes.v1.ListNotesRequest
+	9,  // 22: notes.v1.NoteService.StreamNotes:input_type -> notes.v1.StreamNotesRequest
+	10, // 23: notes.v1.NoteService.UpdateNote:input_type -> notes.v1.UpdateNoteRequest
+	11, // 24: notes.v1.NoteService.PatchNote:input_type -> notes.v1.PatchNoteRequest
+	12, // 25: notes.v1.NoteService.DeleteNote:input_type -> notes.v1.DeleteNoteRequest
+	14, // 26: notes.v1.NoteService.BulkNotes:input_type -> notes.v1.BulkNotesRequest
+	17, // 27: notes.v1.NoteService.MoveNote:input_type -> notes.v1.MoveNoteRequest
+	18, // 28: notes.v1.NoteService.SearchNotes:input_type -> notes.v1.SearchNotesRequest
+	2,  // 29: notes.v1.NoteService.CreateNote:output_type -> notes.v1.Note
+	2,  // 30: notes.v1.NoteService.GetNote:output_type -> notes.v1.Note
+	8,  // 31: notes.v1.NoteService.ListNotes:output_type -> notes.v1.ListNotesResponse
+	2,  // 32: notes.v1.NoteService.StreamNotes:output_type -> notes.v1.Note
+	2,  // 33: notes.v1.NoteService.UpdateNote:output_type -> notes.v1.Note
+	2,  // 34: notes.v1.NoteService.PatchNote:output_type -> notes.v1.Note
+	23, // 35: notes.v1.NoteService.DeleteNote:output_type -> google.protobuf.Empty
+	16, // 36: notes.v1.NoteService.BulkNotes:output_type -> notes.v1.BulkNotesResponse
+	2,  // 37: notes.v1.NoteService.MoveNote:output_type -> notes.v1.Note
+	20, // 38: notes.v1.NoteService.SearchNotes:output_type -> notes.v1.SearchNotesResponse
+	29, // [29:39] is the sub-list for method output_type
+	19, // [19:29] is the sub-list for method input_type
+	19, // [19:19] is the sub-list for extension type_name
+	19, // [19:19] is the sub-list for extension extendee
+	0,  // [0:19] is the sub-list for field type_name
+}
+
+func init() { file_notes_v1_notes_proto_init() }
+func file_notes_v1_notes_proto_init() {
+	if File_notes_v1_notes_proto != nil {
+		return
+	}
+	file_notes_v1_notes_proto_msgTypes[0].OneofWrappers = []any{}
+	file_notes_v1_notes_proto_msgTypes[3].OneofWrappers = []any{}
+	file_notes_v1_notes_proto_msgTypes[11].OneofWrappers = []any{}
+	file_notes_v1_notes_proto_msgTypes[15].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_notes_v1_notes_proto_rawDesc), len(file_notes_v1_notes_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   19,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_notes_v1_notes_proto_goTypes,
+		DependencyIndexes: file_notes_v1_notes_proto_depIdxs,
+		EnumInfos:         file_notes_v1_notes_proto_enumTypes,
+		MessageInfos:      file_notes_v1_notes_proto_msgTypes,
+	}.Build()
+	File_notes_v1_notes_proto = out.File
+	file_notes_v1_notes_proto_goTypes = nil
+	file_notes_v1_notes_proto_depIdxs = nil
+}
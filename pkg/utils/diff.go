@@ -0,0 +1,259 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffOpKind тип операции редактирования
+type diffOpKind int
+
+const (
+	diffEqual diffOpKind = iota
+	diffDelete
+	diffInsert
+)
+
+// diffOp одна строка в последовательности редактирования
+type diffOp struct {
+	kind diffOpKind
+	line string
+}
+
+// UnifiedDiff возвращает разницу между текстами a и b в формате unified diff
+// с context строками контекста вокруг изменений. Для одинаковых текстов возвращает пустую строку.
+func UnifiedDiff(fromName, toName, a, b string, context int) string {
+	ops := diffLines(splitLines(a), splitLines(b))
+
+	hunks := formatHunks(ops, context)
+	if hunks == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("--- %s\n+++ %s\n%s", fromName, toName, hunks)
+}
+
+// splitLines разбивает текст на строки; пустой текст не содержит строк
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// maxDiffCost ограничивает число шагов поиска по диагоналям за один diff.
+// Для огромных полностью переписанных текстов точный поиск занимает O((N+M)·D) времени,
+// поэтому после исчерпания бюджета оставшиеся участки заменяются целиком.
+const maxDiffCost = 1 << 24
+
+// differ хранит общие для всех шагов рекурсии буферы
+type differ struct {
+	a, b   []string
+	vf, vb []int // Самые дальние точки прямого и обратного поиска на каждой диагонали
+	offset int
+	cost   int // Оставшийся бюджет шагов
+	ops    []diffOp
+}
+
+// diffLines строит кратчайшую последовательность редактирования алгоритмом Майерса
+// в линейной памяти: задача делится пополам по средней змейке и решается рекурсивно
+func diffLines(a, b []string) []diffOp {
+	size := len(a) + len(b)
+	d := &differ{
+		a:      a,
+		b:      b,
+		vf:     make([]int, 2*size+3),
+		vb:     make([]int, 2*size+3),
+		offset: size + 1,
+		cost:   maxDiffCost,
+	}
+	d.compare(0, len(a), 0, len(b))
+	return d.ops
+}
+
+// compare добавляет операции, превращающие a[aLo:aHi] в b[bLo:bHi]
+func (d *differ) compare(aLo, aHi, bLo, bHi int) {
+	// Общее начало и конец не требуют поиска
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		d.emit(diffEqual, d.a[aLo])
+		aLo++
+		bLo++
+	}
+	suffix := 0
+	for aLo < aHi-suffix && bLo < bHi-suffix && d.a[aHi-suffix-1] == d.b[bHi-suffix-1] {
+		suffix++
+	}
+	aHi -= suffix
+	bHi -= suffix
+
+	switch {
+	case aLo == aHi:
+		d.emitRange(diffInsert, d.b[bLo:bHi])
+	case bLo == bHi:
+		d.emitRange(diffDelete, d.a[aLo:aHi])
+	default:
+		x, y, u, v, ok := d.middleSnake(aLo, aHi, bLo, bHi)
+		if !ok {
+			// Бюджет исчерпан: результат остается корректным, но уже не кратчайшим
+			d.emitRange(diffDelete, d.a[aLo:aHi])
+			d.emitRange(diffInsert, d.b[bLo:bHi])
+			break
+		}
+		d.compare(aLo, x, bLo, y)
+		d.emitRange(diffEqual, d.a[x:u])
+		d.compare(u, aHi, v, bHi)
+	}
+
+	d.emitRange(diffEqual, d.a[aHi:aHi+suffix])
+}
+
+// middleSnake находит змейку (x, y)-(u, v) в середине кратчайшего пути,
+// одновременно идя от начала и от конца участка. Возвращает false, если кончился бюджет.
+func (d *differ) middleSnake(aLo, aHi, bLo, bHi int) (x, y, u, v int, ok bool) {
+	a, b := d.a[aLo:aHi], d.b[bLo:bHi]
+	n, m := len(a), len(b)
+	delta := n - m
+	odd := delta%2 != 0
+	vf, vb, off := d.vf, d.vb, d.offset
+
+	// Обратный поиск хранит расстояние от конца участка, его диагональ k соответствует прямой delta-k
+	vf[off+1], vb[off+1] = 0, 0
+	for D := 0; D <= (n+m+1)/2; D++ {
+		for k := -D; k <= D; k += 2 {
+			var x int
+			if k == -D || (k != D && vf[off+k-1] < vf[off+k+1]) {
+				x = vf[off+k+1] // Вставка
+			} else {
+				x = vf[off+k-1] + 1 // Удаление
+			}
+			y := x - k
+			x0, y0 := x, y
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			vf[off+k] = x
+			d.cost -= 1 + x - x0
+
+			if odd && delta-k >= -(D-1) && delta-k <= D-1 && x+vb[off+delta-k] >= n {
+				return aLo + x0, bLo + y0, aLo + x, bLo + y, true
+			}
+		}
+
+		for k := -D; k <= D; k += 2 {
+			var x int
+			if k == -D || (k != D && vb[off+k-1] < vb[off+k+1]) {
+				x = vb[off+k+1]
+			} else {
+				x = vb[off+k-1] + 1
+			}
+			y := x - k
+			x0, y0 := x, y
+			for x < n && y < m && a[n-x-1] == b[m-y-1] {
+				x++
+				y++
+			}
+			vb[off+k] = x
+			d.cost -= 1 + x - x0
+
+			if !odd && delta-k >= -D && delta-k <= D && x+vf[off+delta-k] >= n {
+				return aHi - x, bHi - y, aHi - x0, bHi - y0, true
+			}
+		}
+
+		if d.cost <= 0 {
+			return 0, 0, 0, 0, false
+		}
+	}
+
+	// Сюда не попадаем: пути встречаются не позже чем через (n+m+1)/2 шагов
+	return 0, 0, 0, 0, false
+}
+
+// emit добавляет одну операцию
+func (d *differ) emit(kind diffOpKind, line string) {
+	d.ops = append(d.ops, diffOp{kind: kind, line: line})
+}
+
+// emitRange добавляет операцию одного типа для каждой строки
+func (d *differ) emitRange(kind diffOpKind, lines []string) {
+	for _, line := range lines {
+		d.emit(kind, line)
+	}
+}
+
+// formatHunks группирует изменения в блоки с контекстом
+func formatHunks(ops []diffOp, context int) string {
+	// Номера строк в a и b перед каждой операцией
+	aLine := make([]int, len(ops)+1)
+	bLine := make([]int, len(ops)+1)
+	for i, op := range ops {
+		aLine[i+1], bLine[i+1] = aLine[i], bLine[i]
+		if op.kind != diffInsert {
+			aLine[i+1]++
+		}
+		if op.kind != diffDelete {
+			bLine[i+1]++
+		}
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(ops); {
+		if ops[i].kind == diffEqual {
+			i++
+			continue
+		}
+
+		start := max(0, i-context)
+
+		// Расширяем блок, пока промежутки между изменениями короче двойного контекста
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != diffEqual {
+				end++
+				continue
+			}
+			j := end
+			for j < len(ops) && ops[j].kind == diffEqual {
+				j++
+			}
+			if j == len(ops) || j-end > 2*context {
+				end = min(end+context, len(ops))
+				break
+			}
+			end = j
+		}
+
+		aCount := aLine[end] - aLine[start]
+		bCount := bLine[end] - bLine[start]
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(aLine[start], aCount), hunkRange(bLine[start], bCount))
+
+		for _, op := range ops[start:end] {
+			switch op.kind {
+			case diffEqual:
+				sb.WriteString(" ")
+			case diffDelete:
+				sb.WriteString("-")
+			case diffInsert:
+				sb.WriteString("+")
+			}
+			sb.WriteString(op.line)
+			sb.WriteString("\n")
+		}
+
+		i = end
+	}
+
+	return sb.String()
+}
+
+// hunkRange форматирует диапазон строк заголовка блока
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
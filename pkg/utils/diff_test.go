@@ -0,0 +1,183 @@
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		context int
+		want    string
+	}{
+		{name: "identical texts", a: "a\nb", b: "a\nb", context: 3, want: ""},
+		{name: "both empty", a: "", b: "", context: 3, want: ""},
+		{
+			name: "from empty", a: "", b: "a\nb", context: 3,
+			want: "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name: "to empty", a: "a\nb", b: "", context: 3,
+			want: "--- old\n+++ new\n@@ -1,2 +0,0 @@\n-a\n-b\n",
+		},
+		{
+			name: "changed line with context", a: "1\n2\n3\n4\n5", b: "1\n2\nX\n4\n5", context: 1,
+			want: "--- old\n+++ new\n@@ -2,3 +2,3 @@\n 2\n-3\n+X\n 4\n",
+		},
+		{
+			name: "removed line", a: "a\nb\nc", b: "a\nc", context: 3,
+			want: "--- old\n+++ new\n@@ -1,3 +1,2 @@\n a\n-b\n c\n",
+		},
+		{
+			name: "appended line without context", a: "a\nb", b: "a\nb\nc", context: 0,
+			want: "--- old\n+++ new\n@@ -2,0 +3 @@\n+c\n",
+		},
+		{
+			name: "distant changes in separate hunks", a: "1\n2\n3\n4\n5", b: "X\n2\n3\n4\nY", context: 1,
+			want: "--- old\n+++ new\n@@ -1,2 +1,2 @@\n-1\n+X\n 2\n@@ -4,2 +4,2 @@\n 4\n-5\n+Y\n",
+		},
+		{
+			name: "close changes in one hunk", a: "1\n2\n3\n4\n5", b: "X\n2\n3\nY\n5", context: 1,
+			want: "--- old\n+++ new\n@@ -1,5 +1,5 @@\n-1\n+X\n 2\n 3\n-4\n+Y\n 5\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UnifiedDiff("old", "new", tt.a, tt.b, tt.context); got != tt.want {
+				t.Errorf("UnifiedDiff() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffLinesIsShortest(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+	}{
+		{name: "empty to text", a: "", b: "a b c"},
+		{name: "text to empty", a: "a b c", b: ""},
+		{name: "equal", a: "a b c", b: "a b c"},
+		{name: "reordered", a: "a b c a b b a", b: "c b a b a c"},
+		{name: "repeated lines", a: "x x x y", b: "y x x x"},
+		{name: "no common lines", a: "a b", b: "c d e"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := strings.Fields(tt.a), strings.Fields(tt.b)
+			ops := diffLines(a, b)
+
+			// Операции должны восстанавливать оба текста
+			var gotA, gotB []string
+			edits := 0
+			for _, op := range ops {
+				if op.kind != diffInsert {
+					gotA = append(gotA, op.line)
+				}
+				if op.kind != diffDelete {
+					gotB = append(gotB, op.line)
+				}
+				if op.kind != diffEqual {
+					edits++
+				}
+			}
+			if strings.Join(gotA, " ") != tt.a || strings.Join(gotB, " ") != tt.b {
+				t.Fatalf("ops rebuild %q -> %q, want %q -> %q", gotA, gotB, tt.a, tt.b)
+			}
+
+			// Кратчайшая последовательность: все, кроме наибольшей общей подпоследовательности
+			if want := len(a) + len(b) - 2*lcsLength(a, b); edits != want {
+				t.Errorf("got %d edits, want %d", edits, want)
+			}
+		})
+	}
+}
+
+// lcsLength длина наибольшей общей подпоследовательности (динамическое программирование)
+func lcsLength(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	for i := range a {
+		cur := make([]int, len(b)+1)
+		for j := range b {
+			if a[i] == b[j] {
+				cur[j+1] = prev[j] + 1
+			} else {
+				cur[j+1] = max(prev[j+1], cur[j])
+			}
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func TestDiffLinesLargeInput(t *testing.T) {
+	lines := func(n int, format string, changed func(i int) bool) []string {
+		out := make([]string, n)
+		for i := range out {
+			if changed(i) {
+				out[i] = fmt.Sprintf(format, i)
+			} else {
+				out[i] = fmt.Sprintf("line %d", i)
+			}
+		}
+		return out
+	}
+	always := func(int) bool { return true }
+	never := func(int) bool { return false }
+
+	tests := []struct {
+		name      string
+		a, b      []string
+		wantEdits int // 0 - кратчайший путь не ожидается, проверяется только корректность
+	}{
+		{
+			name:      "sparse edits in a long note",
+			a:         lines(20000, "old %d", never),
+			b:         lines(20000, "new %d", func(i int) bool { return i%10 == 0 }),
+			wantEdits: 2 * 2000,
+		},
+		{
+			name:      "fully rewritten note within budget",
+			a:         lines(3000, "old %d", always),
+			b:         lines(3000, "new %d", always),
+			wantEdits: 2 * 3000,
+		},
+		{
+			name: "fully rewritten note over budget",
+			a:    lines(20000, "old %d", always),
+			b:    lines(20000, "new %d", always),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops := diffLines(tt.a, tt.b)
+
+			var gotA, gotB []string
+			edits := 0
+			for _, op := range ops {
+				if op.kind != diffInsert {
+					gotA = append(gotA, op.line)
+				}
+				if op.kind != diffDelete {
+					gotB = append(gotB, op.line)
+				}
+				if op.kind != diffEqual {
+					edits++
+				}
+			}
+			if !slices.Equal(gotA, tt.a) || !slices.Equal(gotB, tt.b) {
+				t.Fatal("ops do not rebuild the input texts")
+			}
+			if tt.wantEdits != 0 && edits != tt.wantEdits {
+				t.Errorf("got %d edits, want %d", edits, tt.wantEdits)
+			}
+		})
+	}
+}
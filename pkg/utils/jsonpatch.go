@@ -0,0 +1,336 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrPatchTestFailed операция test в JSON Patch не совпала с документом
+var ErrPatchTestFailed = errors.New("patch test operation failed")
+
+// MergePatch применяет JSON Merge Patch (RFC 7396) к документу doc
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	var target, changes any
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return nil, fmt.Errorf("invalid document: %w", err)
+	}
+	if err := json.Unmarshal(patch, &changes); err != nil {
+		return nil, fmt.Errorf("invalid merge patch: %w", err)
+	}
+
+	return json.Marshal(mergeValue(target, changes))
+}
+
+// mergeValue рекурсивно сливает patch в target: null удаляет поле, объекты сливаются,
+// остальные значения заменяются целиком
+func mergeValue(target, patch any) any {
+	changes, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	object, ok := target.(map[string]any)
+	if !ok {
+		object = make(map[string]any)
+	}
+	for key, value := range changes {
+		if value == nil {
+			delete(object, key)
+		} else {
+			object[key] = mergeValue(object[key], value)
+		}
+	}
+
+	return object
+}
+
+// patchOperation операция JSON Patch
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// ApplyJSONPatch применяет JSON Patch (RFC 6902) к документу doc.
+// Операции применяются по порядку; при ошибке документ не меняется.
+func ApplyJSONPatch(doc, patch []byte) ([]byte, error) {
+	var target any
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return nil, fmt.Errorf("invalid document: %w", err)
+	}
+	var operations []patchOperation
+	if err := json.Unmarshal(patch, &operations); err != nil {
+		return nil, fmt.Errorf("invalid json patch: %w", err)
+	}
+
+	for i, op := range operations {
+		var err error
+		target, err = applyOperation(target, op)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d (%s): %w", i, op.Op, err)
+		}
+	}
+
+	return json.Marshal(target)
+}
+
+// applyOperation применяет одну операцию JSON Patch и возвращает новый корень документа
+func applyOperation(doc any, op patchOperation) (any, error) {
+	if op.Path == nil {
+		return nil, errors.New("missing path")
+	}
+	path, err := parsePointer(*op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, errors.New("missing value")
+		}
+		var value any
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return nil, fmt.Errorf("invalid value: %w", err)
+		}
+
+		switch op.Op {
+		case "add":
+			return pointerAdd(doc, path, value)
+		case "replace":
+			return pointerReplace(doc, path, value)
+		default:
+			current, err := pointerGet(doc, path)
+			if err != nil {
+				return nil, err
+			}
+			if !reflect.DeepEqual(current, value) {
+				return nil, ErrPatchTestFailed
+			}
+			return doc, nil
+		}
+	case "remove":
+		_, doc, err := pointerRemove(doc, path)
+		return doc, err
+	case "move", "copy":
+		if op.From == nil {
+			return nil, errors.New("missing from")
+		}
+		from, err := parsePointer(*op.From)
+		if err != nil {
+			return nil, err
+		}
+
+		var value any
+		if op.Op == "move" {
+			if len(from) < len(path) && reflect.DeepEqual(from, path[:len(from)]) {
+				return nil, errors.New("cannot move a value into itself")
+			}
+			if value, doc, err = pointerRemove(doc, from); err != nil {
+				return nil, err
+			}
+		} else {
+			if value, err = pointerGet(doc, from); err != nil {
+				return nil, err
+			}
+			value = deepCopy(value)
+		}
+		return pointerAdd(doc, path, value)
+	default:
+		return nil, fmt.Errorf("unknown operation %q", op.Op)
+	}
+}
+
+// parsePointer разбирает JSON Pointer (RFC 6901) на токены
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid path %q", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+// arrayIndex разбирает индекс массива; allowEnd разрешает индекс, равный длине массива
+func arrayIndex(token string, length int, allowEnd bool) (int, error) {
+	if allowEnd && token == "-" {
+		return length, nil
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || index > length || (!allowEnd && index == length) ||
+		(len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	return index, nil
+}
+
+// pointerGet возвращает значение по пути
+func pointerGet(doc any, path []string) (any, error) {
+	for _, token := range path {
+		switch container := doc.(type) {
+		case map[string]any:
+			value, exists := container[token]
+			if !exists {
+				return nil, fmt.Errorf("path member %q not found", token)
+			}
+			doc = value
+		case []any:
+			index, err := arrayIndex(token, len(container), false)
+			if err != nil {
+				return nil, err
+			}
+			doc = container[index]
+		default:
+			return nil, fmt.Errorf("path member %q not found", token)
+		}
+	}
+	return doc, nil
+}
+
+// pointerUpdate заменяет значение родителя последнего токена результатом fn
+// и возвращает новый корень документа
+func pointerUpdate(doc any, path []string, fn func(parent any, key string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+
+	switch container := doc.(type) {
+	case map[string]any:
+		child, exists := container[path[0]]
+		if !exists {
+			return nil, fmt.Errorf("path member %q not found", path[0])
+		}
+		updated, err := pointerUpdate(child, path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		container[path[0]] = updated
+		return container, nil
+	case []any:
+		index, err := arrayIndex(path[0], len(container), false)
+		if err != nil {
+			return nil, err
+		}
+		updated, err := pointerUpdate(container[index], path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		container[index] = updated
+		return container, nil
+	default:
+		return nil, fmt.Errorf("path member %q not found", path[0])
+	}
+}
+
+// pointerAdd добавляет значение по пути (в массив - со сдвигом элементов)
+func pointerAdd(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return pointerUpdate(doc, path, func(parent any, key string) (any, error) {
+		switch container := parent.(type) {
+		case map[string]any:
+			container[key] = value
+			return container, nil
+		case []any:
+			index, err := arrayIndex(key, len(container), true)
+			if err != nil {
+				return nil, err
+			}
+			container = append(container, nil)
+			copy(container[index+1:], container[index:])
+			container[index] = value
+			return container, nil
+		default:
+			return nil, fmt.Errorf("cannot add member %q to a scalar value", key)
+		}
+	})
+}
+
+// pointerReplace заменяет существующее значение по пути
+func pointerReplace(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return pointerUpdate(doc, path, func(parent any, key string) (any, error) {
+		switch container := parent.(type) {
+		case map[string]any:
+			if _, exists := container[key]; !exists {
+				return nil, fmt.Errorf("path member %q not found", key)
+			}
+			container[key] = value
+			return container, nil
+		case []any:
+			index, err := arrayIndex(key, len(container), false)
+			if err != nil {
+				return nil, err
+			}
+			container[index] = value
+			return container, nil
+		default:
+			return nil, fmt.Errorf("path member %q not found", key)
+		}
+	})
+}
+
+// pointerRemove удаляет значение по пути и возвращает его вместе с новым корнем документа
+func pointerRemove(doc any, path []string) (any, any, error) {
+	if len(path) == 0 {
+		return nil, nil, errors.New("cannot remove the whole document")
+	}
+
+	var removed any
+	doc, err := pointerUpdate(doc, path, func(parent any, key string) (any, error) {
+		switch container := parent.(type) {
+		case map[string]any:
+			value, exists := container[key]
+			if !exists {
+				return nil, fmt.Errorf("path member %q not found", key)
+			}
+			removed = value
+			delete(container, key)
+			return container, nil
+		case []any:
+			index, err := arrayIndex(key, len(container), false)
+			if err != nil {
+				return nil, err
+			}
+			removed = container[index]
+			return append(container[:index], container[index+1:]...), nil
+		default:
+			return nil, fmt.Errorf("path member %q not found", key)
+		}
+	})
+	return removed, doc, err
+}
+
+// deepCopy копирует значение JSON документа
+func deepCopy(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		copied := make(map[string]any, len(v))
+		for key, item := range v {
+			copied[key] = deepCopy(item)
+		}
+		return copied
+	case []any:
+		copied := make([]any, len(v))
+		for i, item := range v {
+			copied[i] = deepCopy(item)
+		}
+		return copied
+	default:
+		return v
+	}
+}
@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// assertJSONEqual сравнивает JSON документы без учета форматирования и порядка ключей
+func assertJSONEqual(t *testing.T, got []byte, want string) {
+	t.Helper()
+	var gotValue, wantValue any
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatalf("result is not valid JSON: %v (%s)", err, got)
+	}
+	if err := json.Unmarshal([]byte(want), &wantValue); err != nil {
+		t.Fatalf("expected value is not valid JSON: %v", err)
+	}
+	if !reflect.DeepEqual(gotValue, wantValue) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	// Примеры из приложения A RFC 7396
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{name: "replace member", doc: `{"a":"b"}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{name: "add member", doc: `{"a":"b"}`, patch: `{"b":"c"}`, want: `{"a":"b","b":"c"}`},
+		{name: "null removes member", doc: `{"a":"b"}`, patch: `{"a":null}`, want: `{}`},
+		{name: "remove one of several", doc: `{"a":"b","b":"c"}`, patch: `{"a":null}`, want: `{"b":"c"}`},
+		{name: "array replaced", doc: `{"a":["b"]}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{name: "value replaced by array", doc: `{"a":"c"}`, patch: `{"a":["b"]}`, want: `{"a":["b"]}`},
+		{name: "nested merge", doc: `{"a":{"b":"c"}}`, patch: `{"a":{"b":"d","c":null}}`, want: `{"a":{"b":"d"}}`},
+		{name: "arrays not merged", doc: `{"a":[{"b":"c"}]}`, patch: `{"a":[1]}`, want: `{"a":[1]}`},
+		{name: "non-object patch replaces document", doc: `{"a":"foo"}`, patch: `"bar"`, want: `"bar"`},
+		{name: "null in new object dropped", doc: `{"e":null}`, patch: `{"a":1}`, want: `{"e":null,"a":1}`},
+		{name: "object created for scalar", doc: `[1,2]`, patch: `{"a":"b","c":null}`, want: `{"a":"b"}`},
+		{name: "deep null dropped", doc: `{}`, patch: `{"a":{"bb":{"ccc":null}}}`, want: `{"a":{"bb":{}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MergePatch([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("MergePatch() error = %v", err)
+			}
+			assertJSONEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestMergePatchInvalidJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+	}{
+		{name: "invalid document", doc: `{`, patch: `{}`},
+		{name: "invalid patch", doc: `{}`, patch: `{"a":`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := MergePatch([]byte(tt.doc), []byte(tt.patch)); err == nil {
+				t.Error("MergePatch() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	// Большая часть примеров из приложения A RFC 6902
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{name: "add object member", doc: `{"foo":"bar"}`, patch: `[{"op":"add","path":"/baz","value":"qux"}]`, want: `{"baz":"qux","foo":"bar"}`},
+		{name: "add array element", doc: `{"foo":["bar","baz"]}`, patch: `[{"op":"add","path":"/foo/1","value":"qux"}]`, want: `{"foo":["bar","qux","baz"]}`},
+		{name: "append to array", doc: `{"foo":["bar"]}`, patch: `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, want: `{"foo":["bar",["abc","def"]]}`},
+		{name: "add replaces existing member", doc: `{"foo":"bar"}`, patch: `[{"op":"add","path":"/foo","value":1}]`, want: `{"foo":1}`},
+		{name: "add whole document", doc: `{"foo":"bar"}`, patch: `[{"op":"add","path":"","value":[1]}]`, want: `[1]`},
+		{name: "remove object member", doc: `{"baz":"qux","foo":"bar"}`, patch: `[{"op":"remove","path":"/baz"}]`, want: `{"foo":"bar"}`},
+		{name: "remove array element", doc: `{"foo":["bar","qux","baz"]}`, patch: `[{"op":"remove","path":"/foo/1"}]`, want: `{"foo":["bar","baz"]}`},
+		{name: "replace value", doc: `{"baz":"qux","foo":"bar"}`, patch: `[{"op":"replace","path":"/baz","value":"boo"}]`, want: `{"baz":"boo","foo":"bar"}`},
+		{name: "move value", doc: `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, patch: `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`, want: `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{name: "move array element", doc: `{"foo":["all","grass","cows","eat"]}`, patch: `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, want: `{"foo":["all","cows","eat","grass"]}`},
+		{name: "copy value", doc: `{"a":{"b":[1]}}`, patch: `[{"op":"copy","from":"/a","path":"/c"},{"op":"add","path":"/c/b/-","value":2}]`, want: `{"a":{"b":[1]},"c":{"b":[1,2]}}`},
+		{name: "test passes", doc: `{"baz":"qux","foo":["a",2,"c"]}`, patch: `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`, want: `{"baz":"qux","foo":["a",2,"c"]}`},
+		{name: "escaped pointer", doc: `{"/":9,"~1":10}`, patch: `[{"op":"replace","path":"/~01","value":11},{"op":"remove","path":"/~1"}]`, want: `{"~1":11}`},
+		{name: "operations in order", doc: `{"tags":["a"]}`, patch: `[{"op":"add","path":"/tags/-","value":"b"},{"op":"remove","path":"/tags/0"}]`, want: `{"tags":["b"]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyJSONPatch([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("ApplyJSONPatch() error = %v", err)
+			}
+			assertJSONEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestApplyJSONPatchErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		doc        string
+		patch      string
+		testFailed bool // Ошибка должна быть ErrPatchTestFailed
+	}{
+		{name: "invalid document", doc: `{`, patch: `[]`},
+		{name: "patch is not an array", doc: `{}`, patch: `{"op":"add"}`},
+		{name: "unknown operation", doc: `{}`, patch: `[{"op":"frobnicate","path":"/a"}]`},
+		{name: "missing path", doc: `{}`, patch: `[{"op":"add","value":1}]`},
+		{name: "missing value", doc: `{}`, patch: `[{"op":"add","path":"/a"}]`},
+		{name: "missing from", doc: `{"a":1}`, patch: `[{"op":"move","path":"/b"}]`},
+		{name: "path without slash", doc: `{}`, patch: `[{"op":"add","path":"a","value":1}]`},
+		{name: "add to missing parent", doc: `{}`, patch: `[{"op":"add","path":"/a/b","value":1}]`},
+		{name: "replace missing member", doc: `{}`, patch: `[{"op":"replace","path":"/a","value":1}]`},
+		{name: "remove missing member", doc: `{}`, patch: `[{"op":"remove","path":"/a"}]`},
+		{name: "remove whole document", doc: `{}`, patch: `[{"op":"remove","path":""}]`},
+		{name: "index out of range", doc: `{"a":[1]}`, patch: `[{"op":"add","path":"/a/2","value":1}]`},
+		{name: "index with leading zero", doc: `{"a":[1,2]}`, patch: `[{"op":"replace","path":"/a/01","value":1}]`},
+		{name: "end index outside add", doc: `{"a":[1]}`, patch: `[{"op":"remove","path":"/a/-"}]`},
+		{name: "move into itself", doc: `{"a":{"b":1}}`, patch: `[{"op":"move","from":"/a","path":"/a/c"}]`},
+		{name: "test value differs", doc: `{"a":"b"}`, patch: `[{"op":"test","path":"/a","value":"c"}]`, testFailed: true},
+		{name: "test number as string", doc: `{"a":1}`, patch: `[{"op":"test","path":"/a","value":"1"}]`, testFailed: true},
+		{name: "failed operation cancels patch", doc: `{"a":1}`, patch: `[{"op":"add","path":"/b","value":2},{"op":"test","path":"/a","value":2}]`, testFailed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyJSONPatch([]byte(tt.doc), []byte(tt.patch))
+			if err == nil {
+				t.Fatalf("ApplyJSONPatch() = %s, want error", got)
+			}
+			if got != nil {
+				t.Errorf("ApplyJSONPatch() returned document %s with error", got)
+			}
+			if tt.testFailed != errors.Is(err, ErrPatchTestFailed) {
+				t.Errorf("ApplyJSONPatch() error = %v, want ErrPatchTestFailed: %v", err, tt.testFailed)
+			}
+		})
+	}
+}